@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/m96-chan/Slacko/internal/config"
+)
+
+// configUsage is printed for "slacko config" without a valid action.
+const configUsage = `Usage:
+  slacko config check            report unknown keys, bad key names, binding conflicts and theme errors
+  slacko config print-defaults   print the built-in default config.toml
+  slacko config diff             show settings that differ from the defaults
+`
+
+// runConfig implements "slacko config": inspects the config file given by
+// --config-path.
+func runConfig(args []string, configPath string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, configUsage)
+		return errUsage
+	}
+
+	action, rest := args[0], args[1:]
+	if len(rest) > 0 {
+		fmt.Fprint(os.Stderr, configUsage)
+		return errUsage
+	}
+	switch action {
+	case "check":
+		return configCheck(os.Stdout, configPath)
+	case "print-defaults", "defaults":
+		_, err := os.Stdout.Write(config.Defaults())
+		return err
+	case "diff":
+		return configDiff(os.Stdout, configPath)
+	default:
+		fmt.Fprint(os.Stderr, configUsage)
+		return fmt.Errorf("unknown config action %q", action)
+	}
+}
+
+// configCheck prints every problem found in the config file and fails if
+// there are any, so it can be used in scripts.
+func configCheck(w io.Writer, path string) error {
+	problems, err := config.Check(path)
+	if err != nil {
+		return err
+	}
+	if len(problems) == 0 {
+		fmt.Fprintf(w, "%s: ok\n", path)
+		return nil
+	}
+	for _, p := range problems {
+		fmt.Fprintf(w, "%s: %s\n", path, p)
+	}
+	return fmt.Errorf("%d problem(s) found", len(problems))
+}
+
+// configDiff prints each overridden setting as a TOML assignment annotated
+// with its default value.
+func configDiff(w io.Writer, path string) error {
+	overrides, err := config.Diff(path)
+	if err != nil {
+		return err
+	}
+	for _, o := range overrides {
+		def := "not a default setting"
+		if o.HasDefault {
+			def = "default: " + config.FormatValue(o.Default)
+		}
+		fmt.Fprintf(w, "%s = %s  # %s\n", o.Key, config.FormatValue(o.Value), def)
+	}
+	return nil
+}
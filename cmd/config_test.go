@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigCheckAndDiff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("messages_limit = 20\nfoo = true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := configCheck(&out, path); err == nil {
+		t.Error("check should fail for an unknown key")
+	}
+	if !strings.Contains(out.String(), "foo: unknown key") {
+		t.Errorf("check output = %q", out.String())
+	}
+
+	out.Reset()
+	if err := configDiff(&out, path); err != nil {
+		t.Fatal(err)
+	}
+	want := "messages_limit = 20  # default: 50\nfoo = true  # not a default setting\n"
+	if out.String() != want {
+		t.Errorf("diff output = %q, want %q", out.String(), want)
+	}
+}
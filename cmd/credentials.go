@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+
+	"github.com/m96-chan/Slacko/internal/config"
+	"github.com/m96-chan/Slacko/internal/keyring"
+)
+
+// useCredentials selects the credential backend configured in cfg.
+func useCredentials(cfg *config.Config) error {
+	s, err := keyring.NewStore(cfg.Credentials.Backend, credentialOptions(cfg.Credentials.Command))
+	if err != nil {
+		return err
+	}
+	keyring.Use(s)
+	return nil
+}
+
+// credentialOptions returns the options for keyring.NewStore.
+func credentialOptions(command string) keyring.Options {
+	return keyring.Options{Command: command, Passphrase: readPassphrase}
+}
+
+// readPassphrase returns the passphrase of the encrypted credentials file
+// from SLACKO_PASSPHRASE, or asks for it on the terminal. A new passphrase
+// is asked for twice.
+func readPassphrase(confirm bool) ([]byte, error) {
+	if v := os.Getenv("SLACKO_PASSPHRASE"); v != "" {
+		return []byte(v), nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, errors.New("encrypted credentials need a passphrase: set SLACKO_PASSPHRASE")
+	}
+
+	prompt := "Passphrase for " + keyring.FilePath() + ": "
+	if confirm {
+		prompt = "New passphrase for " + keyring.FilePath() + ": "
+	}
+	pass, err := promptPassword(fd, prompt)
+	if err != nil {
+		return nil, err
+	}
+	if confirm {
+		again, err := promptPassword(fd, "Repeat passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+		if string(again) != string(pass) {
+			return nil, errors.New("passphrases do not match")
+		}
+	}
+	return pass, nil
+}
+
+// promptPassword prints prompt to stderr and reads a line without echo.
+func promptPassword(fd int, prompt string) ([]byte, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	pass, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	return pass, err
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/slack-go/slack"
+
+	"github.com/m96-chan/Slacko/internal/export"
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
+)
+
+// runExport implements "slacko export": writes the history of a channel,
+// DM or thread to JSON, Markdown or HTML.
+func runExport(args []string, configPath string) error {
+	fs := newFlagSet("export")
+	workspace := addWorkspaceFlag(fs)
+	formatName := fs.String("format", "markdown", "output format: json, markdown or html")
+	output := fs.String("output", "", "output file (directory for json); default <name>-<date>.<ext> in the current directory")
+	since := fs.String("since", "", "only messages on or after this date (YYYY-MM-DD or RFC 3339)")
+	until := fs.String("until", "", "only messages on or before this date (YYYY-MM-DD or RFC 3339)")
+	thread := fs.String("thread", "", "export only the thread with this parent ts")
+	withFiles := fs.Bool("files", false, "download attached files next to the export")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	r, err := export.ParseRange(*since, *until)
+	if err != nil {
+		return err
+	}
+
+	client, err := connect(*workspace, configPath)
+	if err != nil {
+		return err
+	}
+
+	// A permalink selects the conversation and, for thread messages, the thread.
+	ref, threadTS := fs.Arg(0), *thread
+	var channelID string
+	if p, err := slackclient.ParsePermalink(ref); err == nil {
+		channelID = p.ChannelID
+		if threadTS == "" {
+			threadTS = p.Timestamp
+			if p.ThreadTS != "" {
+				threadTS = p.ThreadTS
+			}
+		}
+	} else if channelID, err = client.ResolveConversation(ref); err != nil {
+		return err
+	}
+
+	names := exportNames(client)
+	conv, err := export.Collect(client, channelID, threadTS, r)
+	if err != nil {
+		return err
+	}
+	conv.Name = conversationName(client, channelID, names)
+
+	path := *output
+	if path == "" {
+		path = export.DefaultPath(".", conv.Name, format, time.Now())
+	}
+	var dl export.Downloader
+	if *withFiles {
+		dl = client
+	}
+	path, err = export.Write(conv, format, path, names, dl)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported to %s\n", path)
+	return nil
+}
+
+// exportNames builds the user and channel lookups used to resolve mentions.
+func exportNames(client *slackclient.Client) export.Names {
+	names := export.Names{
+		Users:    make(map[string]slack.User),
+		Channels: make(map[string]string),
+	}
+	if users, err := client.GetUsers(); err == nil {
+		for _, u := range users {
+			names.Users[u.ID] = u
+		}
+	}
+	if channels, err := client.GetAllConversations("public_channel", "private_channel", "mpim"); err == nil {
+		for _, ch := range channels {
+			names.Channels[ch.ID] = ch.Name
+		}
+	}
+	return names
+}
+
+// conversationName returns the channel name, or the partner's handle for a DM.
+func conversationName(client *slackclient.Client, channelID string, names export.Names) string {
+	if name, ok := names.Channels[channelID]; ok {
+		return name
+	}
+	info, err := client.GetConversationInfo(channelID)
+	if err != nil {
+		return channelID
+	}
+	if info.IsIM {
+		if u, ok := names.Users[info.User]; ok {
+			return u.Name
+		}
+		return info.User
+	}
+	return info.Name
+}
@@ -8,7 +8,9 @@ import (
 
 	"github.com/m96-chan/Slacko/internal/app"
 	"github.com/m96-chan/Slacko/internal/config"
+	"github.com/m96-chan/Slacko/internal/keyring"
 	"github.com/m96-chan/Slacko/internal/logger"
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
 )
 
 var (
@@ -17,12 +19,15 @@ var (
 	Date    = "unknown"
 )
 
-// Run parses CLI flags, sets up logging and config, and starts the app.
+// Run parses CLI flags, sets up logging and config, and starts the app or
+// runs a non-interactive subcommand.
 func Run() error {
 	showVersion := flag.Bool("version", false, "print version and exit")
 	configPath := flag.String("config-path", config.DefaultPath(), "path to config file")
 	logPath := flag.String("log-path", logger.DefaultPath(), "path to log file")
 	logLevel := flag.String("log-level", "info", "log level (debug, info, warn, error)")
+	openLink := flag.String("open", "", "Slack permalink to open on startup")
+	flag.Usage = usage
 	flag.Parse()
 
 	if *showVersion {
@@ -35,14 +40,32 @@ func Run() error {
 		return err
 	}
 
+	if flag.NArg() > 0 {
+		return runSubcommand(flag.Arg(0), flag.Args()[1:], *configPath)
+	}
+
 	slog.Info("starting slacko", "config", *configPath, "log", *logPath)
 
 	cfg, err := config.Load(*configPath)
 	if err != nil {
 		return err
 	}
+	if err := useCredentials(cfg); err != nil {
+		return err
+	}
+	// Ask for the credentials passphrase before the TUI takes the terminal.
+	if err := keyring.Unlock(); err != nil {
+		return err
+	}
 
-	return app.New(cfg).Run()
+	a := app.New(cfg)
+	if *openLink != "" {
+		if !slackclient.IsPermalink(*openLink) {
+			return fmt.Errorf("--open: not a Slack message permalink: %s", *openLink)
+		}
+		a.OpenOnStart(*openLink)
+	}
+	return a.Run()
 }
 
 func parseLevel(s string) slog.Level {
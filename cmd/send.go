@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/slack-go/slack"
+
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
+)
+
+// runSend implements "slacko send": posts text (from the arguments or stdin)
+// and/or uploads a file to a conversation, optionally in a thread.
+func runSend(args []string, configPath string) error {
+	fs := newFlagSet("send")
+	workspace := addWorkspaceFlag(fs)
+	thread := fs.String("thread", "", "reply in the thread with this parent ts (or a message permalink)")
+	file := fs.String("file", "", "upload this file; the text becomes its comment")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	text := strings.Join(fs.Args()[1:], " ")
+	if text == "" && *file == "" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("read stdin: %w", err)
+		}
+		text = strings.TrimRight(string(data), "\n")
+	}
+	if text == "" && *file == "" {
+		return fmt.Errorf("nothing to send")
+	}
+
+	threadTS := *thread
+	if slackclient.IsPermalink(threadTS) {
+		p, _ := slackclient.ParsePermalink(threadTS)
+		threadTS = p.Timestamp
+		if p.ThreadTS != "" {
+			threadTS = p.ThreadTS
+		}
+	}
+
+	client, err := connect(*workspace, configPath)
+	if err != nil {
+		return err
+	}
+	channelID, err := client.ResolveConversation(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *file != "" {
+		_, err := client.UploadFile(slack.UploadFileParameters{
+			File:            *file,
+			Filename:        filepath.Base(*file),
+			InitialComment:  text,
+			Channel:         channelID,
+			ThreadTimestamp: threadTS,
+		})
+		if err != nil {
+			return fmt.Errorf("upload %s: %w", *file, err)
+		}
+		return nil
+	}
+
+	opts := []slack.MsgOption{slack.MsgOptionText(text, false)}
+	if threadTS != "" {
+		opts = append(opts, slack.MsgOptionTS(threadTS))
+	}
+	_, ts, err := client.PostMessage(channelID, opts...)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ts)
+	return nil
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/m96-chan/Slacko/internal/status"
+)
+
+// defaultStatusFormat is the output of "slacko status" without --format.
+const defaultStatusFormat = "{mentions}/{unread}"
+
+// runStatus implements "slacko status": prints the unread and mention
+// counts published by running Slacko instances, for status bars to poll.
+// It never contacts Slack; with no instance running it reports zero counts
+// and the "offline" state.
+func runStatus(args []string, _ string) error {
+	fs := newFlagSet("status")
+	format := fs.String("format", defaultStatusFormat, "output format; placeholders: {mentions} {unread} {state} {team} {team_id}")
+	workspace := fs.String("workspace", "", "only count this workspace (name or team ID)")
+	asJSON := fs.Bool("json", false, "print the per-workspace summaries as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	summaries, err := status.ReadAll()
+	if err != nil {
+		return err
+	}
+	if *workspace != "" {
+		summaries = filterSummaries(summaries, *workspace)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if summaries == nil {
+			summaries = []status.Summary{}
+		}
+		return enc.Encode(summaries)
+	}
+
+	fmt.Println(status.Format(*format, status.Total(summaries)))
+	return nil
+}
+
+// filterSummaries returns the summaries whose team name (case-insensitive)
+// or team ID is workspace.
+func filterSummaries(summaries []status.Summary, workspace string) []status.Summary {
+	var out []status.Summary
+	for _, s := range summaries {
+		if s.TeamID == workspace || strings.EqualFold(s.Team, workspace) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
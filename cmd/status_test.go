@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/m96-chan/Slacko/internal/status"
+)
+
+// captureStdout returns what fn writes to os.Stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+func TestRunStatus(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	out := captureStdout(t, func() {
+		if err := runStatus(nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if out != "0/0\n" {
+		t.Errorf("output with no instances = %q, want 0/0", out)
+	}
+
+	for _, s := range []status.Summary{
+		{TeamID: "T1", Team: "Home", State: status.StateConnected, Unread: 2, Mentions: 1},
+		{TeamID: "T2", Team: "Work", State: status.StateConnected, Unread: 7, Mentions: 3},
+	} {
+		if err := status.Write(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out = captureStdout(t, func() {
+		if err := runStatus([]string{"--format", "{mentions}/{unread} {state}"}, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if out != "4/9 connected\n" {
+		t.Errorf("output = %q, want totals", out)
+	}
+
+	out = captureStdout(t, func() {
+		if err := runStatus([]string{"--workspace", "work", "--format", "{team}:{unread}"}, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if strings.TrimSpace(out) != "Work:7" {
+		t.Errorf("output for one workspace = %q, want Work:7", out)
+	}
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/m96-chan/Slacko/internal/config"
+	"github.com/m96-chan/Slacko/internal/keyring"
+	"github.com/m96-chan/Slacko/internal/oauth"
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
+)
+
+// subcommand is a non-interactive command run instead of the TUI, e.g.
+// "slacko send #general hello".
+type subcommand struct {
+	name    string
+	args    string // argument synopsis shown in usage
+	summary string
+	run     func(args []string, configPath string) error
+	// tokens is set for subcommands that read stored tokens. Only they set
+	// up the credential backend, which may probe the keyring or run the
+	// credentials command.
+	tokens bool
+}
+
+// subcommands returns the available subcommands in the order they are listed
+// in usage output.
+func subcommands() []subcommand {
+	return []subcommand{
+		{"send", "[flags] <#channel|@user|ID> [text]", "Send a message (reads stdin when text is omitted)", runSend, true},
+		{"tail", "[flags] [#channel ...]", "Stream incoming messages", runTail, true},
+		{"unread", "[flags]", "Print unread message counts", runUnread, true},
+		{"status", "[flags]", "Print unread and mention counts of running instances", runStatus, false},
+		{"export", "[flags] <#channel|@user|ID|permalink>", "Export conversation history to JSON, Markdown or HTML", runExport, true},
+		{"workspace", "list|add|remove|rename|default|migrate ...", "Manage signed-in workspaces", runWorkspace, true},
+		{"config", "check|print-defaults|diff", "Validate and inspect the config file", runConfig, false},
+	}
+}
+
+// errUsage is returned by subcommands for invalid arguments; the flag set's
+// usage has already been printed.
+var errUsage = errors.New("invalid arguments")
+
+// runSubcommand dispatches to the named subcommand. Errors are printed to
+// stderr as well as returned, since the log goes to a file.
+func runSubcommand(name string, args []string, configPath string) error {
+	for _, sc := range subcommands() {
+		if sc.name != name {
+			continue
+		}
+		slog.Info("running subcommand", "name", name)
+		// An invalid config is reported by the subcommands that need it.
+		if sc.tokens {
+			if cfg, err := config.Load(configPath); err == nil {
+				if err := useCredentials(cfg); err != nil {
+					slog.Warn("credential backend unavailable", "error", err)
+				}
+			}
+		}
+		err := sc.run(args, configPath)
+		if err != nil && !errors.Is(err, errUsage) && !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "slacko %s: %v\n", name, err)
+		}
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	usage()
+	return fmt.Errorf("unknown command %q", name)
+}
+
+// usage prints the top-level help including the subcommand list.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage:\n  slacko [flags]                 start the TUI\n  slacko [flags] <command> ...   run a command\n\nCommands:\n")
+	for _, sc := range subcommands() {
+		fmt.Fprintf(out, "  %-10s %s\n", sc.name, sc.summary)
+	}
+	fmt.Fprintf(out, "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+// newFlagSet creates a flag set for a subcommand whose usage lists its
+// synopsis. Parse errors are returned rather than exiting.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("slacko "+name, flag.ContinueOnError)
+	fs.Usage = func() {
+		for _, sc := range subcommands() {
+			if sc.name == name {
+				fmt.Fprintf(fs.Output(), "Usage: slacko %s %s\n\n%s.\n\nFlags:\n", sc.name, sc.args, sc.summary)
+			}
+		}
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// addWorkspaceFlag registers the --workspace flag shared by subcommands that
+// talk to Slack.
+func addWorkspaceFlag(fs *flag.FlagSet) *string {
+	return fs.String("workspace", "", "workspace name or team ID from the registry (default: last signed-in workspace)")
+}
+
+// refreshParams returns the OAuth settings used to refresh rotating tokens.
+// Without a readable config they are empty, and only tokens that don't need
+// a refresh can be used.
+func refreshParams(configPath string) oauth.Params {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return oauth.Params{}
+	}
+	return oauth.Params{
+		ClientID:     cfg.OAuth.ClientID,
+		ClientSecret: cfg.OAuth.ClientSecret,
+		ProxyURL:     cfg.OAuth.ProxyURL,
+	}
+}
+
+// connect creates a Slack client using stored credentials. With an empty
+// workspace it uses the same tokens the TUI starts with; otherwise the
+// workspace is looked up in the registry by name or team ID. Rotating tokens
+// that are about to expire are refreshed with the OAuth settings from the
+// config file.
+func connect(workspace, configPath string) (*slackclient.Client, error) {
+	if workspace == "" {
+		workspace = keyring.DefaultWorkspaceID()
+	}
+
+	var userToken, appToken string
+	if workspace == "" {
+		var err error
+		if userToken, err = keyring.GetUserToken(); err != nil {
+			return nil, fmt.Errorf("no stored user token (run slacko to sign in): %w", err)
+		}
+		if appToken, err = keyring.GetAppToken(); err != nil {
+			return nil, fmt.Errorf("no stored app token (run slacko to sign in): %w", err)
+		}
+	} else {
+		w, ok := keyring.FindWorkspace(workspace)
+		if !ok {
+			return nil, fmt.Errorf("workspace not found: %s", workspace)
+		}
+		tokens, err := oauth.FreshTokens(context.Background(), refreshParams(configPath), w)
+		if err != nil {
+			return nil, fmt.Errorf("tokens for %s: %w", w.Name, err)
+		}
+		userToken, appToken = tokens.UserToken, tokens.AppToken
+	}
+
+	client, err := slackclient.New(userToken, appToken)
+	if err != nil {
+		return nil, fmt.Errorf("authenticate: %w", err)
+	}
+	return client, nil
+}
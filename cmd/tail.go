@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/slack-go/slack/slackevents"
+
+	"github.com/m96-chan/Slacko/internal/config"
+	"github.com/m96-chan/Slacko/internal/rpc"
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
+)
+
+// tailMessage is the JSON form of a message printed by "slacko tail --json".
+type tailMessage struct {
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name,omitempty"`
+	UserID      string `json:"user_id,omitempty"`
+	UserName    string `json:"user_name,omitempty"`
+	Timestamp   string `json:"ts"`
+	ThreadTS    string `json:"thread_ts,omitempty"`
+	Text        string `json:"text"`
+}
+
+// runTail implements "slacko tail": streams new messages until interrupted.
+// When slacko is running for the workspace, messages are read from its
+// control socket: Slack spreads events across all Socket Mode connections of
+// an app token, so a second connection would take messages away from the
+// running instance. Otherwise tail opens its own Socket Mode connection.
+func runTail(args []string, configPath string) error {
+	fs := newFlagSet("tail")
+	workspace := addWorkspaceFlag(fs)
+	asJSON := fs.Bool("json", false, "print one JSON object per line")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := connect(*workspace, configPath)
+	if err != nil {
+		return err
+	}
+
+	filter := make(map[string]bool)
+	for _, ref := range fs.Args() {
+		id, err := client.ResolveConversation(ref)
+		if err != nil {
+			return err
+		}
+		filter[id] = true
+	}
+
+	channelNames := make(map[string]string)
+	if channels, err := client.GetAllConversations("public_channel", "private_channel", "mpim", "im"); err == nil {
+		for _, ch := range channels {
+			channelNames[ch.ID] = ch.Name
+		}
+	}
+	userNames := fetchUserNames(client)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	onMessage := func(evt *slackevents.MessageEvent) {
+		if len(filter) > 0 && !filter[evt.Channel] {
+			return
+		}
+		msg := tailMessage{
+			ChannelID:   evt.Channel,
+			ChannelName: channelNames[evt.Channel],
+			UserID:      evt.User,
+			UserName:    userNames[evt.User],
+			Timestamp:   evt.TimeStamp,
+			ThreadTS:    evt.ThreadTimeStamp,
+			Text:        evt.Text,
+		}
+		if msg.UserName == "" {
+			msg.UserName = evt.Username
+		}
+		if err := writeTailMessage(os.Stdout, msg, *asJSON); err != nil {
+			stop()
+		}
+	}
+
+	if conn, err := rpc.Dial(rpc.SocketPath(client.TeamID)); err == nil {
+		fmt.Fprintln(os.Stderr, "reading from the running slacko instance, waiting for messages (Ctrl+C to stop)")
+		err := conn.Subscribe(ctx, []string{config.HookMessage}, func(params json.RawMessage) {
+			if evt, ok := decodeTailEvent(params); ok {
+				onMessage(evt)
+			}
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	handler := &slackclient.EventHandler{
+		OnMessage: onMessage,
+		OnConnected: func() {
+			fmt.Fprintln(os.Stderr, "connected, waiting for messages (Ctrl+C to stop)")
+		},
+		OnError: func(err error) {
+			fmt.Fprintf(os.Stderr, "socket mode error: %v\n", err)
+		},
+	}
+
+	err = client.RunSocketMode(ctx, handler)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// decodeTailEvent extracts the message event from the params of a control
+// socket "message" event.
+func decodeTailEvent(params json.RawMessage) (*slackevents.MessageEvent, bool) {
+	var evt struct {
+		Data *slackevents.MessageEvent `json:"event"`
+	}
+	if err := json.Unmarshal(params, &evt); err != nil || evt.Data == nil {
+		return nil, false
+	}
+	return evt.Data, true
+}
+
+// writeTailMessage prints msg as a JSON line or as
+// "2006-01-02 15:04:05 #channel user: text".
+func writeTailMessage(w io.Writer, msg tailMessage, asJSON bool) error {
+	if asJSON {
+		return json.NewEncoder(w).Encode(msg)
+	}
+
+	when := msg.Timestamp
+	sec, _, _ := strings.Cut(msg.Timestamp, ".")
+	if n, err := strconv.ParseInt(sec, 10, 64); err == nil {
+		when = time.Unix(n, 0).Format("2006-01-02 15:04:05")
+	}
+	channel := msg.ChannelID
+	if msg.ChannelName != "" {
+		channel = "#" + msg.ChannelName
+	}
+	user := msg.UserName
+	if user == "" {
+		user = msg.UserID
+	}
+	thread := ""
+	if msg.ThreadTS != "" && msg.ThreadTS != msg.Timestamp {
+		thread = " (thread " + msg.ThreadTS + ")"
+	}
+
+	_, err := fmt.Fprintf(w, "%s %s%s %s: %s\n", when, channel, thread, user, msg.Text)
+	return err
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack/slackevents"
+
+	"github.com/m96-chan/Slacko/internal/hooks"
+)
+
+func TestWriteTailMessageText(t *testing.T) {
+	var buf bytes.Buffer
+	msg := tailMessage{
+		ChannelID:   "C1",
+		ChannelName: "general",
+		UserID:      "U1",
+		UserName:    "alice",
+		Timestamp:   "1700000000.000100",
+		ThreadTS:    "1699999999.000100",
+		Text:        "hello",
+	}
+	if err := writeTailMessage(&buf, msg, false); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{"#general", "(thread 1699999999.000100)", "alice: hello"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q missing %q", got, want)
+		}
+	}
+}
+
+func TestWriteTailMessageJSON(t *testing.T) {
+	var buf bytes.Buffer
+	msg := tailMessage{ChannelID: "C1", UserID: "U1", Timestamp: "1.2", Text: "hi"}
+	if err := writeTailMessage(&buf, msg, true); err != nil {
+		t.Fatal(err)
+	}
+	var decoded tailMessage
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if decoded != msg {
+		t.Errorf("decoded = %+v, want %+v", decoded, msg)
+	}
+}
+
+func TestRunSubcommandUnknown(t *testing.T) {
+	if err := runSubcommand("nope", nil, ""); err == nil {
+		t.Error("unknown subcommand should fail")
+	}
+}
+
+func TestDecodeTailEvent(t *testing.T) {
+	params, err := json.Marshal(hooks.Event{
+		Type:   "message",
+		TeamID: "T1",
+		Data:   &slackevents.MessageEvent{Channel: "C1", User: "U1", TimeStamp: "1.2", Text: "hi"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	evt, ok := decodeTailEvent(params)
+	if !ok {
+		t.Fatalf("decodeTailEvent(%s) failed", params)
+	}
+	if evt.Channel != "C1" || evt.User != "U1" || evt.TimeStamp != "1.2" || evt.Text != "hi" {
+		t.Errorf("decoded = %+v", evt)
+	}
+
+	if _, ok := decodeTailEvent(json.RawMessage(`{"type":"message"}`)); ok {
+		t.Error("decodeTailEvent accepted an event without payload")
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+
+	"github.com/slack-go/slack"
+
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
+)
+
+// unreadEntry is one conversation with unread messages, as printed by
+// "slacko unread --json".
+type unreadEntry struct {
+	ChannelID string `json:"channel_id"`
+	Name      string `json:"name"`
+	Unread    int    `json:"unread"`
+	IsDM      bool   `json:"is_dm"`
+}
+
+// runUnread implements "slacko unread": prints conversations with unread
+// messages and a total.
+func runUnread(args []string, configPath string) error {
+	fs := newFlagSet("unread")
+	workspace := addWorkspaceFlag(fs)
+	asJSON := fs.Bool("json", false, "print JSON instead of text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := connect(*workspace, configPath)
+	if err != nil {
+		return err
+	}
+
+	entries, err := fetchUnreads(client)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if entries == nil {
+			entries = []unreadEntry{}
+		}
+		return enc.Encode(entries)
+	}
+
+	total := 0
+	for _, e := range entries {
+		fmt.Printf("%6d  %s\n", e.Unread, e.Name)
+		total += e.Unread
+	}
+	fmt.Printf("%6d  total\n", total)
+	return nil
+}
+
+// fetchUnreads returns the conversations the user belongs to that have
+// unread messages, most unread first. Counts come from conversations.list;
+// conversations.info is only asked for conversations the list returned
+// without read state (no last_read), which keeps this to one call per page
+// for most workspaces.
+func fetchUnreads(client *slackclient.Client) ([]unreadEntry, error) {
+	channels, err := client.GetAllConversations("public_channel", "private_channel", "mpim", "im")
+	if err != nil {
+		return nil, err
+	}
+
+	var userNames map[string]string
+	var entries []unreadEntry
+	for _, ch := range channels {
+		if !ch.IsMember && !ch.IsIM {
+			continue
+		}
+		unread := ch.UnreadCountDisplay
+		if unread == 0 && ch.LastRead == "" {
+			info, err := client.GetConversationInfo(ch.ID)
+			if err != nil {
+				slog.Warn("failed to fetch conversation info", "channel", ch.ID, "error", err)
+				continue
+			}
+			unread = info.UnreadCountDisplay
+		}
+		if unread == 0 {
+			continue
+		}
+
+		name := "#" + ch.Name
+		if ch.IsIM {
+			if userNames == nil {
+				userNames = fetchUserNames(client)
+			}
+			name = "@" + userNames[ch.User]
+			if userNames[ch.User] == "" {
+				name = "@" + ch.User
+			}
+		}
+		entries = append(entries, unreadEntry{
+			ChannelID: ch.ID,
+			Name:      name,
+			Unread:    unread,
+			IsDM:      ch.IsIM || ch.IsMpIM,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Unread > entries[j].Unread
+	})
+	return entries, nil
+}
+
+// fetchUserNames returns user ID → handle for the workspace. Failures are
+// logged and yield an empty map so callers can fall back to IDs.
+func fetchUserNames(client *slackclient.Client) map[string]string {
+	names := make(map[string]string)
+	users, err := client.GetUsers()
+	if err != nil {
+		slog.Warn("failed to fetch users", "error", err)
+		return names
+	}
+	for _, u := range users {
+		names[u.ID] = userHandle(u)
+	}
+	return names
+}
+
+// userHandle returns the name shown for a user: display name, then handle.
+func userHandle(u slack.User) string {
+	if u.Profile.DisplayName != "" {
+		return u.Profile.DisplayName
+	}
+	return u.Name
+}
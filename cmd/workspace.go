@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/m96-chan/Slacko/internal/config"
+	"github.com/m96-chan/Slacko/internal/keyring"
+	"github.com/m96-chan/Slacko/internal/oauth"
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
+)
+
+// workspaceUsage is printed for "slacko workspace" without a valid action.
+const workspaceUsage = `Usage:
+  slacko workspace list [--offline]          list workspaces and check their tokens
+  slacko workspace add [flags]               sign in to a workspace (OAuth, or --user-token)
+  slacko workspace remove <name|team ID>     remove a workspace and its tokens
+  slacko workspace rename <name|team ID> <new name>
+  slacko workspace default <name|team ID>    start Slacko with this workspace
+  slacko workspace migrate [--command cmd] <keyring|file|pass|command>
+                                             move stored tokens to another credential backend
+`
+
+// runWorkspace implements "slacko workspace": manages the workspace registry.
+func runWorkspace(args []string, configPath string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, workspaceUsage)
+		return errUsage
+	}
+
+	action, rest := args[0], args[1:]
+	switch action {
+	case "list", "ls":
+		return workspaceList(rest, configPath)
+	case "add":
+		return workspaceAdd(rest, configPath)
+	case "remove", "rm":
+		return workspaceRemove(rest)
+	case "rename":
+		return workspaceRename(rest)
+	case "default":
+		return workspaceDefault(rest)
+	case "migrate":
+		return workspaceMigrate(rest, configPath)
+	default:
+		fmt.Fprint(os.Stderr, workspaceUsage)
+		return fmt.Errorf("unknown workspace action %q", action)
+	}
+}
+
+// workspaceList prints the registry and, unless --offline, validates each
+// workspace's tokens with auth.test. It fails when any token needs
+// re-authentication so scripts can alert on it.
+func workspaceList(args []string, configPath string) error {
+	fs := newFlagSet("workspace list")
+	offline := fs.Bool("offline", false, "do not validate tokens")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ws, err := keyring.ListWorkspaces()
+	if err != nil {
+		return err
+	}
+	if len(ws) == 0 {
+		fmt.Println("No workspaces registered. Run `slacko workspace add` to sign in.")
+		return nil
+	}
+
+	params := refreshParams(configPath)
+	defaultID := keyring.DefaultWorkspaceID()
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tNAME\tTEAM ID\tDOMAIN\tSTATUS")
+	problems := 0
+	for _, w := range ws {
+		marker := ""
+		if w.ID == defaultID {
+			marker = "*"
+		}
+		status := "-"
+		if !*offline {
+			var ok bool
+			status, ok = checkWorkspace(w, params)
+			if !ok {
+				problems++
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", marker, w.Name, w.ID, w.Domain, status)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("%d workspace(s) need to be re-added with `slacko workspace add`", problems)
+	}
+	return nil
+}
+
+// checkWorkspace validates a workspace's stored tokens and returns a status
+// for display and whether the tokens are usable. A rotating token about to
+// expire is refreshed first, with params, like on startup.
+func checkWorkspace(w keyring.Workspace, params oauth.Params) (string, bool) {
+	tokens, err := keyring.GetWorkspaceTokens(w)
+	if err != nil {
+		return "missing tokens", false
+	}
+	if !strings.HasPrefix(tokens.AppToken, "xapp-") {
+		return "invalid app token", false
+	}
+	if tokens, err = oauth.FreshTokens(context.Background(), params, w); err != nil {
+		return "token refresh failed (" + err.Error() + ")", false
+	}
+
+	resp, err := slackclient.ValidateToken(tokens.UserToken)
+	if err != nil {
+		switch problem := slackclient.AuthProblem(err); problem {
+		case slackclient.TokenRevoked, slackclient.TokenExpired, slackclient.TokenInvalid:
+			return "token " + problem, false
+		default:
+			// Network errors say nothing about the token itself.
+			return "unknown (" + err.Error() + ")", true
+		}
+	}
+	if resp.TeamID != w.ID {
+		return "token belongs to " + resp.TeamID, false
+	}
+
+	if w.Domain == "" {
+		if domain := slackclient.TeamDomainFromURL(resp.URL); domain != "" {
+			_ = keyring.SetWorkspaceDomain(w.ID, domain)
+		}
+	}
+	return "ok (" + resp.User + ")", true
+}
+
+// workspaceAdd signs in to a workspace and stores it in the registry. With
+// --user-token (or SLACKO_USER_TOKEN) the given tokens are used; otherwise the
+// browser OAuth flow configured in config.toml runs.
+func workspaceAdd(args []string, configPath string) error {
+	fs := newFlagSet("workspace add")
+	userToken := fs.String("user-token", os.Getenv("SLACKO_USER_TOKEN"), "user token (xoxp-...) instead of OAuth")
+	appToken := fs.String("app-token", os.Getenv("SLACKO_APP_TOKEN"), "app-level token (xapp-...); defaults to oauth.app_token")
+	name := fs.String("name", "", "display name (default: the Slack team name)")
+	makeDefault := fs.Bool("default", false, "start Slacko with this workspace")
+	manual := fs.Bool("manual", false, "print the authorize URL and paste the result back, for a browser on another machine")
+	port := fs.Int("port", 0, "fixed port for the OAuth callback, e.g. for SSH port forwarding (default: oauth.port)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	if *port == 0 {
+		*port = cfg.OAuth.Port
+	}
+
+	user, app := *userToken, *appToken
+	var refresh string
+	var expires time.Time
+	if user == "" {
+		params := oauth.Params{
+			ClientID:     cfg.OAuth.ClientID,
+			ClientSecret: cfg.OAuth.ClientSecret,
+			ProxyURL:     cfg.OAuth.ProxyURL,
+			Port:         *port,
+		}
+		var result *oauth.Result
+		if *manual {
+			result, err = oauth.RunManual(context.Background(), params, os.Stdin, os.Stderr)
+		} else {
+			result, err = oauth.Run(context.Background(), params)
+		}
+		if err != nil {
+			return fmt.Errorf("oauth: %w", err)
+		}
+		user = result.UserToken
+		refresh, expires = result.RefreshToken, result.ExpiresAt
+		if app == "" {
+			app = result.AppToken
+		}
+	}
+	if app == "" {
+		app = cfg.OAuth.AppToken
+	}
+	if app == "" {
+		return errors.New("no app token: pass --app-token or set oauth.app_token in config")
+	}
+
+	client, err := slackclient.New(user, app)
+	if err != nil {
+		if problem := slackclient.AuthProblem(err); problem != "" {
+			return fmt.Errorf("token %s", problem)
+		}
+		return err
+	}
+
+	display := *name
+	if display == "" {
+		display = client.TeamName
+	}
+	if err := keyring.AddWorkspace(client.TeamID, display, user, app); err != nil {
+		return err
+	}
+	if err := keyring.SetWorkspaceRefresh(client.TeamID, refresh, expires); err != nil {
+		return err
+	}
+	if client.TeamDomain != "" {
+		_ = keyring.SetWorkspaceDomain(client.TeamID, client.TeamDomain)
+	}
+
+	if *makeDefault || keyring.DefaultWorkspaceID() == "" {
+		w, _ := keyring.FindWorkspace(client.TeamID)
+		if err := keyring.SetDefaultWorkspace(w); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Added %s (%s) as %s\n", display, client.TeamID, client.UserName)
+	return nil
+}
+
+// workspaceRemove deletes a workspace and its tokens. Removing the default
+// workspace also clears the default tokens.
+func workspaceRemove(args []string) error {
+	w, err := workspaceArg(args, 1)
+	if err != nil {
+		return err
+	}
+	wasDefault := keyring.DefaultWorkspaceID() == w.ID
+	if err := keyring.RemoveWorkspace(w.ID); err != nil {
+		return err
+	}
+	if wasDefault {
+		_ = keyring.DeleteUserToken()
+		_ = keyring.DeleteAppToken()
+	}
+	fmt.Printf("Removed %s (%s)\n", w.Name, w.ID)
+	return nil
+}
+
+// workspaceRename changes a workspace's display name.
+func workspaceRename(args []string) error {
+	w, err := workspaceArg(args, 2)
+	if err != nil {
+		return err
+	}
+	name := strings.Join(args[1:], " ")
+	if err := keyring.RenameWorkspace(w.ID, name); err != nil {
+		return err
+	}
+	fmt.Printf("Renamed %s to %s\n", w.Name, name)
+	return nil
+}
+
+// workspaceDefault makes a workspace the one Slacko starts with.
+func workspaceDefault(args []string) error {
+	w, err := workspaceArg(args, 1)
+	if err != nil {
+		return err
+	}
+	if err := keyring.SetDefaultWorkspace(w); err != nil {
+		return err
+	}
+	fmt.Printf("%s is now the default workspace\n", w.Name)
+	return nil
+}
+
+// workspaceMigrate moves every stored token from the configured credential
+// backend to another one and switches credentials.backend in the config file
+// to it.
+func workspaceMigrate(args []string, configPath string) error {
+	fs := newFlagSet("workspace migrate")
+	command := fs.String("command", "", "helper program for the command backend (default: credentials.command)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || fs.Arg(0) == keyring.BackendAuto {
+		fmt.Fprint(os.Stderr, workspaceUsage)
+		return errUsage
+	}
+	backend := fs.Arg(0)
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	cmdline := *command
+	if cmdline == "" {
+		cmdline = cfg.Credentials.Command
+	}
+
+	from := keyring.Current()
+	to, err := keyring.NewStore(backend, credentialOptions(cmdline))
+	if err != nil {
+		return err
+	}
+	n, err := keyring.Migrate(from, to)
+	if err != nil {
+		return err
+	}
+	keyring.Use(to)
+
+	if *command != "" {
+		if err := config.SetValue(cfg.Path, "credentials.command", *command); err != nil {
+			return err
+		}
+	}
+	if err := config.SetValue(cfg.Path, "credentials.backend", backend); err != nil {
+		return err
+	}
+	fmt.Printf("Moved %d token(s) from %s to %s\n", n, from, to)
+	return nil
+}
+
+// workspaceArg looks up the workspace named by args[0], requiring at least
+// min arguments.
+func workspaceArg(args []string, min int) (keyring.Workspace, error) {
+	if len(args) < min {
+		fmt.Fprint(os.Stderr, workspaceUsage)
+		return keyring.Workspace{}, errUsage
+	}
+	w, ok := keyring.FindWorkspace(args[0])
+	if !ok {
+		return keyring.Workspace{}, fmt.Errorf("workspace not found: %s", args[0])
+	}
+	return w, nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gokeyring "github.com/zalando/go-keyring"
+
+	"github.com/m96-chan/Slacko/internal/config"
+	"github.com/m96-chan/Slacko/internal/consts"
+	"github.com/m96-chan/Slacko/internal/keyring"
+)
+
+func useTempRegistry(t *testing.T) {
+	t.Helper()
+	gokeyring.MockInit()
+	orig := consts.CacheDir
+	consts.CacheDir = t.TempDir()
+	t.Cleanup(func() { consts.CacheDir = orig })
+	t.Setenv("SLACKO_USER_TOKEN", "")
+	t.Setenv("SLACKO_BOT_TOKEN", "")
+	t.Setenv("SLACKO_APP_TOKEN", "")
+}
+
+func TestWorkspaceRenameDefaultRemove(t *testing.T) {
+	useTempRegistry(t)
+	if err := keyring.AddWorkspace("T1", "One", "xoxp-1", "xapp-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runWorkspace([]string{"rename", "one", "Work", "Space"}, ""); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if w, ok := keyring.FindWorkspace("T1"); !ok || w.Name != "Work Space" {
+		t.Errorf("after rename = %+v", w)
+	}
+
+	if err := runWorkspace([]string{"default", "T1"}, ""); err != nil {
+		t.Fatalf("default: %v", err)
+	}
+	if id := keyring.DefaultWorkspaceID(); id != "T1" {
+		t.Errorf("default = %q, want T1", id)
+	}
+
+	if err := runWorkspace([]string{"remove", "Work Space"}, ""); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, ok := keyring.FindWorkspace("T1"); ok {
+		t.Error("workspace still registered after remove")
+	}
+	if _, err := keyring.GetUserToken(); err == nil {
+		t.Error("removing the default workspace should clear the default tokens")
+	}
+}
+
+func TestWorkspaceUnknownAction(t *testing.T) {
+	if err := runWorkspace([]string{"frobnicate"}, ""); err == nil {
+		t.Error("unknown action should fail")
+	}
+	if err := runWorkspace([]string{"remove", "nope"}, ""); err == nil {
+		t.Error("removing an unknown workspace should fail")
+	}
+}
+
+func TestWorkspaceMigrate(t *testing.T) {
+	useTempRegistry(t)
+	orig := keyring.Current()
+	t.Cleanup(func() { keyring.Use(orig) })
+	t.Setenv("SLACKO_PASSPHRASE", "correct horse")
+	if err := keyring.AddWorkspace("T1", "One", "xoxp-1", "xapp-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	if err := runWorkspace([]string{"migrate", "file"}, configPath); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Credentials.Backend != keyring.BackendFile {
+		t.Errorf("credentials.backend = %q, want file", cfg.Credentials.Backend)
+	}
+	if _, err := os.Stat(keyring.FilePath()); err != nil {
+		t.Errorf("encrypted file not written: %v", err)
+	}
+	if _, err := gokeyring.Get(consts.Name, "user_T1"); err == nil {
+		t.Error("token left in the system keyring")
+	}
+	w, _ := keyring.FindWorkspace("T1")
+	if tokens, err := keyring.GetWorkspaceTokens(w); err != nil || tokens.UserToken != "xoxp-1" {
+		t.Errorf("tokens after migrate = %+v, %v", tokens, err)
+	}
+
+	if err := runWorkspace([]string{"migrate", "auto"}, configPath); err == nil {
+		t.Error("migrating to auto should fail")
+	}
+}
@@ -0,0 +1,262 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+
+	"github.com/m96-chan/Slacko/internal/notifications"
+	"github.com/m96-chan/Slacko/internal/ui/chat"
+)
+
+// activitySearchCount is the number of search results requested per query
+// when refreshing the activity inbox.
+const activitySearchCount = 50
+
+// activityQuery pairs a search query with the kind of activity it finds.
+type activityQuery struct {
+	query string
+	kind  chat.ActivityKind
+}
+
+// userDisplayName resolves a user ID to a display name, falling back to the
+// real name, the handle and finally the ID itself.
+func userDisplayName(users map[string]slack.User, userID string) string {
+	u, ok := users[userID]
+	if !ok {
+		return userID
+	}
+	if u.Profile.DisplayName != "" {
+		return u.Profile.DisplayName
+	}
+	if u.RealName != "" {
+		return u.RealName
+	}
+	if u.Name != "" {
+		return u.Name
+	}
+	return userID
+}
+
+// channelLabel returns a human-readable name for a channel ID. DMs resolve
+// to the other user's name. Callers must hold a.mu.
+func (a *App) channelLabel(channelID string) string {
+	for _, ch := range a.channels {
+		if ch.ID != channelID {
+			continue
+		}
+		if ch.IsIM {
+			return userDisplayName(a.users, ch.User)
+		}
+		return ch.Name
+	}
+	return ""
+}
+
+// fetchUserGroups loads the IDs of the user groups the current user belongs
+// to, so that subteam mentions can be detected. Missing the usergroups:read
+// scope is not fatal; group mentions are then simply not reported.
+func (a *App) fetchUserGroups() {
+	groups, err := a.slack.GetUserGroups()
+	if err != nil {
+		slog.Warn("failed to fetch user groups", "error", err)
+		return
+	}
+
+	var ids []string
+	for _, g := range groups {
+		for _, uid := range g.Users {
+			if uid == a.slack.UserID {
+				ids = append(ids, g.ID)
+				break
+			}
+		}
+	}
+
+	a.mu.Lock()
+	a.userGroups = ids
+	a.mu.Unlock()
+}
+
+// loadActivity searches for direct, user group and @here/@channel mentions
+// and merges the results into the activity inbox.
+func (a *App) loadActivity() {
+	a.mu.Lock()
+	groups := a.userGroups
+	a.mu.Unlock()
+
+	queries := []activityQuery{
+		{"<@" + a.slack.UserID + ">", chat.ActivityMention},
+		{"<!here>", chat.ActivityBroadcast},
+		{"<!channel>", chat.ActivityBroadcast},
+	}
+	for _, id := range groups {
+		queries = append(queries, activityQuery{"<!subteam^" + id + ">", chat.ActivityGroupMention})
+	}
+
+	var entries []chat.ActivityEntry
+	failed := false
+	for _, q := range queries {
+		results, err := a.slack.SearchMessages(q.query, slack.SearchParameters{
+			Count:         activitySearchCount,
+			Sort:          "timestamp",
+			SortDirection: "desc",
+		})
+		if err != nil {
+			slog.Error("failed to search activity", "query", q.query, "error", err)
+			failed = true
+			continue
+		}
+		entries = append(entries, a.activityFromSearch(results.Matches, q.kind)...)
+	}
+
+	a.tview.QueueUpdateDraw(func() {
+		a.chatView.ActivityPanel.AddEntries(entries)
+		a.updateActivityStatus(failed)
+	})
+}
+
+// activityFromSearch converts search matches to activity entries.
+func (a *App) activityFromSearch(matches []slack.SearchMessage, kind chat.ActivityKind) []chat.ActivityEntry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entries := make([]chat.ActivityEntry, 0, len(matches))
+	for _, m := range matches {
+		if m.User == a.slack.UserID {
+			continue
+		}
+		userName := m.Username
+		if userName == "" {
+			userName = userDisplayName(a.users, m.User)
+		}
+		// Prefer the locally known name: search reports DMs by user ID.
+		channelName := a.conversationLabel(m.Channel.ID)
+		if channelName == "" && m.Channel.Name != "" && !m.Channel.IsMPIM {
+			channelName = "#" + m.Channel.Name
+		}
+		entries = append(entries, chat.ActivityEntry{
+			Kind:        kind,
+			ChannelID:   m.Channel.ID,
+			ChannelName: channelName,
+			UserName:    userName,
+			Timestamp:   m.Timestamp,
+			ThreadTS:    threadTSFromPermalink(m.Permalink),
+			Text:        m.Text,
+		})
+	}
+	return entries
+}
+
+// updateActivityStatus refreshes the activity panel status line.
+// Must be called on the UI goroutine.
+func (a *App) updateActivityStatus(failed bool) {
+	unread := a.chatView.ActivityPanel.UnreadCount()
+	status := fmt.Sprintf("%d unread  [x]toggle read [X]mark all read", unread)
+	if failed {
+		status = "Some searches failed — " + status
+	}
+	a.chatView.ActivityPanel.SetStatus(status)
+}
+
+// recordMessageActivity adds an incoming message to the activity inbox if it
+// mentions the current user, one of their user groups, or the whole channel.
+func (a *App) recordMessageActivity(evt *slackevents.MessageEvent) {
+	if evt.User == "" || evt.User == a.slack.UserID {
+		return
+	}
+
+	a.mu.Lock()
+	isDM := a.dmSet[evt.Channel]
+	groups := a.userGroups
+	a.mu.Unlock()
+	if isDM {
+		return
+	}
+
+	var kind chat.ActivityKind
+	switch notifications.DetectMention(evt.Text, a.slack.UserID, false) {
+	case notifications.MentionDirect:
+		kind = chat.ActivityMention
+	case notifications.MentionHere, notifications.MentionChannel, notifications.MentionEveryone:
+		kind = chat.ActivityBroadcast
+	default:
+		found := false
+		for _, id := range groups {
+			if strings.Contains(evt.Text, "<!subteam^"+id) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return
+		}
+		kind = chat.ActivityGroupMention
+	}
+
+	a.mu.Lock()
+	entry := chat.ActivityEntry{
+		Kind:        kind,
+		ChannelID:   evt.Channel,
+		ChannelName: a.conversationLabel(evt.Channel),
+		UserName:    userDisplayName(a.users, evt.User),
+		Timestamp:   evt.TimeStamp,
+		ThreadTS:    evt.ThreadTimeStamp,
+		Text:        evt.Text,
+	}
+	a.mu.Unlock()
+
+	a.tview.QueueUpdateDraw(func() {
+		a.chatView.ActivityPanel.AddEntries([]chat.ActivityEntry{entry})
+	})
+}
+
+// recordReactionActivity adds a reaction on one of our own messages to the
+// activity inbox.
+func (a *App) recordReactionActivity(evt *slackevents.ReactionAddedEvent) {
+	if evt.ItemUser != a.slack.UserID || evt.User == a.slack.UserID {
+		return
+	}
+	if evt.Item.Type != "" && evt.Item.Type != "message" {
+		return
+	}
+
+	a.mu.Lock()
+	entry := chat.ActivityEntry{
+		Kind:        chat.ActivityReaction,
+		ChannelID:   evt.Item.Channel,
+		ChannelName: a.conversationLabel(evt.Item.Channel),
+		UserName:    userDisplayName(a.users, evt.User),
+		Timestamp:   evt.Item.Timestamp,
+		Reaction:    evt.Reaction,
+		EventTS:     evt.EventTimestamp,
+	}
+	a.mu.Unlock()
+
+	a.tview.QueueUpdateDraw(func() {
+		a.chatView.ActivityPanel.AddEntries([]chat.ActivityEntry{entry})
+	})
+}
+
+// openActivity jumps to the message referenced by an activity entry.
+func (a *App) openActivity(entry chat.ActivityEntry) {
+	a.jumpToMessage(entry.ChannelID, entry.Timestamp, entry.ThreadTS)
+}
+
+// threadTSFromPermalink extracts the thread_ts query parameter from a Slack
+// permalink, returning "" when the message is not a thread reply.
+func threadTSFromPermalink(permalink string) string {
+	_, query, ok := strings.Cut(permalink, "?")
+	if !ok {
+		return ""
+	}
+	for _, kv := range strings.Split(query, "&") {
+		if v, ok := strings.CutPrefix(kv, "thread_ts="); ok {
+			return v
+		}
+	}
+	return ""
+}
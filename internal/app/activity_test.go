@@ -0,0 +1,71 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestThreadTSFromPermalink(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"https://team.slack.com/archives/C123/p1700000000123456", ""},
+		{"https://team.slack.com/archives/C123/p1700000001000000?thread_ts=1700000000.123456&cid=C123", "1700000000.123456"},
+		{"https://team.slack.com/archives/C123/p1700000001000000?cid=C123&thread_ts=1700000000.123456", "1700000000.123456"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := threadTSFromPermalink(tt.link); got != tt.want {
+			t.Errorf("threadTSFromPermalink(%q) = %q, want %q", tt.link, got, tt.want)
+		}
+	}
+}
+
+func TestConversationLabel(t *testing.T) {
+	a := &App{
+		channels: []slack.Channel{
+			{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C1"}, Name: "general"}},
+			{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D1", IsIM: true, User: "U1"}}},
+			{GroupConversation: slack.GroupConversation{
+				Conversation: slack.Conversation{ID: "G1", IsMpIM: true},
+				Name:         "mpdm-alice--bob-1",
+				Purpose:      slack.Purpose{Value: "Group messaging with: @alice @bob"},
+			}},
+		},
+		users: map[string]slack.User{"U1": {ID: "U1", Name: "alice"}},
+	}
+	tests := map[string]string{
+		"C1": "#general",
+		"D1": "alice",
+		"G1": "Group messaging with: @alice @bob",
+		"C9": "",
+	}
+	for id, want := range tests {
+		if got := a.conversationLabel(id); got != want {
+			t.Errorf("conversationLabel(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestUserDisplayName(t *testing.T) {
+	users := map[string]slack.User{
+		"U1": {ID: "U1", Name: "alice", RealName: "Alice A", Profile: slack.UserProfile{DisplayName: "ali"}},
+		"U2": {ID: "U2", Name: "bob", RealName: "Bob B"},
+		"U3": {ID: "U3", Name: "carol"},
+		"U4": {ID: "U4"},
+	}
+	tests := map[string]string{
+		"U1": "ali",
+		"U2": "Bob B",
+		"U3": "carol",
+		"U4": "U4",
+		"U9": "U9",
+	}
+	for id, want := range tests {
+		if got := userDisplayName(users, id); got != want {
+			t.Errorf("userDisplayName(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/m96-chan/Slacko/internal/ui/chat"
+)
+
+// maxAliasDepth limits how deeply aliases may invoke other aliases, so an
+// alias that calls itself stops instead of recursing forever.
+const maxAliasDepth = 10
+
+// runAlias runs the user-defined alias name with args, returning false when
+// no such alias exists. Each expanded step is dispatched like typed input:
+// ":" steps as vim commands, "/" steps as slash commands in channelID and
+// anything else as a message to channelID. When channelID is empty the
+// current channel is used. Must be called from the tview event loop.
+func (a *App) runAlias(name, channelID, args string) bool {
+	alias, ok := a.Config.Aliases[name]
+	if !ok {
+		return false
+	}
+	if a.aliasDepth >= maxAliasDepth {
+		slog.Warn("alias nesting too deep", "alias", name)
+		a.showCommandFeedback(fmt.Sprintf("Alias %s: nested too deeply", name))
+		return true
+	}
+	a.aliasDepth++
+	defer func() { a.aliasDepth-- }()
+
+	if channelID == "" {
+		a.mu.Lock()
+		channelID = a.currentChannel
+		a.mu.Unlock()
+	}
+
+	for _, step := range alias.Expand(args) {
+		step = strings.TrimSpace(step)
+		switch {
+		case strings.HasPrefix(step, ":"):
+			command, stepArgs := chat.ParseVimCommand(step[1:])
+			a.executeVimCommand(command, stepArgs)
+		case strings.HasPrefix(step, "/"):
+			if channelID == "" {
+				a.showCommandFeedback(fmt.Sprintf("Alias %s: no channel selected", name))
+				return true
+			}
+			command, stepArgs := chat.ParseSlashCommand(step)
+			a.executeSlashCommand(channelID, command, stepArgs)
+		default:
+			if channelID == "" {
+				a.showCommandFeedback(fmt.Sprintf("Alias %s: no channel selected", name))
+				return true
+			}
+			a.onMessageSend(channelID, step, "")
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunAliasRunsVimSteps(t *testing.T) {
+	a := newThemeTestApp(t, `[aliases]
+dark = [":theme $1", ":set timestamps=false"]
+`)
+
+	if !a.runAlias("dark", "C1", "monokai") {
+		t.Fatal("runAlias did not find the alias")
+	}
+	if a.Config.Theme.Preset != "monokai" {
+		t.Errorf("Preset = %q, want monokai", a.Config.Theme.Preset)
+	}
+	if a.Config.Timestamps.Enabled {
+		t.Error("timestamps still enabled after alias")
+	}
+}
+
+func TestRunAliasUnknown(t *testing.T) {
+	a := newThemeTestApp(t, "")
+
+	if a.runAlias("nope", "C1", "") {
+		t.Error("runAlias reported an undefined alias as found")
+	}
+}
+
+func TestRunAliasStopsRecursion(t *testing.T) {
+	a := newThemeTestApp(t, `[aliases]
+loop = [":loop", ":theme light"]
+`)
+
+	a.runAlias("loop", "C1", "")
+	if a.aliasDepth != 0 {
+		t.Errorf("aliasDepth = %d after run, want 0", a.aliasDepth)
+	}
+	if a.Config.Theme.Preset != "light" {
+		t.Errorf("steps after the recursive call did not run; Preset = %q", a.Config.Theme.Preset)
+	}
+}
+
+func TestFormatHelpTextListsAliases(t *testing.T) {
+	a := newThemeTestApp(t, `[aliases]
+standup = ["/status :calendar: Standup", ":join #standup"]
+`)
+
+	help := a.formatHelpText()
+	if !strings.Contains(help, "/standup — /status :calendar: Standup; :join #standup") {
+		t.Errorf("help text does not list the alias: %q", help)
+	}
+}
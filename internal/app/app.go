@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
@@ -20,14 +22,15 @@ import (
 	"github.com/gdamore/tcell/v2"
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
-	gokeyring "github.com/zalando/go-keyring"
 
 	"github.com/m96-chan/Slacko/internal/clipboard"
 	"github.com/m96-chan/Slacko/internal/config"
+	"github.com/m96-chan/Slacko/internal/hooks"
 	"github.com/m96-chan/Slacko/internal/keyring"
 	"github.com/m96-chan/Slacko/internal/markdown"
 	"github.com/m96-chan/Slacko/internal/notifications"
 	slackclient "github.com/m96-chan/Slacko/internal/slack"
+	"github.com/m96-chan/Slacko/internal/status"
 	"github.com/m96-chan/Slacko/internal/typing"
 	"github.com/m96-chan/Slacko/internal/ui/chat"
 	"github.com/m96-chan/Slacko/internal/ui/keys"
@@ -35,10 +38,14 @@ import (
 	"github.com/rivo/tview"
 )
 
-// App is the top-level application struct.
+// App is the top-level application struct. The App created by New runs
+// the UI; every connected workspace gets an App of its own that shares its
+// tview application, config and notifier (see workspaces.go).
 type App struct {
 	Config         *config.Config
+	cfgState       *sharedConfig
 	tview          *tview.Application
+	workspaces     *workspaceSet
 	slack          *slackclient.Client
 	chatView       *chat.View
 	notifier       *notifications.Notifier
@@ -48,7 +55,10 @@ type App struct {
 	dmSet          map[string]bool            // set of DM channel IDs
 	lastRead       map[string]string          // channelID → last-read timestamp
 	pinnedMsgs     map[string]map[string]bool // channelID → set of pinned timestamps
+	userGroups     []string                   // IDs of user groups the current user belongs to
+	pendingLink    *pendingPermalink          // permalink to open once data has loaded
 	currentChannel string
+	aliasDepth     int // nesting depth of running aliases
 	typingTracker  *typing.Tracker
 	mu             sync.Mutex
 }
@@ -57,7 +67,9 @@ type App struct {
 func New(cfg *config.Config) *App {
 	return &App{
 		Config:     cfg,
+		cfgState:   newSharedConfig(cfg),
 		tview:      tview.NewApplication(),
+		workspaces: newWorkspaceSet(),
 		users:      make(map[string]slack.User),
 		dmSet:      make(map[string]bool),
 		lastRead:   make(map[string]string),
@@ -86,29 +98,39 @@ func (a *App) Run() error {
 	app, appErr := keyring.GetAppToken()
 
 	if userErr == nil && appErr == nil {
-		client, err := slackclient.New(user, app)
-		if err != nil {
+		if user, err := a.freshDefaultToken(user); err != nil {
+			slog.Warn("failed to refresh user token, showing login", "error", err)
+			a.showReauth(fmt.Sprintf("Your session has expired (%v). Sign in again to continue.", err))
+		} else if client, err := slackclient.New(user, app); err != nil {
 			slog.Warn("stored tokens invalid, showing login", "error", err)
 			a.showLogin()
 		} else {
-			a.slack = client
-			a.showMain()
+			a.openWorkspace(client)
 		}
 	} else {
-		if userErr != nil && !errors.Is(userErr, gokeyring.ErrNotFound) {
+		if userErr != nil && !errors.Is(userErr, keyring.ErrNotFound) {
 			slog.Warn("error reading user token", "error", userErr)
 		}
-		if appErr != nil && !errors.Is(appErr, gokeyring.ErrNotFound) {
+		if appErr != nil && !errors.Is(appErr, keyring.ErrNotFound) {
 			slog.Warn("error reading app token", "error", appErr)
 		}
 		a.showLogin()
 	}
 
+	stopWatch := make(chan struct{})
+	defer close(stopWatch)
+	go a.watchConfig(a.Config.Path, stopWatch)
+
 	return a.tview.Run()
 }
 
-// shutdown tears down Socket Mode and stops the TUI.
+// shutdown tears down the event loops of all workspaces and stops the TUI.
 func (a *App) shutdown() {
+	for _, w := range a.workspaces.all() {
+		if w.cancel != nil {
+			w.cancel()
+		}
+	}
 	if a.cancel != nil {
 		a.cancel()
 	}
@@ -118,7 +140,13 @@ func (a *App) shutdown() {
 // logout deletes stored tokens and returns to the login screen.
 // Must be called from the tview event loop (slash command or vim command handler).
 func (a *App) logout() {
-	// Stop Socket Mode.
+	// Stop Socket Mode in every workspace.
+	for _, w := range a.workspaces.reset() {
+		if w.cancel != nil {
+			w.cancel()
+			w.cancel = nil
+		}
+	}
 	if a.cancel != nil {
 		a.cancel()
 		a.cancel = nil
@@ -138,14 +166,14 @@ func (a *App) logout() {
 func (a *App) handleGlobalKey(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
-	if name == a.Config.Keybinds.Quit {
+	if a.Config.Keybinds.Quit.Matches(name) {
 		a.shutdown()
 		return nil
 	}
 
-	// Delegate to chat view if active.
-	if a.chatView != nil {
-		return a.chatView.HandleKey(event)
+	// Delegate to the chat view of the workspace on screen, if any.
+	if view := a.activeApp().chatView; view != nil {
+		return view.HandleKey(event)
 	}
 
 	return event
@@ -153,16 +181,26 @@ func (a *App) handleGlobalKey(event *tcell.EventKey) *tcell.EventKey {
 
 // showLogin sets the root to the login form.
 func (a *App) showLogin() {
-	form := login.New(a.tview, a.Config, func(client *slackclient.Client) {
-		a.slack = client
-		a.showMain()
-	})
+	form := login.New(a.tview, a.Config, a.openWorkspace)
+	a.tview.SetRoot(form, true)
+}
+
+// showReauth shows the login form with notice explaining why the user has
+// to sign in again.
+func (a *App) showReauth(notice string) {
+	form := login.NewReauth(a.tview, a.Config, notice, a.openWorkspace)
 	a.tview.SetRoot(form, true)
 }
 
-// showMain sets the root to the chat layout and starts Socket Mode in the
-// background.
+// showMain starts the workspace of a.slack and shows it.
 func (a *App) showMain() {
+	a.startWorkspace()
+	a.showWorkspace()
+}
+
+// startWorkspace builds the chat layout for a.slack and starts its Socket
+// Mode loop in the background, without showing it.
+func (a *App) startWorkspace() {
 	// Cancel any previous Socket Mode connection.
 	if a.cancel != nil {
 		a.cancel()
@@ -228,12 +266,16 @@ func (a *App) showMain() {
 	})
 
 	// Wire search picker.
-	a.chatView.SearchPicker.SetOnSearch(func(query string) {
-		go a.searchMessages(query)
+	a.chatView.SearchPicker.SetOnSearch(func(req chat.SearchRequest) {
+		go a.search(req)
 	})
 	a.chatView.SearchPicker.SetOnSelect(func(channelID, timestamp string) {
 		a.chatView.HideSearchPicker()
-		a.onChannelSelected(channelID)
+		a.jumpToMessage(channelID, timestamp, "")
+	})
+	a.chatView.SearchPicker.SetOnFileSelect(func(file slack.File) {
+		a.chatView.HideSearchPicker()
+		go a.openFile(file)
 	})
 
 	// Wire file picker: Ctrl+F from input opens picker, selection triggers upload.
@@ -256,10 +298,24 @@ func (a *App) showMain() {
 	a.chatView.MessagesList.SetOnFileOpenRequest(func(channelID string, file slack.File) {
 		go a.openFile(file)
 	})
+	a.chatView.MessagesList.SetOnOpenLink(func(link string) {
+		go a.openLink(link)
+	})
 
 	// Wire pins picker selection: jump to the channel/message.
 	a.chatView.PinsPicker.SetOnSelect(func(channelID, timestamp string) {
 		a.chatView.HidePinsPicker()
+		a.jumpToMessage(channelID, timestamp, "")
+	})
+
+	// Wire history paging in both directions from a loaded window.
+	a.chatView.MessagesList.SetOnLoadHistory(func(channelID, edgeTS string, older bool) {
+		go a.loadHistoryPage(channelID, edgeTS, older)
+	})
+
+	// Wire inline previews for linked Slack messages.
+	a.chatView.MessagesList.SetOnPreviewRequest(func(url string) {
+		go a.loadMessagePreview(url)
 	})
 
 	// Wire pin/unpin toggle from messages list.
@@ -386,7 +442,7 @@ func (a *App) showMain() {
 		a.mu.Lock()
 		isCurrent := channelID == a.currentChannel
 		a.mu.Unlock()
-		if isCurrent && a.Config.TypingIndicator.Receive {
+		if isCurrent && a.config().TypingIndicator.Receive {
 			status := a.typingTracker.FormatStatus(channelID)
 			a.tview.QueueUpdateDraw(func() {
 				a.chatView.StatusBar.SetTypingIndicator(status)
@@ -395,8 +451,13 @@ func (a *App) showMain() {
 	})
 	if a.Config.TypingIndicator.Send {
 		a.chatView.MessageInput.SetOnTyping(func(channelID string) {
-			// Typing send is a no-op until RTM support is added.
-			slog.Debug("typing indicator send", "channel", channelID)
+			if !a.Config.TypingIndicator.Enabled || !a.Config.TypingIndicator.Send {
+				return
+			}
+			// Only the RTM transport can send typing events.
+			if err := a.slack.SendTyping(channelID); err != nil {
+				slog.Debug("typing indicator not sent", "channel", channelID, "error", err)
+			}
 		})
 	}
 
@@ -412,6 +473,20 @@ func (a *App) showMain() {
 		go a.loadPinnedMessages(ch)
 	})
 
+	// Wire activity inbox: refresh mentions when user opens the popup.
+	a.chatView.SetOnActivity(func() {
+		a.chatView.ActivityPanel.SetStatus("Loading...")
+		go a.loadActivity()
+	})
+	a.chatView.ActivityPanel.SetOnSelect(func(entry chat.ActivityEntry) {
+		a.chatView.HideActivityPanel()
+		a.openActivity(entry)
+	})
+
+	// Wire theme picker: preview on the live UI while moving through the list.
+	a.chatView.ThemePicker.SetOnPreview(a.applyTheme)
+	a.chatView.ThemePicker.SetOnSelect(a.selectTheme)
+
 	// Wire bookmarks popup: fetch bookmarks when user opens the popup.
 	a.chatView.SetOnBookmarks(func() {
 		a.mu.Lock()
@@ -567,6 +642,7 @@ func (a *App) showMain() {
 		a.executeVimCommand(command, args)
 	})
 	a.chatView.CommandBar.SetSetOptionNames(RuntimeOptionNames())
+	a.chatView.CommandBar.SetSetOptionValues(RuntimeOptionValues())
 
 	// Wire channel create form.
 	a.chatView.ChannelCreateForm.SetOnCreate(func(name string, isPrivate bool) {
@@ -615,15 +691,22 @@ func (a *App) showMain() {
 
 	a.chatView.StatusBar.SetConnectionStatus(
 		fmt.Sprintf("%s (%s) — connecting...", a.slack.UserName, a.slack.TeamName))
-	a.tview.SetRoot(a.chatView, true)
-	a.chatView.FocusPanel(chat.PanelChannels)
+	a.workspaces.add(a)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	a.cancel = cancel
 
+	statusFile := status.NewPublisher(a.slack.TeamID, a.slack.TeamName)
+	a.chatView.ChannelsTree.SetOnUnreadChanged(func() {
+		statusFile.SetCounts(a.chatView.ChannelsTree.UnreadTotals())
+		a.refreshWorkspaceBadges()
+	})
+	go statusFile.Run(ctx)
+
 	handler := &slackclient.EventHandler{
 		OnConnected: func() {
 			slog.Info("socket mode connected")
+			statusFile.SetState(status.StateConnected)
 			a.tview.QueueUpdateDraw(func() {
 				a.chatView.StatusBar.SetConnectionStatus(
 					fmt.Sprintf("%s (%s) — connected", a.slack.UserName, a.slack.TeamName))
@@ -634,6 +717,7 @@ func (a *App) showMain() {
 		},
 		OnDisconnected: func() {
 			slog.Warn("socket mode disconnected")
+			statusFile.SetState(status.StateDisconnected)
 			a.tview.QueueUpdateDraw(func() {
 				a.chatView.StatusBar.SetConnectionStatus(
 					fmt.Sprintf("%s (%s) — disconnected", a.slack.UserName, a.slack.TeamName))
@@ -641,6 +725,7 @@ func (a *App) showMain() {
 		},
 		OnError: func(err error) {
 			slog.Error("socket mode error", "error", err)
+			statusFile.SetState(status.StateError)
 			a.tview.QueueUpdateDraw(func() {
 				a.chatView.StatusBar.SetConnectionStatus(
 					fmt.Sprintf("%s (%s) — error: %s", a.slack.UserName, a.slack.TeamName, err.Error()))
@@ -685,8 +770,13 @@ func (a *App) showMain() {
 			msg.Channel = evt.Channel
 
 			a.mu.Lock()
-			isCurrent := evt.Channel == a.currentChannel
+			// The open channel of a workspace that is not on screen is
+			// not being read.
+			isCurrent := evt.Channel == a.currentChannel && a.workspaces.isActive(a)
+			isDM := a.dmSet[evt.Channel]
 			a.mu.Unlock()
+			mentioned := evt.User != a.slack.UserID &&
+				notifications.DetectMention(evt.Text, a.slack.UserID, isDM) != notifications.MentionNone
 
 			a.tview.QueueUpdateDraw(func() {
 				a.chatView.MessagesList.AppendMessage(evt.Channel, msg)
@@ -704,6 +794,9 @@ func (a *App) showMain() {
 				// Update unread badge for background channels.
 				if !isCurrent {
 					a.chatView.ChannelsTree.SetUnreadCount(evt.Channel, -1)
+					if mentioned {
+						a.chatView.ChannelsTree.SetMentionCount(evt.Channel, -1)
+					}
 				}
 			})
 
@@ -721,6 +814,7 @@ func (a *App) showMain() {
 
 			// Desktop notifications.
 			a.maybeNotify(evt)
+			a.recordMessageActivity(evt)
 		},
 		OnMessageChanged: func(evt *slackevents.MessageEvent) {
 			if evt.Message == nil {
@@ -750,6 +844,7 @@ func (a *App) showMain() {
 				a.chatView.MessagesList.AddReaction(
 					evt.Item.Channel, evt.Item.Timestamp, evt.Reaction, evt.User)
 			})
+			a.recordReactionActivity(evt)
 		},
 		OnReactionRemoved: func(evt *slackevents.ReactionRemovedEvent) {
 			a.tview.QueueUpdateDraw(func() {
@@ -800,8 +895,9 @@ func (a *App) showMain() {
 				})
 			}
 		},
+		OnPresenceChange: a.onPresenceChange,
 		OnTyping: func(evt *slackclient.TypingEvent) {
-			if a.typingTracker == nil || !a.Config.TypingIndicator.Receive {
+			if a.typingTracker == nil || !a.config().TypingIndicator.Receive {
 				return
 			}
 			a.mu.Lock()
@@ -842,9 +938,23 @@ func (a *App) showMain() {
 		},
 	}
 
+	runner := hooks.NewRunner(func() []config.Hook { return a.config().Hooks }, a.slack.TeamID, a.slack.UserID, a.runHookAction)
+	srv := a.startRPC(ctx)
+	wrapped := hooks.Wrap(handler, func(event string, data any) {
+		runner.Dispatch(event, data)
+		a.publishEvent(srv, event, data)
+	})
+	go a.keepTokenFresh(ctx)
+
+	run := func(ctx context.Context, handler *slackclient.EventHandler) error {
+		return a.workspaces.runSocketMode(ctx, a.slack, handler)
+	}
+	if a.Config.Transport == config.TransportRTM {
+		run = a.slack.RunRTM
+	}
 	go func() {
-		if err := a.slack.RunSocketMode(ctx, handler); err != nil {
-			slog.Error("socket mode exited", "error", err)
+		if err := run(ctx, wrapped); err != nil {
+			slog.Error("event transport exited", "transport", a.Config.Transport, "error", err)
 		}
 	}()
 }
@@ -891,11 +1001,21 @@ func (a *App) fetchInitialData() {
 
 	slog.Info("initial data loaded", "channels", len(channels), "users", len(users))
 
+	a.subscribeDMPresence(channels)
+
+	a.fetchUserGroups()
+
 	// Migrate legacy tokens and populate workspace picker.
 	if err := keyring.MigrateDefaultWorkspace(a.slack.TeamID, a.slack.TeamName); err != nil {
 		slog.Warn("failed to migrate workspace to registry", "error", err)
 	}
+	if a.slack.TeamDomain != "" {
+		if err := keyring.SetWorkspaceDomain(a.slack.TeamID, a.slack.TeamDomain); err != nil {
+			slog.Warn("failed to record workspace domain", "error", err)
+		}
+	}
 	a.populateWorkspacePicker()
+	a.connectWorkspaces()
 
 	channelNames := make(map[string]string, len(channels))
 	for _, ch := range channels {
@@ -907,7 +1027,8 @@ func (a *App) fetchInitialData() {
 		a.chatView.ChannelsPicker.SetData(channels, userMap, a.slack.UserID)
 		a.chatView.MentionsList.SetUsers(userMap)
 		a.chatView.MentionsList.SetChannels(channels, userMap, a.slack.UserID)
-		a.chatView.MentionsList.SetCommands(chat.BuiltinCommandEntries())
+		a.chatView.MentionsList.SetCommands(chat.CommandEntries(a.Config))
+		a.chatView.SearchPicker.SetCompletions(searchChannelNames(channels), searchUserNames(users))
 		a.chatView.MessagesList.SetSelfUserID(a.slack.UserID)
 		a.chatView.MessagesList.SetSelfTeamID(a.slack.TeamID)
 		a.chatView.ThreadView.SetSelfTeamID(a.slack.TeamID)
@@ -917,16 +1038,29 @@ func (a *App) fetchInitialData() {
 		for _, ch := range channels {
 			if ch.UnreadCountDisplay > 0 {
 				a.chatView.ChannelsTree.SetUnreadCount(ch.ID, ch.UnreadCountDisplay)
+				// Every unread DM counts as a mention.
+				if ch.IsIM {
+					a.chatView.ChannelsTree.SetMentionCount(ch.ID, ch.UnreadCountDisplay)
+				}
 			}
 		}
 		a.chatView.StatusBar.SetConnectionStatus(
 			fmt.Sprintf("%s (%s) — connected (%d channels, %d users)",
 				a.slack.UserName, a.slack.TeamName, len(channels), len(users)))
 	})
+
+	a.openPendingPermalink()
 }
 
 // onChannelSelected is called when the user selects a channel in the tree.
 func (a *App) onChannelSelected(channelID string) {
+	a.enterChannel(channelID)
+	go a.loadMessages(channelID)
+}
+
+// enterChannel makes channelID the current channel and updates the header,
+// input and status bar. It does not load any messages.
+func (a *App) enterChannel(channelID string) {
 	// Close thread if open when switching channels.
 	if a.chatView.ThreadView.IsOpen() {
 		a.chatView.CloseThread()
@@ -949,14 +1083,13 @@ func (a *App) onChannelSelected(channelID string) {
 	if a.typingTracker != nil {
 		a.chatView.StatusBar.SetTypingIndicator("")
 	}
-	go a.loadMessages(channelID)
 }
 
 // loadMessages fetches conversation history and updates the messages list.
 func (a *App) loadMessages(channelID string) {
 	resp, err := a.slack.GetConversationHistory(&slack.GetConversationHistoryParameters{
 		ChannelID: channelID,
-		Limit:     a.Config.MessagesLimit,
+		Limit:     a.config().MessagesLimit,
 	})
 	if err != nil {
 		slog.Error("failed to fetch messages", "channel", channelID, "error", err)
@@ -969,6 +1102,7 @@ func (a *App) loadMessages(channelID string) {
 
 	a.tview.QueueUpdateDraw(func() {
 		a.chatView.MessagesList.SetMessages(channelID, resp.Messages, users)
+		a.chatView.MessagesList.SetPaging(resp.HasMore, false)
 		a.updateChannelPresence(channelID, resp.Messages, users)
 	})
 
@@ -1188,58 +1322,9 @@ func (a *App) loadStarredItems() {
 	})
 }
 
-// searchMessages searches Slack messages and updates the search picker with results.
-func (a *App) searchMessages(query string) {
-	results, err := a.slack.SearchMessages(query, slack.SearchParameters{
-		Count:         20,
-		Sort:          "timestamp",
-		SortDirection: "desc",
-	})
-	if err != nil {
-		slog.Error("failed to search messages", "query", query, "error", err)
-		a.tview.QueueUpdateDraw(func() {
-			a.chatView.SearchPicker.SetStatus("Search failed")
-		})
-		return
-	}
-
-	a.mu.Lock()
-	users := a.users
-	a.mu.Unlock()
-
-	entries := make([]chat.SearchResultEntry, 0, len(results.Matches))
-	for _, m := range results.Matches {
-		userName := m.Username
-		if userName == "" {
-			if u, ok := users[m.User]; ok {
-				if u.Profile.DisplayName != "" {
-					userName = u.Profile.DisplayName
-				} else if u.RealName != "" {
-					userName = u.RealName
-				} else {
-					userName = u.Name
-				}
-			}
-		}
-
-		entries = append(entries, chat.SearchResultEntry{
-			ChannelID:   m.Channel.ID,
-			ChannelName: m.Channel.Name,
-			UserName:    userName,
-			Timestamp:   m.Timestamp,
-			Text:        m.Text,
-		})
-	}
-
-	a.tview.QueueUpdateDraw(func() {
-		a.chatView.SearchPicker.SetResults(entries)
-		a.chatView.SearchPicker.SetStatus(fmt.Sprintf("%d results", results.Total))
-	})
-}
-
 // updateChannelPresence counts online users from the given messages and updates the status bar.
 func (a *App) updateChannelPresence(channelID string, messages []slack.Message, users map[string]slack.User) {
-	if !a.Config.Presence.Enabled {
+	if !a.config().Presence.Enabled {
 		a.chatView.StatusBar.SetChannelPresence(0, 0)
 		return
 	}
@@ -1289,6 +1374,10 @@ func (a *App) sendMeMessage(channelID, text string) {
 
 // onThreadReplySend handles sending a reply in the thread view.
 func (a *App) onThreadReplySend(channelID, text, threadTS string) {
+	// Custom commands typed in the thread run with the thread's timestamp.
+	if command, args := chat.ParseSlashCommand(text); command != "" && a.runCustomCommand(command, channelID, threadTS, args) {
+		return
+	}
 	go func() {
 		opts := []slack.MsgOption{
 			slack.MsgOptionText(text, false),
@@ -1336,7 +1425,7 @@ func (a *App) onMessageEdit(channelID, timestamp, text string) {
 
 // maybeNotify sends a desktop notification if the message warrants one.
 func (a *App) maybeNotify(evt *slackevents.MessageEvent) {
-	if !a.Config.Notifications.Enabled {
+	if !a.config().Notifications.Enabled {
 		return
 	}
 	// Never notify for own messages.
@@ -1378,6 +1467,11 @@ func (a *App) maybeNotify(evt *slackevents.MessageEvent) {
 		title = fmt.Sprintf("%s in channel", sender)
 	}
 
+	// Say which workspace it is from when several are connected.
+	if len(a.workspaces.all()) > 1 {
+		title = a.slack.TeamName + ": " + title
+	}
+
 	a.notifier.Send(title, body)
 }
 
@@ -1428,7 +1522,7 @@ func (a *App) openFile(file slack.File) {
 	})
 
 	// Ensure download directory exists.
-	downloadDir := a.Config.DownloadDir
+	downloadDir := a.config().DownloadDir
 	if err := os.MkdirAll(downloadDir, 0o755); err != nil {
 		slog.Error("failed to create download dir", "dir", downloadDir, "error", err)
 		return
@@ -1695,11 +1789,9 @@ func (a *App) executeSlashCommand(channelID, command, args string) {
 	case "leave":
 		go a.leaveChannel(channelID)
 	case "search":
+		a.chatView.ShowSearchPicker()
 		if args != "" {
-			a.chatView.ShowSearchPicker()
-			go a.searchMessages(args)
-		} else {
-			a.chatView.ShowSearchPicker()
+			a.chatView.SearchPicker.SetQuery(args)
 		}
 	case "who":
 		a.chatView.ShowMembersPicker()
@@ -1732,6 +1824,13 @@ func (a *App) executeSlashCommand(channelID, command, args string) {
 	case "logout":
 		a.logout()
 	default:
+		threadTS := ""
+		if a.chatView.MessageInput.Mode() == chat.InputModeReply {
+			threadTS = a.chatView.MessageInput.ThreadTS()
+		}
+		if a.runCustomCommand(command, channelID, threadTS, args) || a.runAlias(command, channelID, args) {
+			return
+		}
 		a.showCommandFeedback("Unknown command: /" + command)
 	}
 }
@@ -1744,6 +1843,12 @@ func (a *App) formatHelpText() string {
 	for _, cmd := range cmds {
 		b.WriteString(fmt.Sprintf("  %s — %s", cmd.Usage, cmd.Description))
 	}
+	for _, cmd := range a.Config.Commands {
+		b.WriteString(fmt.Sprintf("  /%s — %s", cmd.Name, chat.CustomCommandDescription(cmd)))
+	}
+	for _, name := range slices.Sorted(maps.Keys(a.Config.Aliases)) {
+		b.WriteString(fmt.Sprintf("  /%s — %s", name, strings.Join(a.Config.Aliases[name], "; ")))
+	}
 	return b.String()
 }
 
@@ -2077,11 +2182,7 @@ func (a *App) executeVimCommand(command, args string) {
 	case "q":
 		a.shutdown()
 	case "theme":
-		if args == "" {
-			a.showCommandFeedback("Usage: :theme [name] (default, dark, light, monokai, solarized_dark, solarized_light, high_contrast, monochrome)")
-		} else {
-			a.showCommandFeedback("Theme switching requires restart. Set theme.preset = \"" + args + "\" in config.")
-		}
+		a.cmdTheme(args)
 	case "join":
 		if args == "" {
 			a.showCommandFeedback("Usage: :join #channel")
@@ -2097,11 +2198,9 @@ func (a *App) executeVimCommand(command, args string) {
 			go a.leaveChannel(ch)
 		}
 	case "search":
+		a.chatView.ShowSearchPicker()
 		if args != "" {
-			a.chatView.ShowSearchPicker()
-			go a.searchMessages(args)
-		} else {
-			a.chatView.ShowSearchPicker()
+			a.chatView.SearchPicker.SetQuery(args)
 		}
 	case "mark-read":
 		a.mu.Lock()
@@ -2121,52 +2220,63 @@ func (a *App) executeVimCommand(command, args string) {
 		}()
 	case "open":
 		if args == "" {
-			a.showCommandFeedback("Usage: :open [url]")
+			a.showCommandFeedback("Usage: :open [url|permalink]")
 			return
 		}
-		go a.openURL(args)
+		go a.openLink(args)
 	case "reconnect":
 		a.showCommandFeedback("Reconnecting...")
 		if a.cancel != nil {
 			a.cancel()
 		}
-		go a.showMain()
+		a.tview.QueueUpdateDraw(a.showMain)
 	case "debug":
 		go a.toggleDebugLogging()
+	case "source":
+		a.cmdSource(args)
 	case "set":
 		if args == "" {
 			a.showCommandFeedback(ListRuntimeOptions(a.Config))
 			return
 		}
 
-		option, value, query, err := ParseSetCommand(args)
+		cmd, err := ParseSetCommand(args)
 		if err != nil {
 			a.showCommandFeedback("Error: " + err.Error())
 			return
 		}
 
-		if query {
-			msg, err := QueryOption(a.Config, option)
-			if err != nil {
-				a.showCommandFeedback("Error: " + err.Error())
-			} else {
-				a.showCommandFeedback(msg)
-			}
-			return
-		}
-
-		msg, err := ApplySetCommand(a.Config, option, value)
+		var msg string
+		a.updateConfig(func(c *config.Config) {
+			msg, err = ApplySetCommand(c, cmd)
+		})
 		if err != nil {
 			a.showCommandFeedback("Error: " + err.Error())
 			return
 		}
 		a.showCommandFeedback(msg)
+		if cmd.Op == SetQuery {
+			return
+		}
 
 		// Apply side effects for options that need immediate action.
-		switch option {
+		opt, _ := findOption(cmd.Option)
+		switch opt.Name {
 		case "mouse":
 			a.tview.EnableMouse(a.Config.Mouse)
-		}
+		case "theme":
+			a.applyTheme(a.Config.Theme.Preset)
+		case "timestamps", "timestamp_format", "markdown", "syntax_theme", "date_separator":
+			a.chatView.ApplyConfig()
+		}
+	case "mkconfig":
+		a.cmdWriteConfig()
+	case "export":
+		a.cmdExport(args)
+	case "activity":
+		a.chatView.ShowActivityPanel()
+		a.chatView.ActivityPanel.SetStatus("Loading...")
+		go a.loadActivity()
 	case "bookmarks":
 		a.mu.Lock()
 		ch := a.currentChannel
@@ -2179,10 +2289,7 @@ func (a *App) executeVimCommand(command, args string) {
 		a.chatView.BookmarksPicker.SetStatus("Loading...")
 		go a.loadChannelBookmarks(ch)
 	case "workspace":
-		a.populateWorkspacePicker()
-		a.tview.QueueUpdateDraw(func() {
-			a.chatView.ShowWorkspacePicker()
-		})
+		a.cmdWorkspace(args)
 	case "members":
 		a.mu.Lock()
 		ch := a.currentChannel
@@ -2208,6 +2315,9 @@ func (a *App) executeVimCommand(command, args string) {
 	case "logout":
 		a.logout()
 	default:
+		if a.runAlias(command, "", args) {
+			return
+		}
 		a.showCommandFeedback("Unknown command: :" + command)
 	}
 }
@@ -2380,86 +2490,6 @@ func (a *App) createGroupDM(userIDs []string) {
 	a.showCommandFeedback("Group DM created with " + strings.Join(names, ", "))
 }
 
-// populateWorkspacePicker refreshes the workspace picker with stored workspaces.
-func (a *App) populateWorkspacePicker() {
-	ws, err := keyring.ListWorkspaces()
-	if err != nil {
-		slog.Warn("failed to list workspaces", "error", err)
-		return
-	}
-
-	entries := make([]chat.WorkspaceEntry, len(ws))
-	for i, w := range ws {
-		entries[i] = chat.WorkspaceEntry{
-			ID:   w.ID,
-			Name: w.Name,
-		}
-	}
-
-	a.tview.QueueUpdateDraw(func() {
-		a.chatView.WorkspacePicker.SetCurrentWorkspace(a.slack.TeamID)
-		a.chatView.WorkspacePicker.SetWorkspaces(entries)
-	})
-}
-
-// switchWorkspace disconnects from the current workspace and connects to a new one.
-func (a *App) switchWorkspace(workspaceID string) {
-	ws, err := keyring.ListWorkspaces()
-	if err != nil {
-		slog.Error("failed to list workspaces", "error", err)
-		a.showCommandFeedback("Failed to list workspaces")
-		return
-	}
-
-	var target keyring.Workspace
-	found := false
-	for _, w := range ws {
-		if w.ID == workspaceID {
-			target = w
-			found = true
-			break
-		}
-	}
-	if !found {
-		a.showCommandFeedback("Workspace not found: " + workspaceID)
-		return
-	}
-
-	tokens, err := keyring.GetWorkspaceTokens(target)
-	if err != nil {
-		slog.Error("failed to get workspace tokens", "workspace", target.Name, "error", err)
-		a.showCommandFeedback("Failed to get tokens for " + target.Name)
-		return
-	}
-
-	// Disconnect current.
-	if a.cancel != nil {
-		a.cancel()
-	}
-
-	// Create new client.
-	client, err := slackclient.New(tokens.UserToken, tokens.AppToken)
-	if err != nil {
-		slog.Error("failed to create client for workspace", "workspace", target.Name, "error", err)
-		a.showCommandFeedback("Failed to connect to " + target.Name)
-		return
-	}
-
-	a.slack = client
-	a.mu.Lock()
-	a.currentChannel = ""
-	a.channels = nil
-	a.users = make(map[string]slack.User)
-	a.dmSet = make(map[string]bool)
-	a.lastRead = make(map[string]string)
-	a.pinnedMsgs = make(map[string]map[string]bool)
-	a.mu.Unlock()
-
-	a.tview.QueueUpdateDraw(func() {
-		a.showMain()
-	})
-}
-
 // fetchAllChannels retrieves all conversations with pagination.
 func (a *App) fetchAllChannels() ([]slack.Channel, error) {
 	var all []slack.Channel
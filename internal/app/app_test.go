@@ -10,7 +10,7 @@ import (
 
 func TestHandleGlobalKey_QuitConsumed(t *testing.T) {
 	cfg := &config.Config{}
-	cfg.Keybinds.Quit = "Ctrl+C"
+	cfg.Keybinds.Quit = config.Keybind{"Ctrl+C"}
 
 	a := &App{
 		Config: cfg,
@@ -46,7 +46,7 @@ func TestHandleGlobalKey_QuitConsumed(t *testing.T) {
 
 func TestHandleGlobalKey_NonQuitPassesThrough(t *testing.T) {
 	cfg := &config.Config{}
-	cfg.Keybinds.Quit = "Ctrl+C"
+	cfg.Keybinds.Quit = config.Keybind{"Ctrl+C"}
 
 	app := newTestApp()
 	a := &App{
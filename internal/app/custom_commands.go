@@ -0,0 +1,124 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/m96-chan/Slacko/internal/config"
+)
+
+// commandWaitDelay bounds how long a custom command's output pipes are
+// drained after it exits or is killed, e.g. when it left a child running.
+const commandWaitDelay = time.Second
+
+// runCustomCommand runs the custom command name in channelID with the typed
+// args, returning false when no such command exists. threadTS is the thread
+// the command was typed in, if any; "send" mode output is posted there. The
+// executable runs in the background; its output is handled according to the
+// command's mode. Must be called from the tview event loop.
+func (a *App) runCustomCommand(name, channelID, threadTS, args string) bool {
+	cmd, ok := a.Config.FindCommand(name)
+	if !ok {
+		return false
+	}
+	env := a.customCommandEnv(cmd, channelID, threadTS, args)
+
+	go func() {
+		out, err := execCustomCommand(cmd, args, env)
+		if err != nil {
+			slog.Warn("custom command failed", "command", cmd.Name, "error", err)
+			a.showCommandFeedback(fmt.Sprintf("/%s: %s", cmd.Name, err))
+			return
+		}
+		if out == "" {
+			a.showCommandFeedback(fmt.Sprintf("/%s: no output", cmd.Name))
+			return
+		}
+
+		switch cmd.OutputMode() {
+		case config.CommandModeSend:
+			a.onMessageSend(channelID, out, threadTS)
+		case config.CommandModeFeedback:
+			a.showCommandFeedback(strings.ReplaceAll(out, "\n", "  "))
+		default:
+			a.tview.QueueUpdateDraw(func() { a.showCommandPreview(out, threadTS) })
+		}
+	}()
+	return true
+}
+
+// showCommandPreview puts the output of a custom command into the input it
+// was typed in, for the user to edit and send: the thread view's reply input
+// when threadTS is the open thread, the message input otherwise. Must be
+// called from the tview event loop.
+func (a *App) showCommandPreview(out, threadTS string) {
+	if threadTS != "" && a.chatView.ThreadView.IsOpen() && a.chatView.ThreadView.ThreadTS() == threadTS {
+		a.chatView.ThreadView.SetReplyText(out)
+		return
+	}
+	a.chatView.MessageInput.SetText(out, true)
+	a.tview.SetFocus(a.chatView.MessageInput)
+}
+
+// customCommandEnv returns the environment for a custom command: the
+// process environment plus SLACKO_* variables describing the channel, the
+// thread being replied to and the selected message.
+func (a *App) customCommandEnv(cmd config.CustomCommand, channelID, threadTS, args string) []string {
+	vars := map[string]string{
+		"SLACKO_COMMAND":    cmd.Name,
+		"SLACKO_ARGS":       args,
+		"SLACKO_CHANNEL_ID": channelID,
+		"SLACKO_THREAD_TS":  threadTS,
+	}
+	if a.slack != nil {
+		vars["SLACKO_TEAM_ID"] = a.slack.TeamID
+		vars["SLACKO_USER_ID"] = a.slack.UserID
+	}
+	if msg, ok := a.chatView.MessagesList.SelectedMessage(); ok {
+		vars["SLACKO_MESSAGE_TS"] = msg.Timestamp
+		vars["SLACKO_MESSAGE_USER"] = msg.User
+		vars["SLACKO_MESSAGE_TEXT"] = msg.Text
+	}
+
+	env := os.Environ()
+	for k, v := range vars {
+		env = append(env, k+"="+v)
+	}
+	return env
+}
+
+// execCustomCommand runs cmd with args appended to its configured arguments
+// and returns its stdout without trailing newlines. A non-zero exit status
+// is reported with the first line of stderr.
+func execCustomCommand(cmd config.CustomCommand, args string, env []string) (string, error) {
+	timeout := cmd.TimeoutDuration()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	argv := append(append([]string{}, cmd.Args...), strings.Fields(args)...)
+	c := exec.CommandContext(ctx, cmd.Exec, argv...)
+	c.Env = env
+	c.WaitDelay = commandWaitDelay
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	err := c.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		if msg, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m96-chan/Slacko/internal/config"
+)
+
+func shellCommand(t *testing.T, script string) config.CustomCommand {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	return config.CustomCommand{Name: "test", Exec: "sh", Args: []string{"-c", script, "sh"}}
+}
+
+func TestExecCustomCommandArgsAndEnv(t *testing.T) {
+	cmd := shellCommand(t, `printf '%s|%s|%s\n\n' "$1" "$2" "$SLACKO_CHANNEL_ID"`)
+
+	out, err := execCustomCommand(cmd, " ABC-123  extra ", []string{"SLACKO_CHANNEL_ID=C1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "ABC-123|extra|C1" {
+		t.Errorf("output = %q, want %q", out, "ABC-123|extra|C1")
+	}
+}
+
+func TestExecCustomCommandReportsStderr(t *testing.T) {
+	cmd := shellCommand(t, "echo 'no such issue' >&2; echo more >&2; exit 3")
+
+	_, err := execCustomCommand(cmd, "", nil)
+	if err == nil || !strings.Contains(err.Error(), "exit status 3: no such issue") {
+		t.Errorf("error = %v, want exit status with first stderr line", err)
+	}
+}
+
+func TestExecCustomCommandTimeout(t *testing.T) {
+	cmd := shellCommand(t, "exec sleep 5")
+	cmd.Timeout = 50
+
+	_, err := execCustomCommand(cmd, "", nil)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Errorf("error = %v, want timeout", err)
+	}
+}
+
+func TestCustomCommandEnv(t *testing.T) {
+	a := newThemeTestApp(t, "")
+
+	env := a.customCommandEnv(config.CustomCommand{Name: "jira"}, "C1", "111.222", "ABC-1")
+	for _, want := range []string{
+		"SLACKO_COMMAND=jira",
+		"SLACKO_ARGS=ABC-1",
+		"SLACKO_CHANNEL_ID=C1",
+		"SLACKO_THREAD_TS=111.222",
+	} {
+		if !slices.Contains(env, want) {
+			t.Errorf("env is missing %s", want)
+		}
+	}
+}
+
+func TestRunCustomCommandUnknown(t *testing.T) {
+	a := newThemeTestApp(t, "")
+
+	if a.runCustomCommand("nope", "C1", "", "") {
+		t.Error("runCustomCommand reported an undefined command as found")
+	}
+}
+
+func TestThreadReplyRunsCustomCommand(t *testing.T) {
+	a := newThemeTestApp(t, "")
+	out := filepath.Join(t.TempDir(), "env")
+	cmd := shellCommand(t, `printf '%s %s %s' "$SLACKO_CHANNEL_ID" "$SLACKO_THREAD_TS" "$1" > "$0"`)
+	cmd.Name, cmd.Args[2], cmd.Mode = "note", out, config.CommandModeFeedback
+	a.Config.Commands = []config.CustomCommand{cmd}
+
+	// The command runs instead of being posted, with the thread's timestamp.
+	a.onThreadReplySend("C1", "/note hello", "111.222")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(out)
+		if err == nil && len(data) > 0 {
+			if got := string(data); got != "C1 111.222 hello" {
+				t.Errorf("command saw %q, want %q", got, "C1 111.222 hello")
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("custom command did not run")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCommandPreviewInThread(t *testing.T) {
+	a := newThemeTestApp(t, "")
+	a.chatView.ThreadView.SetMessages("C1", "111.222", nil, nil)
+
+	// Output of a command typed in the thread goes to the thread's input.
+	a.showCommandPreview("LGTM", "111.222")
+	if got := a.chatView.MessageInput.GetText(); got != "" {
+		t.Errorf("message input = %q, want the preview in the thread", got)
+	}
+	if !a.chatView.ThreadView.IsInputFocused() {
+		t.Error("thread reply input not focused")
+	}
+
+	a.showCommandPreview("hello", "")
+	if got := a.chatView.MessageInput.GetText(); got != "hello" {
+		t.Errorf("message input = %q, want %q", got, "hello")
+	}
+}
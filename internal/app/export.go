@@ -0,0 +1,130 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/m96-chan/Slacko/internal/export"
+)
+
+// exportRequest holds the parsed arguments of :export.
+type exportRequest struct {
+	thread bool // export the open thread instead of the channel
+	format export.Format
+	since  string
+	until  string
+	files  bool   // download attachments
+	path   string // output path; empty means a default in the download dir
+}
+
+// parseExportArgs parses ":export [thread] [json|markdown|html]
+// [since:DATE] [until:DATE] [files] [path]".
+func parseExportArgs(args string) (exportRequest, error) {
+	req := exportRequest{format: export.FormatMarkdown}
+	for _, tok := range strings.Fields(args) {
+		switch {
+		case tok == "thread":
+			req.thread = true
+		case tok == "files":
+			req.files = true
+		case strings.HasPrefix(tok, "since:"):
+			req.since = strings.TrimPrefix(tok, "since:")
+		case strings.HasPrefix(tok, "until:"):
+			req.until = strings.TrimPrefix(tok, "until:")
+		default:
+			if f, err := export.ParseFormat(tok); err == nil {
+				req.format = f
+				continue
+			}
+			if req.path != "" {
+				return exportRequest{}, fmt.Errorf("unexpected argument %q", tok)
+			}
+			req.path = expandHome(tok)
+		}
+	}
+	return req, nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// cmdExport exports the current channel, or the open thread, in the
+// background and reports the result in the command bar.
+func (a *App) cmdExport(args string) {
+	req, err := parseExportArgs(args)
+	if err != nil {
+		a.showCommandFeedback("Usage: :export [thread] [json|markdown|html] [since:DATE] [until:DATE] [files] [path]")
+		return
+	}
+	r, err := export.ParseRange(req.since, req.until)
+	if err != nil {
+		a.showCommandFeedback("Error: " + err.Error())
+		return
+	}
+
+	a.mu.Lock()
+	channelID := a.currentChannel
+	name := a.channelLabel(channelID)
+	names := export.Names{Users: a.users, Channels: make(map[string]string, len(a.channels))}
+	for _, ch := range a.channels {
+		names.Channels[ch.ID] = ch.Name
+	}
+	a.mu.Unlock()
+
+	if channelID == "" {
+		a.showCommandFeedback("No channel selected")
+		return
+	}
+
+	threadTS := ""
+	if req.thread {
+		if !a.chatView.ThreadView.IsOpen() {
+			a.showCommandFeedback("No thread open")
+			return
+		}
+		channelID = a.chatView.ThreadView.ChannelID()
+		threadTS = a.chatView.ThreadView.ThreadTS()
+	}
+
+	path := req.path
+	if path == "" {
+		path = export.DefaultPath(a.Config.DownloadDir, name, req.format, time.Now())
+	}
+
+	a.showCommandFeedback("Exporting " + name + "...")
+	go func() {
+		conv, err := export.Collect(a.slack, channelID, threadTS, r)
+		if err != nil {
+			slog.Error("export failed", "channel", channelID, "error", err)
+			a.showCommandFeedback("Export failed: " + err.Error())
+			return
+		}
+		conv.Name = name
+
+		var dl export.Downloader
+		if req.files {
+			dl = a.slack
+		}
+		written, err := export.Write(conv, req.format, path, names, dl)
+		if err != nil {
+			slog.Error("export failed", "path", path, "error", err)
+			a.showCommandFeedback("Export failed: " + err.Error())
+			return
+		}
+		slog.Info("exported conversation", "channel", channelID, "path", written)
+		a.showCommandFeedback("Exported to " + written)
+	}()
+}
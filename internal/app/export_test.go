@@ -0,0 +1,34 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/m96-chan/Slacko/internal/export"
+)
+
+func TestParseExportArgs(t *testing.T) {
+	req, err := parseExportArgs("thread html since:2026-01-01 until:2026-02-01 files /tmp/out.html")
+	if err != nil {
+		t.Fatalf("parseExportArgs: %v", err)
+	}
+	want := exportRequest{
+		thread: true,
+		format: export.FormatHTML,
+		since:  "2026-01-01",
+		until:  "2026-02-01",
+		files:  true,
+		path:   "/tmp/out.html",
+	}
+	if req != want {
+		t.Errorf("parseExportArgs = %+v, want %+v", req, want)
+	}
+
+	req, err = parseExportArgs("")
+	if err != nil || req.format != export.FormatMarkdown || req.path != "" {
+		t.Errorf("defaults = %+v, %v", req, err)
+	}
+
+	if _, err := parseExportArgs("a.md b.md"); err == nil {
+		t.Error("two paths should fail")
+	}
+}
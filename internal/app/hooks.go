@@ -0,0 +1,31 @@
+package app
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/slack-go/slack"
+
+	"github.com/m96-chan/Slacko/internal/hooks"
+)
+
+// runHookAction performs an action returned by an event hook. Called from
+// the hook's goroutine.
+func (a *App) runHookAction(act hooks.Action) {
+	slog.Debug("running hook action", "type", act.Type, "channel", act.Channel)
+	switch act.Type {
+	case hooks.ActionSend:
+		a.onMessageSend(act.Channel, act.Text, act.ThreadTS)
+	case hooks.ActionReact:
+		name := strings.Trim(act.Name, ":")
+		if err := a.slack.AddReaction(name, slack.NewRefToMessage(act.Channel, act.Timestamp)); err != nil {
+			slog.Error("failed to add hook reaction", "channel", act.Channel, "error", err)
+		}
+	case hooks.ActionNotify:
+		title := act.Title
+		if title == "" {
+			title = "Slacko"
+		}
+		a.notifier.Send(title, act.Text)
+	}
+}
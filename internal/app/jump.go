@@ -0,0 +1,182 @@
+package app
+
+import (
+	"log/slog"
+
+	"github.com/slack-go/slack"
+)
+
+// jumpToMessage switches to channelID and loads history around ts so that the
+// target message is visible with context on both sides, even if it is far
+// outside the latest page. threadTS is the parent timestamp when the target is
+// known to be a thread reply; pass "" to have it discovered.
+func (a *App) jumpToMessage(channelID, ts, threadTS string) {
+	a.enterChannel(channelID)
+	go a.loadMessagesAround(channelID, ts, threadTS)
+}
+
+// contextPageSize returns how many messages to load on each side of a jump
+// target.
+func (a *App) contextPageSize() int {
+	n := a.config().MessagesLimit / 2
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// loadMessagesAround fetches history on both sides of ts, selects the target
+// and, for thread replies, opens the parent thread with the reply highlighted.
+func (a *App) loadMessagesAround(channelID, ts, threadTS string) {
+	anchor := ts
+	if threadTS != "" && threadTS != ts {
+		anchor = threadTS
+	}
+
+	older, newer, hasOlder, hasNewer, err := a.fetchAround(channelID, anchor)
+	if err != nil {
+		slog.Error("failed to fetch messages around timestamp", "channel", channelID, "ts", anchor, "error", err)
+		a.showCommandFeedback("Failed to load message: " + err.Error())
+		return
+	}
+
+	// The target is not a top-level message: it may be a thread reply whose
+	// parent we did not know about. Look it up and re-anchor on the parent.
+	if threadTS == "" && (len(older) == 0 || older[0].Timestamp != ts) {
+		if parent := a.findThreadParent(channelID, ts); parent != "" && parent != ts {
+			threadTS = parent
+			anchor = parent
+			older, newer, hasOlder, hasNewer, err = a.fetchAround(channelID, anchor)
+			if err != nil {
+				slog.Error("failed to fetch messages around thread parent", "channel", channelID, "ts", anchor, "error", err)
+				a.showCommandFeedback("Failed to load message: " + err.Error())
+				return
+			}
+		}
+	}
+
+	// Combine into a single newest-first slice as returned by the API.
+	msgs := make([]slack.Message, 0, len(newer)+len(older))
+	msgs = append(msgs, newer...)
+	msgs = append(msgs, older...)
+
+	a.mu.Lock()
+	users := a.users
+	a.mu.Unlock()
+
+	a.tview.QueueUpdateDraw(func() {
+		a.chatView.MessagesList.SetMessages(channelID, msgs, users)
+		a.chatView.MessagesList.SetPaging(hasOlder, hasNewer)
+		if !a.chatView.MessagesList.SelectTimestamp(anchor) {
+			a.showCommandFeedback("Message not found (it may have been deleted)")
+		}
+		a.updateChannelPresence(channelID, msgs, users)
+		if threadTS != "" {
+			a.chatView.OpenThread()
+		}
+	})
+
+	if threadTS != "" {
+		a.loadThread(channelID, threadTS)
+		if ts != threadTS {
+			a.tview.QueueUpdateDraw(func() {
+				a.chatView.ThreadView.SelectTimestamp(ts)
+			})
+		}
+	}
+
+	// Only mark as read when the loaded window reaches the latest message.
+	if !hasNewer && len(msgs) > 0 {
+		a.markChannelRead(channelID, msgs[0].Timestamp)
+	}
+
+	go a.fetchChannelPins(channelID)
+}
+
+// fetchAround loads up to contextPageSize messages at or before ts and up to
+// contextPageSize messages after it. Both slices are newest-first.
+func (a *App) fetchAround(channelID, ts string) (older, newer []slack.Message, hasOlder, hasNewer bool, err error) {
+	n := a.contextPageSize()
+
+	before, err := a.slack.GetConversationHistory(&slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Latest:    ts,
+		Inclusive: true,
+		Limit:     n,
+	})
+	if err != nil {
+		return nil, nil, false, false, err
+	}
+
+	// With only Oldest set, Slack returns the messages immediately after it.
+	after, err := a.slack.GetConversationHistory(&slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Oldest:    ts,
+		Inclusive: false,
+		Limit:     n,
+	})
+	if err != nil {
+		return nil, nil, false, false, err
+	}
+
+	return before.Messages, after.Messages, before.HasMore, after.HasMore, nil
+}
+
+// findThreadParent returns the parent timestamp of the thread containing ts,
+// or "" if ts is not part of a thread.
+func (a *App) findThreadParent(channelID, ts string) string {
+	msgs, _, _, err := a.slack.GetConversationReplies(&slack.GetConversationRepliesParameters{
+		ChannelID: channelID,
+		Timestamp: ts,
+		Limit:     1,
+	})
+	if err != nil || len(msgs) == 0 {
+		slog.Debug("no thread found for message", "channel", channelID, "ts", ts, "error", err)
+		return ""
+	}
+	if msgs[0].ThreadTimestamp != "" {
+		return msgs[0].ThreadTimestamp
+	}
+	return msgs[0].Timestamp
+}
+
+// loadHistoryPage loads the next page of history before (older) or after
+// edgeTS and extends the messages list in that direction.
+func (a *App) loadHistoryPage(channelID, edgeTS string, older bool) {
+	params := &slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Limit:     a.config().MessagesLimit,
+	}
+	if older {
+		params.Latest = edgeTS
+	} else {
+		params.Oldest = edgeTS
+	}
+
+	resp, err := a.slack.GetConversationHistory(params)
+	if err != nil {
+		slog.Error("failed to fetch history page", "channel", channelID, "older", older, "error", err)
+		a.tview.QueueUpdateDraw(func() {
+			// Clear the in-flight flag so the user can retry.
+			if older {
+				a.chatView.MessagesList.PrependHistory(channelID, nil, true)
+			} else {
+				a.chatView.MessagesList.AppendHistory(channelID, nil, true)
+			}
+		})
+		a.showCommandFeedback("Failed to load more messages: " + err.Error())
+		return
+	}
+
+	a.tview.QueueUpdateDraw(func() {
+		if older {
+			a.chatView.MessagesList.PrependHistory(channelID, resp.Messages, resp.HasMore)
+		} else {
+			a.chatView.MessagesList.AppendHistory(channelID, resp.Messages, resp.HasMore)
+		}
+	})
+
+	if !older && !resp.HasMore && len(resp.Messages) > 0 {
+		a.markChannelRead(channelID, resp.Messages[0].Timestamp)
+	}
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/m96-chan/Slacko/internal/keyring"
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
+)
+
+// pendingPermalink is a permalink waiting for initial data (or a workspace
+// switch) to complete before it can be opened.
+type pendingPermalink struct {
+	link     string
+	switched bool // a workspace switch was already attempted for this link
+}
+
+// OpenOnStart makes the app navigate to the given permalink as soon as the
+// workspace data has loaded.
+func (a *App) OpenOnStart(link string) {
+	a.mu.Lock()
+	a.pendingLink = &pendingPermalink{link: link}
+	a.mu.Unlock()
+}
+
+// openLink opens a URL, navigating in-app when it is a Slack permalink and
+// falling back to the system browser otherwise.
+func (a *App) openLink(link string) {
+	if slackclient.IsPermalink(link) {
+		a.openPermalink(link, false)
+		return
+	}
+	a.openURL(link)
+}
+
+// openPermalink navigates to the message referenced by a Slack permalink.
+// Links to another registered workspace switch to it first; links to
+// unknown workspaces are opened in the browser.
+func (a *App) openPermalink(link string, switched bool) {
+	p, err := slackclient.ParsePermalink(link)
+	if err != nil {
+		a.showCommandFeedback("Error: " + err.Error())
+		return
+	}
+
+	if p.TeamDomain == "" || a.slack.TeamDomain == "" || strings.EqualFold(p.TeamDomain, a.slack.TeamDomain) {
+		a.tview.QueueUpdateDraw(func() {
+			a.jumpToMessage(p.ChannelID, p.Timestamp, p.ThreadTS)
+		})
+		return
+	}
+
+	w, ok := keyring.FindWorkspaceByDomain(p.TeamDomain)
+	if !ok || switched {
+		slog.Info("permalink for unregistered workspace, opening in browser", "domain", p.TeamDomain)
+		a.showCommandFeedback("Workspace " + p.TeamDomain + " is not registered; opening in browser")
+		a.openURL(link)
+		return
+	}
+
+	a.showCommandFeedback("Switching to " + w.Name + "...")
+	if target := a.switchWorkspace(w.ID); target != nil {
+		target.openWhenLoaded(link)
+	}
+}
+
+// openWhenLoaded opens a permalink to a's workspace after a switch: right
+// away when its data has loaded, otherwise once it has.
+func (a *App) openWhenLoaded(link string) {
+	a.mu.Lock()
+	loaded := a.channels != nil
+	if !loaded {
+		a.pendingLink = &pendingPermalink{link: link, switched: true}
+	}
+	a.mu.Unlock()
+	if loaded {
+		a.openPermalink(link, true)
+	}
+}
+
+// openPendingPermalink opens a permalink queued by OpenOnStart or a
+// workspace switch. Called once initial data has loaded.
+func (a *App) openPendingPermalink() {
+	a.mu.Lock()
+	pending := a.pendingLink
+	a.pendingLink = nil
+	a.mu.Unlock()
+
+	if pending != nil {
+		a.openPermalink(pending.link, pending.switched)
+	}
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"log/slog"
+
+	"github.com/slack-go/slack"
+
+	"github.com/m96-chan/Slacko/internal/config"
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
+)
+
+// subscribeDMPresence asks for live presence updates about the users we
+// have DMs with. Only the RTM transport delivers them.
+func (a *App) subscribeDMPresence(channels []slack.Channel) {
+	if a.config().Transport != config.TransportRTM {
+		return
+	}
+	var ids []string
+	for _, ch := range channels {
+		if ch.IsIM && ch.User != "" {
+			ids = append(ids, ch.User)
+		}
+	}
+	if err := a.slack.SubscribePresence(ids); err != nil {
+		slog.Warn("failed to subscribe to presence", "error", err)
+	}
+}
+
+// onPresenceChange records a user's new presence and refreshes the views
+// that show presence dots.
+func (a *App) onPresenceChange(evt *slackclient.PresenceEvent) {
+	a.mu.Lock()
+	u, ok := a.users[evt.UserID]
+	if !ok || u.Presence == evt.Presence {
+		a.mu.Unlock()
+		return
+	}
+	u.Presence = evt.Presence
+	a.users[evt.UserID] = u
+	users := a.users
+	a.mu.Unlock()
+
+	if !a.config().Presence.Enabled {
+		return
+	}
+	a.tview.QueueUpdateDraw(func() {
+		a.chatView.ChannelsTree.UpdateUserPresence(evt.UserID, evt.Presence)
+		a.chatView.MessagesList.UpdateUsers(users)
+		a.chatView.MentionsList.SetUsers(users)
+		if a.chatView.ThreadView.IsOpen() {
+			a.chatView.ThreadView.UpdateUsers(users)
+		}
+	})
+}
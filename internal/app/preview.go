@@ -0,0 +1,112 @@
+package app
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/slack-go/slack"
+
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
+	"github.com/m96-chan/Slacko/internal/ui/chat"
+)
+
+// loadMessagePreview fetches the message referenced by a Slack permalink and
+// hands it to the messages list as an inline preview. Non-permalinks and links
+// to other workspaces are ignored; the messages list caches the result so each
+// link is only fetched once.
+func (a *App) loadMessagePreview(link string) {
+	p, err := slackclient.ParsePermalink(link)
+	if err != nil {
+		return
+	}
+	if p.TeamDomain != "" && a.slack.TeamDomain != "" && !strings.EqualFold(p.TeamDomain, a.slack.TeamDomain) {
+		return
+	}
+
+	msg, err := a.fetchMessage(p.ChannelID, p.Timestamp, p.ThreadTS)
+	if err != nil {
+		slog.Debug("failed to fetch linked message", "channel", p.ChannelID, "ts", p.Timestamp, "error", err)
+		return
+	}
+	if msg == nil {
+		return
+	}
+
+	a.mu.Lock()
+	preview := chat.MessagePreview{
+		Author:      userDisplayName(a.users, msg.User),
+		ChannelName: a.conversationLabel(p.ChannelID),
+		Timestamp:   msg.Timestamp,
+		Text:        msg.Text,
+	}
+	a.mu.Unlock()
+	if msg.User == "" && msg.Username != "" {
+		preview.Author = msg.Username
+	}
+
+	a.tview.QueueUpdateDraw(func() {
+		a.chatView.MessagesList.SetMessagePreview(link, preview)
+	})
+}
+
+// fetchMessage returns the single message at ts in channelID, looking inside
+// the thread when threadTS is set. It returns nil if no such message exists.
+func (a *App) fetchMessage(channelID, ts, threadTS string) (*slack.Message, error) {
+	if threadTS != "" {
+		msgs, _, _, err := a.slack.GetConversationReplies(&slack.GetConversationRepliesParameters{
+			ChannelID: channelID,
+			Timestamp: threadTS,
+			Oldest:    ts,
+			Latest:    ts,
+			Inclusive: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range msgs {
+			if msgs[i].Timestamp == ts {
+				return &msgs[i], nil
+			}
+		}
+		return nil, nil
+	}
+
+	resp, err := a.slack.GetConversationHistory(&slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Latest:    ts,
+		Inclusive: true,
+		Limit:     1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Messages) == 0 || resp.Messages[0].Timestamp != ts {
+		return nil, nil
+	}
+	return &resp.Messages[0], nil
+}
+
+// conversationLabel returns "#name" for channels, the partner's name for
+// DMs and the member list for group DMs, as the channel tree shows them.
+// Caller must hold a.mu.
+func (a *App) conversationLabel(channelID string) string {
+	label := a.channelLabel(channelID)
+	if label == "" {
+		return ""
+	}
+	for _, ch := range a.channels {
+		if ch.ID != channelID {
+			continue
+		}
+		switch {
+		case ch.IsIM:
+			return label
+		case ch.IsMpIM:
+			if ch.Purpose.Value != "" {
+				return ch.Purpose.Value
+			}
+			return label
+		}
+	}
+	return "#" + label
+}
@@ -0,0 +1,185 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/m96-chan/Slacko/internal/config"
+	"github.com/m96-chan/Slacko/internal/ui/chat"
+)
+
+// configPollInterval is how often the config file is checked for changes.
+const configPollInterval = 2 * time.Second
+
+// sharedConfig guards the Config shared by all workspaces. The config is
+// only changed on the tview event loop, under mu, so code on the event loop
+// reads a.Config directly and background goroutines read a copy from
+// App.config. file is the config as last read from disk; options that
+// differ from it were changed at runtime with :set or :theme.
+type sharedConfig struct {
+	mu   sync.RWMutex
+	file config.Config
+}
+
+// newSharedConfig records cfg, as loaded from its file.
+func newSharedConfig(cfg *config.Config) *sharedConfig {
+	return &sharedConfig{file: *cfg}
+}
+
+// config returns a copy of the config, for code running outside the tview
+// event loop.
+func (a *App) config() config.Config {
+	if s := a.cfgState; s != nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
+	return *a.Config
+}
+
+// updateConfig runs fn, which changes a.Config, while background readers
+// are held off. Must be called from the tview event loop.
+func (a *App) updateConfig(fn func(cfg *config.Config)) {
+	if s := a.cfgState; s != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	fn(a.Config)
+}
+
+// reloadConfig loads the config file at path and applies it in place, so
+// every component sharing a.Config, in every workspace, sees the new theme,
+// keybinds and options. Options changed with :set or :theme keep their
+// runtime value unless the file changed them too, e.g. by :mkconfig.
+// When the file is invalid the current config is kept and the error is
+// returned. Must be called from the tview event loop.
+func (a *App) reloadConfig(path string) error {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+
+	file := *cfg
+	if s := a.cfgState; s != nil {
+		for _, opt := range ChangedOptions(a.Config, &s.file) {
+			if opt.Get(cfg) == opt.Get(&s.file) {
+				opt.set(cfg, opt.Get(a.Config))
+			}
+		}
+		if cfg.Theme.Preset != file.Theme.Preset {
+			cfg.Theme = config.LoadTheme(path, cfg.Theme.Preset)
+		}
+	}
+
+	a.updateConfig(func(c *config.Config) {
+		*c = *cfg
+		if a.cfgState != nil {
+			a.cfgState.file = file
+		}
+	})
+	a.tview.EnableMouse(a.Config.Mouse)
+	for _, w := range a.workspaceApps() {
+		if w.chatView != nil {
+			w.chatView.ApplyConfig()
+			w.chatView.MentionsList.SetCommands(chat.CommandEntries(a.Config))
+		}
+	}
+	return nil
+}
+
+// cmdSource handles ":source [path]" and ":reload". Without a path the
+// config file that is currently loaded is read again.
+func (a *App) cmdSource(path string) {
+	if path == "" {
+		path = a.Config.Path
+	}
+	if path == "" {
+		a.showCommandFeedback("No config file loaded")
+		return
+	}
+
+	// config.Load writes the defaults to a missing file; don't do that for
+	// a mistyped :source path.
+	if _, err := os.Stat(path); err != nil {
+		a.showCommandFeedback("Config error: " + err.Error())
+		return
+	}
+
+	if err := a.reloadConfig(path); err != nil {
+		slog.Warn("config reload failed", "path", path, "error", err)
+		a.showCommandFeedback("Config error: " + err.Error())
+		return
+	}
+	a.showCommandFeedback(fmt.Sprintf("Config reloaded from %s", path))
+}
+
+// watchConfig polls the config file at path and reloads it whenever it
+// changes, until stop is closed.
+func (a *App) watchConfig(path string, stop <-chan struct{}) {
+	if path == "" {
+		return
+	}
+	w := newFileWatcher(path)
+
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if !w.changed() {
+				continue
+			}
+			slog.Info("config file changed, reloading", "path", path)
+			a.tview.QueueUpdateDraw(func() {
+				cur := a.activeApp()
+				if err := a.reloadConfig(path); err != nil {
+					slog.Warn("config reload failed", "path", path, "error", err)
+					if cur.chatView != nil {
+						cur.showCommandFeedback("Config error: " + err.Error())
+					}
+					return
+				}
+				if cur.chatView != nil {
+					cur.showCommandFeedback("Config reloaded")
+				}
+			})
+		}
+	}
+}
+
+// fileWatcher detects changes to a file by comparing its modification time
+// and size between calls.
+type fileWatcher struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// newFileWatcher records the current state of the file at path.
+func newFileWatcher(path string) *fileWatcher {
+	w := &fileWatcher{path: path}
+	if info, err := os.Stat(path); err == nil {
+		w.modTime = info.ModTime()
+		w.size = info.Size()
+	}
+	return w
+}
+
+// changed reports whether the file was modified since the last call. A file
+// that is missing (e.g. mid-save by an editor) is not reported as changed.
+func (w *fileWatcher) changed() bool {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return false
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false
+	}
+	w.modTime = info.ModTime()
+	w.size = info.Size()
+	return true
+}
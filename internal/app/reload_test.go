@@ -0,0 +1,178 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m96-chan/Slacko/internal/config"
+)
+
+func writeTestConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadConfigAppliesInPlace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeTestConfig(t, path, "messages_limit = 50\n")
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{Config: cfg, tview: newTestApp()}
+
+	writeTestConfig(t, path, "messages_limit = 75\n[keybinds]\nquit = \"Ctrl+Q\"\n")
+	if err := a.reloadConfig(path); err != nil {
+		t.Fatalf("reloadConfig: %v", err)
+	}
+
+	// Components hold the same pointer, so the update must be in place.
+	if a.Config != cfg {
+		t.Fatal("reloadConfig replaced the config pointer")
+	}
+	if cfg.MessagesLimit != 75 {
+		t.Errorf("MessagesLimit = %d, want 75", cfg.MessagesLimit)
+	}
+	if !cfg.Keybinds.Quit.Matches("Ctrl+Q") {
+		t.Errorf("Quit = %v, want Ctrl+Q", cfg.Keybinds.Quit)
+	}
+}
+
+func TestReloadConfigKeepsOldOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeTestConfig(t, path, "messages_limit = 50\n")
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{Config: cfg, tview: newTestApp()}
+
+	for name, content := range map[string]string{
+		"syntax":     "messages_limit = \n",
+		"validation": "messages_limit = -1\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			writeTestConfig(t, path, content)
+			if err := a.reloadConfig(path); err == nil {
+				t.Fatal("reloadConfig should fail")
+			}
+			if cfg.MessagesLimit != 50 {
+				t.Errorf("MessagesLimit = %d, want the old value 50", cfg.MessagesLimit)
+			}
+		})
+	}
+}
+
+func TestReloadConfigKeepsRuntimeOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeTestConfig(t, path, "messages_limit = 50\n")
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{Config: cfg, cfgState: newSharedConfig(cfg), tview: newTestApp()}
+
+	set := func(args string) {
+		t.Helper()
+		cmd, err := ParseSetCommand(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.updateConfig(func(c *config.Config) {
+			_, err = ApplySetCommand(c, cmd)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	set("timestamps=false")
+	set("theme=monokai")
+	a.Config.Theme = config.LoadTheme(path, "monokai")
+
+	// An unrelated edit keeps the values changed with :set.
+	writeTestConfig(t, path, "messages_limit = 75\n")
+	if err := a.reloadConfig(path); err != nil {
+		t.Fatalf("reloadConfig: %v", err)
+	}
+	if cfg.MessagesLimit != 75 {
+		t.Errorf("MessagesLimit = %d, want 75", cfg.MessagesLimit)
+	}
+	if cfg.Timestamps.Enabled {
+		t.Error(":set notimestamps lost on reload")
+	}
+	if want := config.LoadTheme(path, "monokai"); cfg.Theme.Preset != "monokai" || cfg.Theme.StatusBar != want.StatusBar {
+		t.Errorf("theme = %q, want the monokai theme kept", cfg.Theme.Preset)
+	}
+
+	// Values the file changes itself (e.g. by :mkconfig) come from the file.
+	writeTestConfig(t, path, "messages_limit = 75\n[theme]\npreset = \"light\"\n")
+	if err := a.reloadConfig(path); err != nil {
+		t.Fatalf("reloadConfig: %v", err)
+	}
+	if cfg.Theme.Preset != "light" {
+		t.Errorf("theme = %q, want light from the file", cfg.Theme.Preset)
+	}
+	if cfg.Timestamps.Enabled {
+		t.Error(":set notimestamps lost on the second reload")
+	}
+}
+
+func TestConfigSnapshotDuringReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeTestConfig(t, path, "messages_limit = 50\n")
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{Config: cfg, cfgState: newSharedConfig(cfg), tview: newTestApp()}
+
+	// Background readers use config() while the event loop reloads; run
+	// with -race to check.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			if n := a.config().MessagesLimit; n != 50 && n != 60 {
+				t.Errorf("MessagesLimit = %d", n)
+			}
+		}
+	}()
+	writeTestConfig(t, path, "messages_limit = 60\n")
+	if err := a.reloadConfig(path); err != nil {
+		t.Fatalf("reloadConfig: %v", err)
+	}
+	<-done
+}
+
+func TestFileWatcherChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeTestConfig(t, path, "mouse = true\n")
+
+	w := newFileWatcher(path)
+	if w.changed() {
+		t.Error("unmodified file reported as changed")
+	}
+
+	writeTestConfig(t, path, "mouse = false\n")
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if !w.changed() {
+		t.Error("modified file not reported as changed")
+	}
+	if w.changed() {
+		t.Error("change reported twice")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if w.changed() {
+		t.Error("missing file reported as changed")
+	}
+}
@@ -0,0 +1,242 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/slack-go/slack"
+
+	"github.com/m96-chan/Slacko/internal/hooks"
+	"github.com/m96-chan/Slacko/internal/rpc"
+)
+
+// rpcChannel describes a channel in the "channels.list" result.
+type rpcChannel struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"` // "channel", "private", "dm" or "group_dm"
+	Unread  int    `json:"unread"`
+	Muted   bool   `json:"muted"`
+	Current bool   `json:"current"`
+}
+
+// startRPC serves the control API for the connected workspace on its Unix
+// socket until ctx is done, and returns the server so events can be
+// published to subscribers. It returns nil when the socket cannot be
+// created; the app works without it.
+func (a *App) startRPC(ctx context.Context) *rpc.Server {
+	srv := rpc.NewServer()
+	srv.Handle("channels.list", a.rpcListChannels)
+	srv.Handle("channels.switch", a.rpcSwitchChannel)
+	srv.Handle("messages.send", a.rpcSendMessage)
+	srv.Handle("permalink.open", a.rpcOpenPermalink)
+	srv.Handle("status.set", a.rpcSetStatus)
+
+	path := rpc.SocketPath(a.slack.TeamID)
+	if err := srv.Listen(path); err != nil {
+		slog.Warn("control socket unavailable", "path", path, "error", err)
+		return nil
+	}
+	slog.Info("control socket listening", "path", path)
+
+	go func() {
+		if err := srv.Serve(); err != nil {
+			slog.Error("control socket stopped", "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	return srv
+}
+
+// publishEvent sends a Slack event to control socket subscribers, in the
+// same JSON shape that hooks receive on stdin.
+func (a *App) publishEvent(srv *rpc.Server, event string, data any) {
+	if srv == nil {
+		return
+	}
+	srv.Publish(event, hooks.Event{Type: event, TeamID: a.slack.TeamID, UserID: a.slack.UserID, Data: data})
+}
+
+// callOnUI runs fn on the tview event loop and waits for it to finish.
+func (a *App) callOnUI(fn func()) {
+	done := make(chan struct{})
+	a.tview.QueueUpdateDraw(func() {
+		defer close(done)
+		fn()
+	})
+	<-done
+}
+
+// decodeParams unmarshals the request params into v. Missing params leave v
+// unchanged.
+func decodeParams(raw json.RawMessage, v any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return rpc.InvalidParams("%v", err)
+	}
+	return nil
+}
+
+// findChannel looks up a channel by ID or name, with or without a leading
+// "#". Callers must hold a.mu.
+func (a *App) findChannel(ref string) (slack.Channel, bool) {
+	name := strings.TrimPrefix(ref, "#")
+	for _, ch := range a.channels {
+		if ch.ID == ref || (ch.Name != "" && ch.Name == name) {
+			return ch, true
+		}
+	}
+	return slack.Channel{}, false
+}
+
+// rpcListChannels handles "channels.list". With {"unread_only": true} only
+// channels with unread messages are returned.
+func (a *App) rpcListChannels(raw json.RawMessage) (any, error) {
+	var p struct {
+		UnreadOnly bool `json:"unread_only"`
+	}
+	if err := decodeParams(raw, &p); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	list := make([]rpcChannel, 0, len(a.channels))
+	for _, ch := range a.channels {
+		c := rpcChannel{ID: ch.ID, Name: a.channelLabel(ch.ID), Current: ch.ID == a.currentChannel}
+		switch {
+		case ch.IsIM:
+			c.Type = "dm"
+		case ch.IsMpIM:
+			c.Type = "group_dm"
+		case ch.IsPrivate:
+			c.Type = "private"
+		default:
+			c.Type = "channel"
+		}
+		list = append(list, c)
+	}
+	a.mu.Unlock()
+
+	a.callOnUI(func() {
+		for i := range list {
+			list[i].Unread = a.chatView.ChannelsTree.UnreadCount(list[i].ID)
+			list[i].Muted = a.chatView.ChannelsTree.IsMuted(list[i].ID)
+		}
+	})
+
+	if p.UnreadOnly {
+		unread := list[:0]
+		for _, c := range list {
+			if c.Unread > 0 {
+				unread = append(unread, c)
+			}
+		}
+		list = unread
+	}
+	return list, nil
+}
+
+// rpcSwitchChannel handles "channels.switch" {"channel": "C123" | "#name"}.
+func (a *App) rpcSwitchChannel(raw json.RawMessage) (any, error) {
+	var p struct {
+		Channel string `json:"channel"`
+	}
+	if err := decodeParams(raw, &p); err != nil {
+		return nil, err
+	}
+	if p.Channel == "" {
+		return nil, rpc.InvalidParams("channel is required")
+	}
+
+	a.mu.Lock()
+	ch, ok := a.findChannel(p.Channel)
+	a.mu.Unlock()
+	if !ok {
+		return nil, errors.New("unknown channel: " + p.Channel)
+	}
+
+	a.callOnUI(func() {
+		if !a.workspaces.isActive(a) {
+			a.showWorkspace()
+		}
+		a.onChannelSelected(ch.ID)
+	})
+	return map[string]string{"id": ch.ID}, nil
+}
+
+// rpcSendMessage handles "messages.send" {"channel", "text", "thread_ts"}.
+// Without a channel the message goes to the current channel.
+func (a *App) rpcSendMessage(raw json.RawMessage) (any, error) {
+	var p struct {
+		Channel  string `json:"channel"`
+		Text     string `json:"text"`
+		ThreadTS string `json:"thread_ts"`
+	}
+	if err := decodeParams(raw, &p); err != nil {
+		return nil, err
+	}
+	if p.Text == "" {
+		return nil, rpc.InvalidParams("text is required")
+	}
+
+	a.mu.Lock()
+	channelID := a.currentChannel
+	if p.Channel != "" {
+		channelID = p.Channel
+		if ch, ok := a.findChannel(p.Channel); ok {
+			channelID = ch.ID
+		}
+	}
+	a.mu.Unlock()
+	if channelID == "" {
+		return nil, errors.New("no channel selected")
+	}
+
+	a.onMessageSend(channelID, p.Text, p.ThreadTS)
+	return map[string]string{"channel": channelID}, nil
+}
+
+// rpcOpenPermalink handles "permalink.open" {"url": "https://..."}.
+func (a *App) rpcOpenPermalink(raw json.RawMessage) (any, error) {
+	var p struct {
+		URL string `json:"url"`
+	}
+	if err := decodeParams(raw, &p); err != nil {
+		return nil, err
+	}
+	if p.URL == "" {
+		return nil, rpc.InvalidParams("url is required")
+	}
+
+	a.openPermalink(p.URL, false)
+	return nil, nil
+}
+
+// rpcSetStatus handles "status.set" {"emoji": ":coffee:", "text": "..."}.
+func (a *App) rpcSetStatus(raw json.RawMessage) (any, error) {
+	var p struct {
+		Emoji string `json:"emoji"`
+		Text  string `json:"text"`
+	}
+	if err := decodeParams(raw, &p); err != nil {
+		return nil, err
+	}
+	args := strings.TrimSpace(p.Text)
+	if p.Emoji != "" {
+		args = ":" + strings.Trim(p.Emoji, ":") + ": " + args
+	}
+	if args == "" {
+		return nil, rpc.InvalidParams("emoji or text is required")
+	}
+
+	a.cmdSetStatus(args)
+	return nil, nil
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/slack-go/slack"
+
+	"github.com/m96-chan/Slacko/internal/rpc"
+)
+
+func newRPCTestApp(t *testing.T) *App {
+	t.Helper()
+	a := newThemeTestApp(t, "")
+	general := slack.Channel{}
+	general.ID, general.Name = "C1", "general"
+	dm := slack.Channel{}
+	dm.ID, dm.IsIM, dm.User = "D1", true, "U2"
+	a.channels = []slack.Channel{general, dm}
+	a.users = map[string]slack.User{"U2": {ID: "U2", Name: "bob"}}
+	return a
+}
+
+// runTestUI runs the tview event loop on a simulation screen so that
+// callOnUI returns.
+func runTestUI(t *testing.T, a *App) {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("")
+	a.tview.SetScreen(screen)
+	a.tview.SetRoot(a.chatView, true)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.tview.Run()
+	}()
+	t.Cleanup(func() {
+		a.tview.Stop()
+		<-done
+	})
+}
+
+func TestFindChannel(t *testing.T) {
+	a := newRPCTestApp(t)
+
+	for _, ref := range []string{"C1", "general", "#general"} {
+		if ch, ok := a.findChannel(ref); !ok || ch.ID != "C1" {
+			t.Errorf("findChannel(%q) = %q, %v", ref, ch.ID, ok)
+		}
+	}
+	if _, ok := a.findChannel("#random"); ok {
+		t.Error("findChannel found an unknown channel")
+	}
+}
+
+func TestRPCListChannels(t *testing.T) {
+	a := newRPCTestApp(t)
+	a.chatView.ChannelsTree.Populate(a.channels, a.users, "U1")
+	a.chatView.ChannelsTree.SetUnreadCount("D1", 3)
+	runTestUI(t, a)
+
+	result, err := a.rpcListChannels(json.RawMessage(`{"unread_only": true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := result.([]rpcChannel)
+	if len(list) != 1 {
+		t.Fatalf("unread channels = %+v, want only D1", list)
+	}
+	if want := (rpcChannel{ID: "D1", Name: "bob", Type: "dm", Unread: 3}); list[0] != want {
+		t.Errorf("channel = %+v, want %+v", list[0], want)
+	}
+}
+
+func TestRPCParamErrors(t *testing.T) {
+	a := newRPCTestApp(t)
+
+	tests := []struct {
+		name    string
+		handler rpc.HandlerFunc
+		params  string
+		code    int
+	}{
+		{"send without text", a.rpcSendMessage, `{"channel": "C1"}`, rpc.CodeInvalidParams},
+		{"switch without channel", a.rpcSwitchChannel, `{}`, rpc.CodeInvalidParams},
+		{"bad params", a.rpcListChannels, `[1]`, rpc.CodeInvalidParams},
+		{"open without url", a.rpcOpenPermalink, ``, rpc.CodeInvalidParams},
+		{"empty status", a.rpcSetStatus, `{"text": " "}`, rpc.CodeInvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.handler(json.RawMessage(tt.params))
+			var rpcErr *rpc.Error
+			if !errors.As(err, &rpcErr) || rpcErr.Code != tt.code {
+				t.Errorf("error = %v, want code %d", err, tt.code)
+			}
+		})
+	}
+
+	if _, err := a.rpcSwitchChannel(json.RawMessage(`{"channel": "#random"}`)); err == nil {
+		t.Error("switching to an unknown channel should fail")
+	}
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/slack-go/slack"
+
+	"github.com/m96-chan/Slacko/internal/ui/chat"
+)
+
+// searchPageSize is the number of results fetched per search page.
+const searchPageSize = 20
+
+// search runs one page of a search picker request against search.messages or
+// search.files and hands the results back to the picker.
+func (a *App) search(req chat.SearchRequest) {
+	if req.Kind == chat.SearchKindFiles {
+		a.searchFiles(req)
+		return
+	}
+	a.searchMessages(req)
+}
+
+// searchParams returns the Slack search parameters for req.
+func searchParams(req chat.SearchRequest) slack.SearchParameters {
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	return slack.SearchParameters{
+		Count:         searchPageSize,
+		Page:          page,
+		Sort:          req.Sort,
+		SortDirection: "desc",
+	}
+}
+
+// searchMessages searches Slack messages and updates the search picker with results.
+func (a *App) searchMessages(req chat.SearchRequest) {
+	results, err := a.slack.SearchMessages(req.Query, searchParams(req))
+	if err != nil {
+		slog.Error("failed to search messages", "query", req.Query, "error", err)
+		a.tview.QueueUpdateDraw(func() {
+			a.chatView.SearchPicker.SearchFailed(req)
+		})
+		return
+	}
+
+	a.mu.Lock()
+	users := a.users
+	a.mu.Unlock()
+
+	entries := make([]chat.SearchResultEntry, 0, len(results.Matches))
+	for _, m := range results.Matches {
+		userName := m.Username
+		if userName == "" {
+			userName = userDisplayName(users, m.User)
+		}
+
+		entries = append(entries, chat.SearchResultEntry{
+			ChannelID:   m.Channel.ID,
+			ChannelName: m.Channel.Name,
+			UserName:    userName,
+			Timestamp:   m.Timestamp,
+			Text:        m.Text,
+		})
+	}
+
+	hasMore := results.Paging.Page < results.Paging.Pages
+	a.tview.QueueUpdateDraw(func() {
+		a.chatView.SearchPicker.ShowResults(req, entries, results.Total, hasMore)
+	})
+}
+
+// searchFiles searches Slack files and updates the search picker's Files tab.
+func (a *App) searchFiles(req chat.SearchRequest) {
+	results, err := a.slack.SearchFiles(req.Query, searchParams(req))
+	if err != nil {
+		slog.Error("failed to search files", "query", req.Query, "error", err)
+		a.tview.QueueUpdateDraw(func() {
+			a.chatView.SearchPicker.SearchFailed(req)
+		})
+		return
+	}
+
+	a.mu.Lock()
+	users := a.users
+	entries := make([]chat.SearchResultEntry, 0, len(results.Matches))
+	for i := range results.Matches {
+		f := results.Matches[i]
+		entry := chat.SearchResultEntry{
+			UserName:  userDisplayName(users, f.User),
+			Timestamp: fmt.Sprintf("%d.000000", int64(f.Created)),
+			Text:      f.Title,
+			File:      &f,
+		}
+		for _, ids := range [][]string{f.Channels, f.Groups, f.IMs} {
+			if len(ids) > 0 {
+				entry.ChannelID = ids[0]
+				entry.ChannelName = a.conversationLabel(ids[0])
+				break
+			}
+		}
+		entries = append(entries, entry)
+	}
+	a.mu.Unlock()
+
+	hasMore := results.Paging.Page < results.Paging.Pages
+	a.tview.QueueUpdateDraw(func() {
+		a.chatView.SearchPicker.ShowResults(req, entries, results.Total, hasMore)
+	})
+}
+
+// searchChannelNames returns the names offered for in: completion.
+func searchChannelNames(channels []slack.Channel) []string {
+	names := make([]string, 0, len(channels))
+	for _, ch := range channels {
+		if ch.Name != "" && !ch.IsIM && !ch.IsMpIM {
+			names = append(names, ch.Name)
+		}
+	}
+	return names
+}
+
+// searchUserNames returns the user handles offered for from: completion.
+func searchUserNames(users []slack.User) []string {
+	names := make([]string, 0, len(users))
+	for _, u := range users {
+		if u.Name != "" && !u.Deleted {
+			names = append(names, u.Name)
+		}
+	}
+	return names
+}
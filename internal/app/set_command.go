@@ -2,62 +2,214 @@ package app
 
 import (
 	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/alecthomas/chroma/v2/styles"
+
 	"github.com/m96-chan/Slacko/internal/config"
 )
 
-// RuntimeOption defines a settable runtime option with typed getter/setter.
+// OptionType is the kind of value a runtime option holds.
+type OptionType int
+
+const (
+	BoolOption OptionType = iota
+	IntOption
+	StringOption
+	EnumOption
+)
+
+// RuntimeOption defines a config value that can be changed with :set.
 type RuntimeOption struct {
-	Name string
-	Get  func(*config.Config) bool
-	Set  func(*config.Config, bool)
+	Name   string // name used with :set
+	Key    string // dotted TOML key in config.toml; also accepted by :set
+	Type   OptionType
+	Values []string // allowed values of an EnumOption
+	Min    int      // smallest value of an IntOption
+	Max    int      // largest value of an IntOption; 0 means unbounded
+
+	// field returns a pointer to the option's value in the config: *bool,
+	// *int or *string depending on Type.
+	field func(*config.Config) any
 }
 
-// runtimeOptions is the registry of all runtime-settable boolean options.
+// runtimeOptions is the registry of all runtime-settable options.
 var runtimeOptions = []RuntimeOption{
 	{
-		Name: "mouse",
-		Get:  func(c *config.Config) bool { return c.Mouse },
-		Set:  func(c *config.Config, v bool) { c.Mouse = v },
+		Name:  "mouse",
+		Key:   "mouse",
+		Type:  BoolOption,
+		field: func(c *config.Config) any { return &c.Mouse },
+	},
+	{
+		Name:  "timestamps",
+		Key:   "timestamps.enabled",
+		Type:  BoolOption,
+		field: func(c *config.Config) any { return &c.Timestamps.Enabled },
+	},
+	{
+		Name:  "markdown",
+		Key:   "markdown.enabled",
+		Type:  BoolOption,
+		field: func(c *config.Config) any { return &c.Markdown.Enabled },
 	},
 	{
-		Name: "timestamps",
-		Get:  func(c *config.Config) bool { return c.Timestamps.Enabled },
-		Set:  func(c *config.Config, v bool) { c.Timestamps.Enabled = v },
+		Name:  "typing",
+		Key:   "typing_indicator.enabled",
+		Type:  BoolOption,
+		field: func(c *config.Config) any { return &c.TypingIndicator.Enabled },
 	},
 	{
-		Name: "markdown",
-		Get:  func(c *config.Config) bool { return c.Markdown.Enabled },
-		Set:  func(c *config.Config, v bool) { c.Markdown.Enabled = v },
+		Name:  "presence",
+		Key:   "presence.enabled",
+		Type:  BoolOption,
+		field: func(c *config.Config) any { return &c.Presence.Enabled },
 	},
 	{
-		Name: "typing",
-		Get:  func(c *config.Config) bool { return c.TypingIndicator.Enabled },
-		Set:  func(c *config.Config, v bool) { c.TypingIndicator.Enabled = v },
+		Name:  "date_separator",
+		Key:   "date_separator.enabled",
+		Type:  BoolOption,
+		field: func(c *config.Config) any { return &c.DateSeparator.Enabled },
 	},
 	{
-		Name: "presence",
-		Get:  func(c *config.Config) bool { return c.Presence.Enabled },
-		Set:  func(c *config.Config, v bool) { c.Presence.Enabled = v },
+		Name:  "notifications",
+		Key:   "notifications.enabled",
+		Type:  BoolOption,
+		field: func(c *config.Config) any { return &c.Notifications.Enabled },
 	},
 	{
-		Name: "date_separator",
-		Get:  func(c *config.Config) bool { return c.DateSeparator.Enabled },
-		Set:  func(c *config.Config, v bool) { c.DateSeparator.Enabled = v },
+		Name:  "messages_limit",
+		Key:   "messages_limit",
+		Type:  IntOption,
+		Min:   1,
+		Max:   100,
+		field: func(c *config.Config) any { return &c.MessagesLimit },
+	},
+	{
+		Name:  "autocomplete_limit",
+		Key:   "autocomplete_limit",
+		Type:  IntOption,
+		field: func(c *config.Config) any { return &c.AutocompleteLimit },
+	},
+	{
+		Name:  "timestamp_format",
+		Key:   "timestamps.format",
+		Type:  StringOption,
+		field: func(c *config.Config) any { return &c.Timestamps.Format },
+	},
+	{
+		Name:  "download_dir",
+		Key:   "download_dir",
+		Type:  StringOption,
+		field: func(c *config.Config) any { return &c.DownloadDir },
+	},
+	{
+		Name:   "syntax_theme",
+		Key:    "markdown.syntax_theme",
+		Type:   EnumOption,
+		Values: styles.Names(),
+		field:  func(c *config.Config) any { return &c.Markdown.SyntaxTheme },
+	},
+	{
+		Name:   "theme",
+		Key:    "theme.preset",
+		Type:   EnumOption,
+		Values: config.ThemePresets,
+		field:  func(c *config.Config) any { return &c.Theme.Preset },
 	},
 }
 
-// findOption looks up a runtime option by name.
+// findOption looks up a runtime option by name or TOML key.
 func findOption(name string) (*RuntimeOption, bool) {
 	for i := range runtimeOptions {
-		if runtimeOptions[i].Name == name {
+		if runtimeOptions[i].Name == name || runtimeOptions[i].Key == name {
 			return &runtimeOptions[i], true
 		}
 	}
 	return nil, false
 }
 
+// unknownOptionError reports an option that is not in the registry.
+func unknownOptionError(name string) error {
+	return fmt.Errorf("unknown option %q (available: %s)", name, strings.Join(RuntimeOptionNames(), ", "))
+}
+
+// Get returns the option's current value: a bool, int or string.
+func (o *RuntimeOption) Get(cfg *config.Config) any {
+	switch p := o.field(cfg).(type) {
+	case *bool:
+		return *p
+	case *int:
+		return *p
+	case *string:
+		return *p
+	}
+	return nil
+}
+
+// Format returns the option's current value for display: "on"/"off" for
+// booleans, quoted strings and plain numbers.
+func (o *RuntimeOption) Format(cfg *config.Config) string {
+	switch v := o.Get(cfg).(type) {
+	case bool:
+		return boolString(v)
+	case string:
+		return strconv.Quote(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parse converts s to the option's type and validates it.
+func (o *RuntimeOption) parse(s string) (any, error) {
+	switch o.Type {
+	case BoolOption:
+		return ParseBoolValue(s)
+	case IntOption:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q: %s takes a number", s, o.Name)
+		}
+		if err := o.checkRange(n); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case EnumOption:
+		if !slices.Contains(o.Values, s) {
+			return nil, fmt.Errorf("invalid value %q for %s (available: %s)", s, o.Name, strings.Join(o.Values, ", "))
+		}
+		return s, nil
+	default:
+		return s, nil
+	}
+}
+
+// checkRange validates an IntOption value.
+func (o *RuntimeOption) checkRange(n int) error {
+	if n < o.Min || (o.Max > 0 && n > o.Max) {
+		if o.Max > 0 {
+			return fmt.Errorf("%s must be between %d and %d, got %d", o.Name, o.Min, o.Max, n)
+		}
+		return fmt.Errorf("%s must be >= %d, got %d", o.Name, o.Min, n)
+	}
+	return nil
+}
+
+// set stores an already parsed value.
+func (o *RuntimeOption) set(cfg *config.Config, v any) {
+	switch p := o.field(cfg).(type) {
+	case *bool:
+		*p = v.(bool)
+	case *int:
+		*p = v.(int)
+	case *string:
+		*p = v.(string)
+	}
+}
+
 // boolString returns "on" or "off" for a boolean value.
 func boolString(v bool) string {
 	if v {
@@ -79,88 +231,149 @@ func ParseBoolValue(s string) (bool, error) {
 	}
 }
 
+// SetOp is the operation requested by a :set command.
+type SetOp int
+
+const (
+	SetAssign   SetOp = iota // option=value or option value
+	SetToggle                // option! or a bare boolean option
+	SetQuery                 // option? or a bare non-boolean option
+	SetAdd                   // option+=value: add to a number, append to a string
+	SetSubtract              // option-=value: subtract from a number, remove from a string
+)
+
+// SetCommand is a parsed :set argument.
+type SetCommand struct {
+	Option string
+	Op     SetOp
+	Value  string
+}
+
 // ParseSetCommand parses the arguments to the :set command.
 //
 // Supported forms:
-//   - "option=value"      — assign (option, value, false, nil)
-//   - "option value"      — assign (option, value, false, nil)
-//   - "option?"           — query  (option, "",    true,  nil)
-//   - "option"            — toggle (option, "",    false, nil)
-//   - ""                  — error
+//   - "option=value"   — assign; everything after "=" is the value
+//   - "option value"   — assign
+//   - "option+=value"  — add (numbers) or append (strings)
+//   - "option-=value"  — subtract (numbers) or remove (strings)
+//   - "option!"        — toggle a boolean
+//   - "option?"        — query
+//   - "option"         — toggle a boolean, query anything else
 //
-// The value is validated as a boolean (on/off, true/false, yes/no).
-func ParseSetCommand(args string) (option, value string, query bool, err error) {
+// Values of known options are validated against the option's type.
+func ParseSetCommand(args string) (SetCommand, error) {
 	args = strings.TrimSpace(args)
 	if args == "" {
-		return "", "", false, fmt.Errorf("no option specified")
+		return SetCommand{}, fmt.Errorf("no option specified")
 	}
 
-	// Check for query form: "option?"
-	if strings.HasSuffix(args, "?") {
-		option = strings.TrimSpace(strings.TrimSuffix(args, "?"))
-		return option, "", true, nil
-	}
-
-	// Check for "option=value" form.
+	var cmd SetCommand
 	if idx := strings.Index(args, "="); idx >= 0 {
-		option = strings.TrimSpace(args[:idx])
-		value = strings.TrimSpace(args[idx+1:])
+		cmd.Option = strings.TrimSpace(args[:idx])
+		cmd.Value = strings.TrimSpace(args[idx+1:])
+		switch {
+		case strings.HasSuffix(cmd.Option, "+"):
+			cmd.Op = SetAdd
+		case strings.HasSuffix(cmd.Option, "-"):
+			cmd.Op = SetSubtract
+		}
+		if cmd.Op != SetAssign {
+			cmd.Option = strings.TrimSpace(cmd.Option[:len(cmd.Option)-1])
+		}
+	} else if name, ok := strings.CutSuffix(args, "?"); ok {
+		return SetCommand{Option: strings.TrimSpace(name), Op: SetQuery}, nil
+	} else if name, ok := strings.CutSuffix(args, "!"); ok {
+		return SetCommand{Option: strings.TrimSpace(name), Op: SetToggle}, nil
 	} else if parts := strings.Fields(args); len(parts) == 2 {
-		// "option value" form.
-		option = parts[0]
-		value = parts[1]
+		cmd.Option, cmd.Value = parts[0], parts[1]
 	} else if len(parts) == 1 {
-		// Toggle form: just "option".
-		return parts[0], "", false, nil
+		cmd.Option, cmd.Op = parts[0], SetToggle
+		if opt, ok := findOption(cmd.Option); ok && opt.Type != BoolOption {
+			cmd.Op = SetQuery
+		}
+		return cmd, nil
 	} else {
-		return "", "", false, fmt.Errorf("invalid syntax: %q", args)
+		return SetCommand{}, fmt.Errorf("invalid syntax: %q", args)
 	}
 
-	// Validate value if provided.
-	if value != "" {
-		if _, err := ParseBoolValue(value); err != nil {
-			return "", "", false, err
+	if cmd.Option == "" {
+		return SetCommand{}, fmt.Errorf("invalid syntax: %q", args)
+	}
+
+	// Validate the value when the option's type is known.
+	if opt, ok := findOption(cmd.Option); ok && cmd.Op == SetAssign {
+		if _, err := opt.parse(cmd.Value); err != nil {
+			return SetCommand{}, err
 		}
 	}
 
-	return option, value, false, nil
+	return cmd, nil
 }
 
-// ApplySetCommand applies a :set command to the config.
-//
-// If value is empty, the option is toggled.
-// If value is provided, it is set to the given boolean.
-//
-// Returns a human-readable feedback message.
-func ApplySetCommand(cfg *config.Config, option, value string) (string, error) {
-	opt, ok := findOption(option)
+// ApplySetCommand applies a parsed :set command to the config and returns a
+// human-readable feedback message. Queries leave the config unchanged.
+func ApplySetCommand(cfg *config.Config, cmd SetCommand) (string, error) {
+	opt, ok := findOption(cmd.Option)
 	if !ok {
-		return "", fmt.Errorf("unknown option %q (available: %s)", option, strings.Join(RuntimeOptionNames(), ", "))
+		return "", unknownOptionError(cmd.Option)
 	}
 
-	var newVal bool
-	if value == "" {
-		// Toggle.
-		newVal = !opt.Get(cfg)
-	} else {
+	var newVal any
+	switch cmd.Op {
+	case SetQuery:
+		return QueryOption(cfg, cmd.Option)
+
+	case SetToggle:
+		if opt.Type != BoolOption {
+			return "", fmt.Errorf("%s is not a boolean option; use %s=value", opt.Name, opt.Name)
+		}
+		newVal = !opt.Get(cfg).(bool)
+
+	case SetAdd, SetSubtract:
+		switch opt.Type {
+		case IntOption:
+			n, err := strconv.Atoi(cmd.Value)
+			if err != nil {
+				return "", fmt.Errorf("invalid value %q: %s takes a number", cmd.Value, opt.Name)
+			}
+			if cmd.Op == SetSubtract {
+				n = -n
+			}
+			n += opt.Get(cfg).(int)
+			if err := opt.checkRange(n); err != nil {
+				return "", err
+			}
+			newVal = n
+		case StringOption:
+			cur := opt.Get(cfg).(string)
+			if cmd.Op == SetAdd {
+				newVal = cur + cmd.Value
+			} else {
+				newVal = strings.Replace(cur, cmd.Value, "", 1)
+			}
+		default:
+			return "", fmt.Errorf("%s does not support += or -=", opt.Name)
+		}
+
+	default:
 		var err error
-		newVal, err = ParseBoolValue(value)
+		newVal, err = opt.parse(cmd.Value)
 		if err != nil {
 			return "", err
 		}
 	}
 
-	opt.Set(cfg, newVal)
-	return fmt.Sprintf("%s = %s", option, boolString(newVal)), nil
+	opt.set(cfg, newVal)
+	return fmt.Sprintf("%s = %s", opt.Name, opt.Format(cfg)), nil
 }
 
 // QueryOption returns the current value of a runtime option.
 func QueryOption(cfg *config.Config, option string) (string, error) {
 	opt, ok := findOption(option)
 	if !ok {
-		return "", fmt.Errorf("unknown option %q (available: %s)", option, strings.Join(RuntimeOptionNames(), ", "))
+		return "", unknownOptionError(option)
 	}
-	return fmt.Sprintf("%s = %s", option, boolString(opt.Get(cfg))), nil
+	return fmt.Sprintf("%s = %s", opt.Name, opt.Format(cfg)), nil
 }
 
 // ListRuntimeOptions returns a formatted string of all settable options
@@ -168,8 +381,9 @@ func QueryOption(cfg *config.Config, option string) (string, error) {
 func ListRuntimeOptions(cfg *config.Config) string {
 	var b strings.Builder
 	b.WriteString("Runtime options:")
-	for _, opt := range runtimeOptions {
-		b.WriteString(fmt.Sprintf("  %s = %s", opt.Name, boolString(opt.Get(cfg))))
+	for i := range runtimeOptions {
+		opt := &runtimeOptions[i]
+		b.WriteString(fmt.Sprintf("  %s = %s", opt.Name, opt.Format(cfg)))
 	}
 	return b.String()
 }
@@ -182,3 +396,71 @@ func RuntimeOptionNames() []string {
 	}
 	return names
 }
+
+// RuntimeOptionValues returns the values offered for completion after
+// "option=": on/off for booleans and the allowed values of enums.
+func RuntimeOptionValues() map[string][]string {
+	values := make(map[string][]string)
+	for _, opt := range runtimeOptions {
+		switch opt.Type {
+		case BoolOption:
+			values[opt.Name] = []string{"on", "off"}
+		case EnumOption:
+			values[opt.Name] = opt.Values
+		}
+	}
+	return values
+}
+
+// ChangedOptions returns the options whose value in cfg differs from saved,
+// the config as it is in the file.
+func ChangedOptions(cfg, saved *config.Config) []*RuntimeOption {
+	var changed []*RuntimeOption
+	for i := range runtimeOptions {
+		opt := &runtimeOptions[i]
+		if opt.Get(cfg) != opt.Get(saved) {
+			changed = append(changed, opt)
+		}
+	}
+	return changed
+}
+
+// WriteRuntimeOptions writes every option changed since the config file at
+// path was loaded back to that file, keeping the user's comments and layout.
+// It returns the names of the options written.
+func WriteRuntimeOptions(cfg *config.Config, path string) ([]string, error) {
+	saved, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, opt := range ChangedOptions(cfg, saved) {
+		if err := config.SetValue(path, opt.Key, opt.Get(cfg)); err != nil {
+			return names, err
+		}
+		names = append(names, opt.Name)
+	}
+	return names, nil
+}
+
+// cmdWriteConfig handles ":mkconfig" and ":w": saves options changed with
+// :set (and :theme) to the config file.
+func (a *App) cmdWriteConfig() {
+	if a.Config.Path == "" {
+		a.showCommandFeedback("No config file loaded")
+		return
+	}
+
+	names, err := WriteRuntimeOptions(a.Config, a.Config.Path)
+	if err != nil {
+		slog.Warn("failed to write config", "path", a.Config.Path, "error", err)
+		a.showCommandFeedback("Error: " + err.Error())
+		return
+	}
+	if len(names) == 0 {
+		a.showCommandFeedback("No changed options to write")
+		return
+	}
+	a.showCommandFeedback(fmt.Sprintf("Wrote %s to %s", strings.Join(names, ", "), a.Config.Path))
+}
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -12,174 +14,174 @@ import (
 // ---------------------------------------------------------------------------
 
 func TestParseSetCommand_AssignOn(t *testing.T) {
-	opt, val, query, err := ParseSetCommand("timestamps=on")
+	cmd, err := ParseSetCommand("timestamps=on")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if opt != "timestamps" {
-		t.Errorf("option = %q, want %q", opt, "timestamps")
+	if cmd.Option != "timestamps" {
+		t.Errorf("option = %q, want %q", cmd.Option, "timestamps")
 	}
-	if val != "on" {
-		t.Errorf("value = %q, want %q", val, "on")
+	if cmd.Value != "on" {
+		t.Errorf("value = %q, want %q", cmd.Value, "on")
 	}
-	if query {
+	if cmd.Op == SetQuery {
 		t.Error("query should be false")
 	}
 }
 
 func TestParseSetCommand_AssignOff(t *testing.T) {
-	opt, val, query, err := ParseSetCommand("mouse=off")
+	cmd, err := ParseSetCommand("mouse=off")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if opt != "mouse" {
-		t.Errorf("option = %q, want %q", opt, "mouse")
+	if cmd.Option != "mouse" {
+		t.Errorf("option = %q, want %q", cmd.Option, "mouse")
 	}
-	if val != "off" {
-		t.Errorf("value = %q, want %q", val, "off")
+	if cmd.Value != "off" {
+		t.Errorf("value = %q, want %q", cmd.Value, "off")
 	}
-	if query {
+	if cmd.Op == SetQuery {
 		t.Error("query should be false")
 	}
 }
 
 func TestParseSetCommand_AssignTrue(t *testing.T) {
-	opt, val, query, err := ParseSetCommand("markdown=true")
+	cmd, err := ParseSetCommand("markdown=true")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if opt != "markdown" {
-		t.Errorf("option = %q, want %q", opt, "markdown")
+	if cmd.Option != "markdown" {
+		t.Errorf("option = %q, want %q", cmd.Option, "markdown")
 	}
-	if val != "true" {
-		t.Errorf("value = %q, want %q", val, "true")
+	if cmd.Value != "true" {
+		t.Errorf("value = %q, want %q", cmd.Value, "true")
 	}
-	if query {
+	if cmd.Op == SetQuery {
 		t.Error("query should be false")
 	}
 }
 
 func TestParseSetCommand_AssignFalse(t *testing.T) {
-	opt, val, query, err := ParseSetCommand("presence=false")
+	cmd, err := ParseSetCommand("presence=false")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if opt != "presence" {
-		t.Errorf("option = %q, want %q", opt, "presence")
+	if cmd.Option != "presence" {
+		t.Errorf("option = %q, want %q", cmd.Option, "presence")
 	}
-	if val != "false" {
-		t.Errorf("value = %q, want %q", val, "false")
+	if cmd.Value != "false" {
+		t.Errorf("value = %q, want %q", cmd.Value, "false")
 	}
-	if query {
+	if cmd.Op == SetQuery {
 		t.Error("query should be false")
 	}
 }
 
 func TestParseSetCommand_AssignYes(t *testing.T) {
-	opt, val, query, err := ParseSetCommand("typing=yes")
+	cmd, err := ParseSetCommand("typing=yes")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if opt != "typing" {
-		t.Errorf("option = %q, want %q", opt, "typing")
+	if cmd.Option != "typing" {
+		t.Errorf("option = %q, want %q", cmd.Option, "typing")
 	}
-	if val != "yes" {
-		t.Errorf("value = %q, want %q", val, "yes")
+	if cmd.Value != "yes" {
+		t.Errorf("value = %q, want %q", cmd.Value, "yes")
 	}
-	if query {
+	if cmd.Op == SetQuery {
 		t.Error("query should be false")
 	}
 }
 
 func TestParseSetCommand_AssignNo(t *testing.T) {
-	opt, val, query, err := ParseSetCommand("date_separator=no")
+	cmd, err := ParseSetCommand("date_separator=no")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if opt != "date_separator" {
-		t.Errorf("option = %q, want %q", opt, "date_separator")
+	if cmd.Option != "date_separator" {
+		t.Errorf("option = %q, want %q", cmd.Option, "date_separator")
 	}
-	if val != "no" {
-		t.Errorf("value = %q, want %q", val, "no")
+	if cmd.Value != "no" {
+		t.Errorf("value = %q, want %q", cmd.Value, "no")
 	}
-	if query {
+	if cmd.Op == SetQuery {
 		t.Error("query should be false")
 	}
 }
 
 func TestParseSetCommand_Query(t *testing.T) {
-	opt, val, query, err := ParseSetCommand("mouse?")
+	cmd, err := ParseSetCommand("mouse?")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if opt != "mouse" {
-		t.Errorf("option = %q, want %q", opt, "mouse")
+	if cmd.Option != "mouse" {
+		t.Errorf("option = %q, want %q", cmd.Option, "mouse")
 	}
-	if val != "" {
-		t.Errorf("value = %q, want empty", val)
+	if cmd.Value != "" {
+		t.Errorf("value = %q, want empty", cmd.Value)
 	}
-	if !query {
+	if cmd.Op != SetQuery {
 		t.Error("query should be true")
 	}
 }
 
 func TestParseSetCommand_Toggle(t *testing.T) {
-	opt, val, query, err := ParseSetCommand("timestamps")
+	cmd, err := ParseSetCommand("timestamps")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if opt != "timestamps" {
-		t.Errorf("option = %q, want %q", opt, "timestamps")
+	if cmd.Option != "timestamps" {
+		t.Errorf("option = %q, want %q", cmd.Option, "timestamps")
 	}
-	if val != "" {
-		t.Errorf("value = %q, want empty", val)
+	if cmd.Value != "" {
+		t.Errorf("value = %q, want empty", cmd.Value)
 	}
-	if query {
+	if cmd.Op == SetQuery {
 		t.Error("query should be false for toggle")
 	}
 }
 
 func TestParseSetCommand_SpaceAssign(t *testing.T) {
-	opt, val, query, err := ParseSetCommand("timestamps on")
+	cmd, err := ParseSetCommand("timestamps on")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if opt != "timestamps" {
-		t.Errorf("option = %q, want %q", opt, "timestamps")
+	if cmd.Option != "timestamps" {
+		t.Errorf("option = %q, want %q", cmd.Option, "timestamps")
 	}
-	if val != "on" {
-		t.Errorf("value = %q, want %q", val, "on")
+	if cmd.Value != "on" {
+		t.Errorf("value = %q, want %q", cmd.Value, "on")
 	}
-	if query {
+	if cmd.Op == SetQuery {
 		t.Error("query should be false")
 	}
 }
 
 func TestParseSetCommand_TrimsWhitespace(t *testing.T) {
-	opt, val, query, err := ParseSetCommand("  mouse = on  ")
+	cmd, err := ParseSetCommand("  mouse = on  ")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if opt != "mouse" {
-		t.Errorf("option = %q, want %q", opt, "mouse")
+	if cmd.Option != "mouse" {
+		t.Errorf("option = %q, want %q", cmd.Option, "mouse")
 	}
-	if val != "on" {
-		t.Errorf("value = %q, want %q", val, "on")
+	if cmd.Value != "on" {
+		t.Errorf("value = %q, want %q", cmd.Value, "on")
 	}
-	if query {
+	if cmd.Op == SetQuery {
 		t.Error("query should be false")
 	}
 }
 
 func TestParseSetCommand_Empty(t *testing.T) {
-	_, _, _, err := ParseSetCommand("")
+	_, err := ParseSetCommand("")
 	if err == nil {
 		t.Error("expected error for empty input")
 	}
 }
 
 func TestParseSetCommand_InvalidValue(t *testing.T) {
-	_, _, _, err := ParseSetCommand("timestamps=maybe")
+	_, err := ParseSetCommand("timestamps=maybe")
 	if err == nil {
 		t.Error("expected error for invalid value")
 	}
@@ -193,7 +195,7 @@ func TestApplySetCommand_ToggleTimestamps(t *testing.T) {
 	cfg := &config.Config{}
 	cfg.Timestamps.Enabled = false
 
-	msg, err := ApplySetCommand(cfg, "timestamps", "")
+	msg, err := ApplySetCommand(cfg, SetCommand{Option: "timestamps", Op: SetToggle})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -205,7 +207,7 @@ func TestApplySetCommand_ToggleTimestamps(t *testing.T) {
 	}
 
 	// Toggle again.
-	msg, err = ApplySetCommand(cfg, "timestamps", "")
+	msg, err = ApplySetCommand(cfg, SetCommand{Option: "timestamps", Op: SetToggle})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -220,7 +222,7 @@ func TestApplySetCommand_ToggleTimestamps(t *testing.T) {
 func TestApplySetCommand_ToggleMouse(t *testing.T) {
 	cfg := &config.Config{Mouse: false}
 
-	msg, err := ApplySetCommand(cfg, "mouse", "")
+	msg, err := ApplySetCommand(cfg, SetCommand{Option: "mouse", Op: SetToggle})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -236,7 +238,7 @@ func TestApplySetCommand_ToggleMarkdown(t *testing.T) {
 	cfg := &config.Config{}
 	cfg.Markdown.Enabled = true
 
-	_, err := ApplySetCommand(cfg, "markdown", "")
+	_, err := ApplySetCommand(cfg, SetCommand{Option: "markdown", Op: SetToggle})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -249,7 +251,7 @@ func TestApplySetCommand_ToggleTyping(t *testing.T) {
 	cfg := &config.Config{}
 	cfg.TypingIndicator.Enabled = false
 
-	_, err := ApplySetCommand(cfg, "typing", "")
+	_, err := ApplySetCommand(cfg, SetCommand{Option: "typing", Op: SetToggle})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -262,7 +264,7 @@ func TestApplySetCommand_TogglePresence(t *testing.T) {
 	cfg := &config.Config{}
 	cfg.Presence.Enabled = true
 
-	_, err := ApplySetCommand(cfg, "presence", "")
+	_, err := ApplySetCommand(cfg, SetCommand{Option: "presence", Op: SetToggle})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -275,7 +277,7 @@ func TestApplySetCommand_ToggleDateSeparator(t *testing.T) {
 	cfg := &config.Config{}
 	cfg.DateSeparator.Enabled = false
 
-	_, err := ApplySetCommand(cfg, "date_separator", "")
+	_, err := ApplySetCommand(cfg, SetCommand{Option: "date_separator", Op: SetToggle})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -292,7 +294,7 @@ func TestApplySetCommand_SetTimestampsOn(t *testing.T) {
 	cfg := &config.Config{}
 	cfg.Timestamps.Enabled = false
 
-	msg, err := ApplySetCommand(cfg, "timestamps", "on")
+	msg, err := ApplySetCommand(cfg, SetCommand{Option: "timestamps", Value: "on"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -308,7 +310,7 @@ func TestApplySetCommand_SetTimestampsOff(t *testing.T) {
 	cfg := &config.Config{}
 	cfg.Timestamps.Enabled = true
 
-	msg, err := ApplySetCommand(cfg, "timestamps", "off")
+	msg, err := ApplySetCommand(cfg, SetCommand{Option: "timestamps", Value: "off"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -323,7 +325,7 @@ func TestApplySetCommand_SetTimestampsOff(t *testing.T) {
 func TestApplySetCommand_SetMouseTrue(t *testing.T) {
 	cfg := &config.Config{Mouse: false}
 
-	_, err := ApplySetCommand(cfg, "mouse", "true")
+	_, err := ApplySetCommand(cfg, SetCommand{Option: "mouse", Value: "true"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -335,7 +337,7 @@ func TestApplySetCommand_SetMouseTrue(t *testing.T) {
 func TestApplySetCommand_SetMouseFalse(t *testing.T) {
 	cfg := &config.Config{Mouse: true}
 
-	_, err := ApplySetCommand(cfg, "mouse", "false")
+	_, err := ApplySetCommand(cfg, SetCommand{Option: "mouse", Value: "false"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -347,7 +349,7 @@ func TestApplySetCommand_SetMouseFalse(t *testing.T) {
 func TestApplySetCommand_SetMouseYes(t *testing.T) {
 	cfg := &config.Config{Mouse: false}
 
-	_, err := ApplySetCommand(cfg, "mouse", "yes")
+	_, err := ApplySetCommand(cfg, SetCommand{Option: "mouse", Value: "yes"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -359,7 +361,7 @@ func TestApplySetCommand_SetMouseYes(t *testing.T) {
 func TestApplySetCommand_SetMouseNo(t *testing.T) {
 	cfg := &config.Config{Mouse: true}
 
-	_, err := ApplySetCommand(cfg, "mouse", "no")
+	_, err := ApplySetCommand(cfg, SetCommand{Option: "mouse", Value: "no"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -371,7 +373,7 @@ func TestApplySetCommand_SetMouseNo(t *testing.T) {
 func TestApplySetCommand_UnknownOption(t *testing.T) {
 	cfg := &config.Config{}
 
-	_, err := ApplySetCommand(cfg, "nonexistent", "on")
+	_, err := ApplySetCommand(cfg, SetCommand{Option: "nonexistent", Value: "on"})
 	if err == nil {
 		t.Error("expected error for unknown option")
 	}
@@ -383,7 +385,7 @@ func TestApplySetCommand_UnknownOption(t *testing.T) {
 func TestApplySetCommand_InvalidValue(t *testing.T) {
 	cfg := &config.Config{}
 
-	_, err := ApplySetCommand(cfg, "mouse", "maybe")
+	_, err := ApplySetCommand(cfg, SetCommand{Option: "mouse", Value: "maybe"})
 	if err == nil {
 		t.Error("expected error for invalid value")
 	}
@@ -392,6 +394,206 @@ func TestApplySetCommand_InvalidValue(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Typed options
+// ---------------------------------------------------------------------------
+
+func TestParseSetCommand_Operators(t *testing.T) {
+	tests := []struct {
+		input string
+		want  SetCommand
+	}{
+		{"messages_limit+=10", SetCommand{Option: "messages_limit", Op: SetAdd, Value: "10"}},
+		{"messages_limit -= 5", SetCommand{Option: "messages_limit", Op: SetSubtract, Value: "5"}},
+		{"mouse!", SetCommand{Option: "mouse", Op: SetToggle}},
+		{"messages_limit", SetCommand{Option: "messages_limit", Op: SetQuery}},
+		{"timestamp_format=Jan 2 15:04", SetCommand{Option: "timestamp_format", Value: "Jan 2 15:04"}},
+		{"download_dir=", SetCommand{Option: "download_dir"}},
+		{"timestamps.format=15:04?", SetCommand{Option: "timestamps.format", Value: "15:04?"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSetCommand(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSetCommand(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSetCommand_ValidatesTypedValues(t *testing.T) {
+	for _, input := range []string{
+		"messages_limit=lots",
+		"messages_limit=0",
+		"messages_limit=101",
+		"autocomplete_limit=-1",
+		"theme=nope",
+		"syntax_theme=nope",
+	} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := ParseSetCommand(input); err == nil {
+				t.Errorf("expected error for %q", input)
+			}
+		})
+	}
+}
+
+func TestApplySetCommand_Int(t *testing.T) {
+	cfg := &config.Config{MessagesLimit: 50}
+
+	steps := []struct {
+		args string
+		want int
+	}{
+		{"messages_limit=20", 20},
+		{"messages_limit+=30", 50},
+		{"messages_limit-=45", 5},
+	}
+	for _, step := range steps {
+		cmd, err := ParseSetCommand(step.args)
+		if err != nil {
+			t.Fatalf("%s: %v", step.args, err)
+		}
+		msg, err := ApplySetCommand(cfg, cmd)
+		if err != nil {
+			t.Fatalf("%s: %v", step.args, err)
+		}
+		if cfg.MessagesLimit != step.want {
+			t.Errorf("%s: MessagesLimit = %d, want %d", step.args, cfg.MessagesLimit, step.want)
+		}
+		if !strings.Contains(msg, "messages_limit") {
+			t.Errorf("%s: message = %q", step.args, msg)
+		}
+	}
+
+	if _, err := ApplySetCommand(cfg, SetCommand{Option: "messages_limit", Op: SetSubtract, Value: "10"}); err == nil {
+		t.Error("subtracting below the minimum should fail")
+	}
+	if cfg.MessagesLimit != 5 {
+		t.Errorf("failed update changed MessagesLimit to %d", cfg.MessagesLimit)
+	}
+	if _, err := ApplySetCommand(cfg, SetCommand{Option: "messages_limit", Op: SetToggle}); err == nil {
+		t.Error("toggling a number should fail")
+	}
+}
+
+func TestApplySetCommand_String(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Timestamps.Format = "15:04"
+
+	if _, err := ApplySetCommand(cfg, SetCommand{Option: "timestamp_format", Op: SetAdd, Value: ":05"}); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Timestamps.Format != "15:04:05" {
+		t.Errorf("Format = %q, want 15:04:05", cfg.Timestamps.Format)
+	}
+	if _, err := ApplySetCommand(cfg, SetCommand{Option: "timestamps.format", Op: SetSubtract, Value: ":05"}); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Timestamps.Format != "15:04" {
+		t.Errorf("Format = %q, want 15:04", cfg.Timestamps.Format)
+	}
+
+	msg, err := ApplySetCommand(cfg, SetCommand{Option: "download_dir", Value: "/tmp/dl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DownloadDir != "/tmp/dl" || msg != `download_dir = "/tmp/dl"` {
+		t.Errorf("DownloadDir = %q, message = %q", cfg.DownloadDir, msg)
+	}
+}
+
+func TestApplySetCommand_Enum(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Theme.Preset = "default"
+
+	if _, err := ApplySetCommand(cfg, SetCommand{Option: "theme", Value: "monokai"}); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Theme.Preset != "monokai" {
+		t.Errorf("Preset = %q, want monokai", cfg.Theme.Preset)
+	}
+	if _, err := ApplySetCommand(cfg, SetCommand{Option: "theme", Op: SetAdd, Value: "x"}); err == nil {
+		t.Error("+= on an enum should fail")
+	}
+}
+
+func TestApplySetCommand_NotificationsByKey(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Notifications.Enabled = true
+
+	msg, err := ApplySetCommand(cfg, SetCommand{Option: "notifications.enabled", Op: SetToggle})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Notifications.Enabled || msg != "notifications = off" {
+		t.Errorf("Enabled = %v, message = %q", cfg.Notifications.Enabled, msg)
+	}
+}
+
+func TestRuntimeOptionValues(t *testing.T) {
+	values := RuntimeOptionValues()
+	if got := values["mouse"]; len(got) != 2 || got[0] != "on" || got[1] != "off" {
+		t.Errorf("mouse values = %v, want [on off]", got)
+	}
+	if got := values["theme"]; len(got) != len(config.ThemePresets) {
+		t.Errorf("theme values = %v, want the presets", got)
+	}
+	if _, ok := values["messages_limit"]; ok {
+		t.Error("numbers should not offer value completion")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Writing options back to config.toml
+// ---------------------------------------------------------------------------
+
+func TestWriteRuntimeOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeTestConfig(t, path, "# my settings\nmessages_limit = 50 # per page\n\n[timestamps]\n# 12h clock\nformat = \"3:04PM\"\n")
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := WriteRuntimeOptions(cfg, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 0 {
+		t.Errorf("unchanged config wrote %v", names)
+	}
+
+	cfg.MessagesLimit = 80
+	cfg.Timestamps.Format = "15:04"
+	cfg.Mouse = !cfg.Mouse
+	names, err = WriteRuntimeOptions(cfg, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(names, ","); got != "mouse,messages_limit,timestamp_format" {
+		t.Errorf("wrote %s", got)
+	}
+
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{"# my settings\n", "messages_limit = 80 # per page\n", "# 12h clock\nformat = \"15:04\"\n", "mouse = false\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("config file missing %q:\n%s", want, data)
+		}
+	}
+
+	reloaded, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ChangedOptions(cfg, reloaded)) != 0 {
+		t.Errorf("options still differ after writing: %v", ChangedOptions(cfg, reloaded))
+	}
+}
+
 // ---------------------------------------------------------------------------
 // QueryOption tests
 // ---------------------------------------------------------------------------
@@ -502,6 +704,9 @@ func TestRuntimeOptionNames_ReturnsAllOptions(t *testing.T) {
 	expected := map[string]bool{
 		"mouse": true, "timestamps": true, "markdown": true,
 		"typing": true, "presence": true, "date_separator": true,
+		"notifications": true, "messages_limit": true, "autocomplete_limit": true,
+		"timestamp_format": true, "download_dir": true, "syntax_theme": true,
+		"theme": true,
 	}
 	for _, name := range names {
 		if !expected[name] {
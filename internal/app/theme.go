@@ -0,0 +1,57 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/m96-chan/Slacko/internal/config"
+)
+
+// applyTheme switches the running UI to the named preset. The config file's
+// own [theme] overrides are applied on top, exactly as when the preset is
+// set in the file. Must be called from the tview event loop.
+func (a *App) applyTheme(name string) {
+	a.updateConfig(func(c *config.Config) {
+		c.Theme = config.LoadTheme(c.Path, name)
+	})
+	a.chatView.ApplyConfig()
+}
+
+// cmdTheme handles ":theme [name]": without a name it opens the theme picker.
+func (a *App) cmdTheme(args string) {
+	name := strings.TrimSpace(args)
+	if name == "" {
+		a.chatView.ThemePicker.SetThemes(config.ThemePresets, a.Config.Theme.Preset)
+		a.chatView.ShowThemePicker()
+		return
+	}
+	if !config.IsThemePreset(name) {
+		a.showCommandFeedback(fmt.Sprintf("Unknown theme %q (available: %s)", name, strings.Join(config.ThemePresets, ", ")))
+		return
+	}
+	a.applyTheme(name)
+	a.showCommandFeedback("Theme: " + name)
+}
+
+// selectTheme applies the theme chosen in the picker and, when save is set,
+// writes it to the config file as theme.preset.
+func (a *App) selectTheme(name string, save bool) {
+	a.chatView.HideThemePicker()
+	a.applyTheme(name)
+	if !save {
+		a.showCommandFeedback("Theme: " + name)
+		return
+	}
+
+	if a.Config.Path == "" {
+		a.showCommandFeedback("Theme: " + name + " (no config file to save to)")
+		return
+	}
+	if err := config.SetValue(a.Config.Path, "theme.preset", name); err != nil {
+		slog.Warn("failed to save theme", "theme", name, "error", err)
+		a.showCommandFeedback("Theme: " + name + " (not saved: " + err.Error() + ")")
+		return
+	}
+	a.showCommandFeedback(fmt.Sprintf("Theme: %s (saved to %s)", name, a.Config.Path))
+}
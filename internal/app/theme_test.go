@@ -0,0 +1,61 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/m96-chan/Slacko/internal/config"
+	"github.com/m96-chan/Slacko/internal/ui/chat"
+)
+
+func newThemeTestApp(t *testing.T, content string) *App {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeTestConfig(t, path, content)
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{Config: cfg, tview: newTestApp()}
+	a.chatView = chat.New(a.tview, cfg)
+	return a
+}
+
+func TestCmdThemeAppliesPreset(t *testing.T) {
+	a := newThemeTestApp(t, "[theme]\npreset = \"default\"\n")
+
+	a.cmdTheme("light")
+
+	if a.Config.Theme.Preset != "light" {
+		t.Errorf("Preset = %q, want light", a.Config.Theme.Preset)
+	}
+	if a.Config.Theme.StatusBar != config.BuiltinTheme("light").StatusBar {
+		t.Error("status bar theme was not switched to the light preset")
+	}
+
+	a.cmdTheme("nope")
+	if a.Config.Theme.Preset != "light" {
+		t.Errorf("unknown theme changed Preset to %q", a.Config.Theme.Preset)
+	}
+}
+
+func TestSelectThemeSavesToConfig(t *testing.T) {
+	a := newThemeTestApp(t, "# keep me\n[theme]\npreset = \"default\"\n")
+
+	a.selectTheme("monokai", false)
+	data, _ := os.ReadFile(a.Config.Path)
+	if strings.Contains(string(data), "monokai") {
+		t.Fatal("selecting without save wrote the config file")
+	}
+
+	a.selectTheme("monokai", true)
+	data, _ = os.ReadFile(a.Config.Path)
+	if want := "# keep me\n[theme]\npreset = \"monokai\"\n"; string(data) != want {
+		t.Errorf("config file = %q, want %q", data, want)
+	}
+	if a.Config.Theme.Preset != "monokai" {
+		t.Errorf("Preset = %q, want monokai", a.Config.Theme.Preset)
+	}
+}
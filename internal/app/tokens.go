@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/m96-chan/Slacko/internal/keyring"
+	"github.com/m96-chan/Slacko/internal/oauth"
+)
+
+// tokenRetryInterval is the pause between attempts to refresh a rotating
+// token, both after a refresh and after a failure.
+const tokenRetryInterval = time.Minute
+
+// oauthParams returns the OAuth settings used to refresh rotating tokens.
+func (a *App) oauthParams() oauth.Params {
+	cfg := a.config().OAuth
+	return oauth.Params{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		ProxyURL:     cfg.ProxyURL,
+	}
+}
+
+// keepTokenFresh renews the user token of a's workspace shortly before it
+// expires, for workspaces with token rotation, until ctx is done. Failed
+// refreshes are retried while the token is still valid; after that the
+// user is asked to sign in again.
+func (a *App) keepTokenFresh(ctx context.Context) {
+	var minWait time.Duration
+	for {
+		w, ok := keyring.FindWorkspace(a.slack.TeamID)
+		if !ok || w.RefreshKey == "" || w.ExpiresAt.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(max(time.Until(w.ExpiresAt)-oauth.RefreshMargin, minWait))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		minWait = tokenRetryInterval
+
+		tokens, err := oauth.FreshTokens(ctx, a.oauthParams(), w)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			if tokens.UserToken != a.slack.Token() {
+				a.slack.SetUserToken(tokens.UserToken)
+				slog.Info("refreshed user token", "workspace", w.Name, "expires", tokens.ExpiresAt)
+			}
+			continue
+		}
+
+		slog.Warn("failed to refresh user token", "workspace", w.Name, "error", err)
+		if time.Until(w.ExpiresAt) > tokenRetryInterval {
+			continue
+		}
+		a.tview.QueueUpdateDraw(func() { a.reauthenticate(err) })
+		return
+	}
+}
+
+// reauthenticate stops a's workspace, whose token could not be refreshed.
+// When it is on screen, the login form replaces it so the user can sign in
+// again; a background workspace is only marked as signed out and asks for
+// the sign-in when switched to. Other workspaces stay connected. Must be
+// called from the tview event loop.
+func (a *App) reauthenticate(err error) {
+	active := a.workspaces.isActive(a)
+	if a.cancel != nil {
+		a.cancel()
+		a.cancel = nil
+	}
+	a.workspaces.remove(a)
+
+	if !active {
+		a.workspaces.expire(a.slack.TeamID)
+		cur := a.activeApp()
+		cur.refreshWorkspaceBadges()
+		if cur.chatView != nil {
+			cur.showCommandFeedback(fmt.Sprintf("Signed out of %s (%v); switch to it to sign in again", a.slack.TeamName, err))
+		}
+		return
+	}
+
+	a.showReauth(fmt.Sprintf("Your session for %s has expired (%v). Sign in again to continue.", a.slack.TeamName, err))
+}
+
+// freshDefaultToken returns the default user token, refreshed first when it
+// belongs to a registered workspace whose rotating token is about to
+// expire.
+func (a *App) freshDefaultToken(user string) (string, error) {
+	id := keyring.DefaultWorkspaceID()
+	if id == "" {
+		return user, nil
+	}
+	w, ok := keyring.FindWorkspace(id)
+	if !ok {
+		return user, nil
+	}
+	tokens, err := oauth.FreshTokens(context.Background(), a.oauthParams(), w)
+	if err != nil {
+		return "", fmt.Errorf("refresh token for %s: %w", w.Name, err)
+	}
+	return tokens.UserToken, nil
+}
@@ -0,0 +1,636 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/slack-go/slack"
+
+	"github.com/m96-chan/Slacko/internal/keyring"
+	"github.com/m96-chan/Slacko/internal/oauth"
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
+	"github.com/m96-chan/Slacko/internal/ui/chat"
+	"github.com/m96-chan/Slacko/internal/ui/login"
+)
+
+// errConnecting is returned by connectWorkspace while another connection
+// attempt to the same workspace is in flight.
+var errConnecting = errors.New("already connecting")
+
+// workspaceSet holds the App of every connected workspace, keyed by team
+// ID. Each workspace App has its own Slack client, event loop, state and
+// chat view, and shares the tview application, config and notifier of the
+// App created by New. Only the active workspace is shown; the others keep
+// receiving events in the background.
+type workspaceSet struct {
+	mu         sync.Mutex
+	apps       map[string]*App
+	active     *App
+	registry   []keyring.Workspace    // registered workspaces, as last read
+	connecting map[string]bool        // team IDs with a connection in flight
+	failed     map[string]bool        // team IDs that could not be connected
+	expired    map[string]bool        // team IDs signed out in the background
+	sockets    map[string]*socketConn // Socket Mode connections by app token
+}
+
+// socketConn is a Socket Mode connection shared by the workspaces signed in
+// with one app token.
+type socketConn struct {
+	dispatcher *slackclient.Dispatcher
+	cancel     context.CancelFunc
+}
+
+// newWorkspaceSet creates an empty workspace set.
+func newWorkspaceSet() *workspaceSet {
+	return &workspaceSet{
+		apps:       make(map[string]*App),
+		connecting: make(map[string]bool),
+		failed:     make(map[string]bool),
+		expired:    make(map[string]bool),
+		sockets:    make(map[string]*socketConn),
+	}
+}
+
+// add registers the App of a connected workspace.
+func (ws *workspaceSet) add(a *App) {
+	if ws == nil {
+		return
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.apps[a.slack.TeamID] = a
+	delete(ws.failed, a.slack.TeamID)
+	delete(ws.expired, a.slack.TeamID)
+}
+
+// remove forgets the App of a workspace that was shut down.
+func (ws *workspaceSet) remove(a *App) {
+	if ws == nil {
+		return
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.apps[a.slack.TeamID] == a {
+		delete(ws.apps, a.slack.TeamID)
+	}
+	if ws.active == a {
+		ws.active = nil
+	}
+}
+
+// expire records that the session of teamID ended while the workspace was
+// in the background. It is not reconnected until the user signs in again.
+func (ws *workspaceSet) expire(teamID string) {
+	if ws == nil {
+		return
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.expired[teamID] = true
+}
+
+// isExpired reports whether the session of teamID ended in the background.
+func (ws *workspaceSet) isExpired(teamID string) bool {
+	if ws == nil {
+		return false
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.expired[teamID]
+}
+
+// find returns the App of the workspace with the given team ID, or nil.
+func (ws *workspaceSet) find(teamID string) *App {
+	if ws == nil {
+		return nil
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.apps[teamID]
+}
+
+// all returns the Apps of all connected workspaces.
+func (ws *workspaceSet) all() []*App {
+	if ws == nil {
+		return nil
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	apps := make([]*App, 0, len(ws.apps))
+	for _, a := range ws.apps {
+		apps = append(apps, a)
+	}
+	return apps
+}
+
+// current returns the App of the workspace being shown, or nil.
+func (ws *workspaceSet) current() *App {
+	if ws == nil {
+		return nil
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.active
+}
+
+// setActive records a as the workspace being shown.
+func (ws *workspaceSet) setActive(a *App) {
+	if ws == nil {
+		return
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.active = a
+}
+
+// isActive reports whether a is the workspace being shown. Without a
+// workspace set, a is the only workspace.
+func (ws *workspaceSet) isActive(a *App) bool {
+	if ws == nil {
+		return true
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.active == a
+}
+
+// reset forgets all workspaces and returns their Apps.
+func (ws *workspaceSet) reset() []*App {
+	apps := ws.all()
+	if ws == nil {
+		return nil
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	clear(ws.apps)
+	clear(ws.failed)
+	clear(ws.expired)
+	ws.active = nil
+	return apps
+}
+
+// claim marks teamID as being connected. It returns false when a
+// connection attempt is already in flight.
+func (ws *workspaceSet) claim(teamID string) bool {
+	if ws == nil {
+		return true
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.connecting[teamID] {
+		return false
+	}
+	ws.connecting[teamID] = true
+	return true
+}
+
+// release ends the connection attempt for teamID. failed records whether
+// it succeeded, so that background reconnects skip broken workspaces.
+func (ws *workspaceSet) release(teamID string, failed bool) {
+	if ws == nil {
+		return
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	delete(ws.connecting, teamID)
+	if failed {
+		ws.failed[teamID] = true
+	}
+}
+
+// setRegistry records the registered workspaces.
+func (ws *workspaceSet) setRegistry(registry []keyring.Workspace) {
+	if ws == nil {
+		return
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.registry = registry
+}
+
+// unconnected returns the registered workspaces that have no App, no
+// connection in flight, no failed attempt and no expired session.
+func (ws *workspaceSet) unconnected() []keyring.Workspace {
+	if ws == nil {
+		return nil
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	var out []keyring.Workspace
+	for _, w := range ws.registry {
+		if ws.apps[w.ID] == nil && !ws.connecting[w.ID] && !ws.failed[w.ID] && !ws.expired[w.ID] {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// runSocketMode delivers the Socket Mode events of client's workspace to
+// handler until ctx is done. Slack spreads the events of an app over all of
+// its open connections, so the workspaces signed in with the same app token
+// share one connection, which hands each event to the workspace it belongs
+// to. The connection is closed when its last workspace stops.
+func (ws *workspaceSet) runSocketMode(ctx context.Context, client *slackclient.Client, handler *slackclient.EventHandler) error {
+	if ws == nil {
+		return client.RunSocketMode(ctx, handler)
+	}
+
+	key := client.AppToken()
+	ws.mu.Lock()
+	conn := ws.sockets[key]
+	if conn == nil {
+		connCtx, cancel := context.WithCancel(context.Background())
+		conn = &socketConn{dispatcher: slackclient.NewDispatcher(), cancel: cancel}
+		ws.sockets[key] = conn
+		go func() {
+			err := client.RunSocketModeShared(connCtx, conn.dispatcher)
+			ws.mu.Lock()
+			if ws.sockets[key] == conn {
+				delete(ws.sockets, key)
+			}
+			ws.mu.Unlock()
+			if err != nil && connCtx.Err() == nil {
+				slog.Error("socket mode connection exited", "error", err)
+			}
+		}()
+	}
+	remove := conn.dispatcher.Add(client.TeamID, handler)
+	ws.mu.Unlock()
+
+	<-ctx.Done()
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	remove()
+	if conn.dispatcher.Len() == 0 && ws.sockets[key] == conn {
+		conn.cancel()
+		delete(ws.sockets, key)
+	}
+	return nil
+}
+
+// newWorkspaceApp creates the App for a workspace connected with client,
+// sharing a's tview application, config, notifier and workspace set.
+func (a *App) newWorkspaceApp(client *slackclient.Client) *App {
+	return &App{
+		Config:     a.Config,
+		cfgState:   a.cfgState,
+		tview:      a.tview,
+		notifier:   a.notifier,
+		workspaces: a.workspaces,
+		slack:      client,
+		users:      make(map[string]slack.User),
+		dmSet:      make(map[string]bool),
+		lastRead:   make(map[string]string),
+		pinnedMsgs: make(map[string]map[string]bool),
+	}
+}
+
+// openWorkspace creates the App for client and shows it, handing over a
+// permalink queued with OpenOnStart. Must be called from the tview event
+// loop.
+func (a *App) openWorkspace(client *slackclient.Client) {
+	w := a.newWorkspaceApp(client)
+	a.mu.Lock()
+	w.pendingLink, a.pendingLink = a.pendingLink, nil
+	a.mu.Unlock()
+	w.showMain()
+}
+
+// activeApp returns the App of the workspace being shown, or a itself
+// when no workspace is.
+func (a *App) activeApp() *App {
+	if cur := a.workspaces.current(); cur != nil {
+		return cur
+	}
+	return a
+}
+
+// workspaceApps returns the Apps of all connected workspaces, or just a
+// when it has no workspace set.
+func (a *App) workspaceApps() []*App {
+	if a.workspaces == nil {
+		return []*App{a}
+	}
+	return a.workspaces.all()
+}
+
+// showWorkspace makes a's workspace the one on screen. Its chat view is
+// kept as it was, so switching back and forth is instant. Must be called
+// from the tview event loop.
+func (a *App) showWorkspace() {
+	if a.chatView == nil {
+		a.startWorkspace()
+	}
+	a.workspaces.setActive(a)
+	// Pick up config changes made while another workspace was shown.
+	a.chatView.ApplyConfig()
+	a.tview.SetRoot(a.chatView, true)
+	a.chatView.FocusPanel(a.chatView.ActivePanel())
+	a.refreshWorkspaceBadges()
+
+	// Messages that arrived in the open channel while hidden are read now.
+	a.mu.Lock()
+	ch := a.currentChannel
+	a.mu.Unlock()
+	if ch != "" && a.chatView.ChannelsTree.UnreadCount(ch) > 0 {
+		if ts := a.chatView.MessagesList.LatestTimestamp(); ts != "" {
+			a.markChannelRead(ch, ts)
+		}
+	}
+}
+
+// connectWorkspace creates a client and App for a registered workspace.
+// The caller starts or shows it.
+func (a *App) connectWorkspace(w keyring.Workspace) (*App, error) {
+	if !a.workspaces.claim(w.ID) {
+		return nil, errConnecting
+	}
+
+	tokens, err := oauth.FreshTokens(context.Background(), a.oauthParams(), w)
+	if err != nil {
+		a.workspaces.release(w.ID, true)
+		return nil, fmt.Errorf("failed to get tokens for %s: %w", w.Name, err)
+	}
+	client, err := slackclient.New(tokens.UserToken, tokens.AppToken)
+	if err != nil {
+		a.workspaces.release(w.ID, true)
+		return nil, fmt.Errorf("failed to connect to %s: %w", w.Name, err)
+	}
+
+	s := a.newWorkspaceApp(client)
+	a.workspaces.add(s)
+	a.workspaces.release(w.ID, false)
+	return s, nil
+}
+
+// connectWorkspaces connects, in the background, every registered
+// workspace that is not connected yet, so that all of them receive events
+// and notifications.
+func (a *App) connectWorkspaces() {
+	for _, w := range a.workspaces.unconnected() {
+		go func() {
+			s, err := a.connectWorkspace(w)
+			if err != nil {
+				if !errors.Is(err, errConnecting) {
+					slog.Warn("failed to connect workspace", "workspace", w.Name, "error", err)
+				}
+				return
+			}
+			slog.Info("workspace connected in background", "workspace", w.Name)
+			a.tview.QueueUpdateDraw(func() {
+				// A switch may have started it already.
+				if s.chatView == nil {
+					s.startWorkspace()
+				}
+				a.refreshWorkspaceBadges()
+			})
+		}()
+	}
+}
+
+// populateWorkspacePicker reloads the stored workspaces and refreshes the
+// workspace picker and status bar badges.
+func (a *App) populateWorkspacePicker() {
+	ws, err := keyring.ListWorkspaces()
+	if err != nil {
+		slog.Warn("failed to list workspaces", "error", err)
+		return
+	}
+	a.workspaces.setRegistry(ws)
+	a.tview.QueueUpdateDraw(a.refreshWorkspaceBadges)
+}
+
+// workspaceEntries lists the registered workspaces, plus any connected one
+// missing from the registry, with the unread badges of the connected ones
+// and the ones signed out in the background marked.
+// Must be called from the tview event loop.
+func (a *App) workspaceEntries() []chat.WorkspaceEntry {
+	var registry []keyring.Workspace
+	if a.workspaces != nil {
+		a.workspaces.mu.Lock()
+		registry = a.workspaces.registry
+		a.workspaces.mu.Unlock()
+	}
+	apps := a.workspaceApps()
+
+	entry := func(id, name string) chat.WorkspaceEntry {
+		e := chat.WorkspaceEntry{ID: id, Name: name, Offline: true, Expired: a.workspaces.isExpired(id)}
+		for _, w := range apps {
+			if w.slack != nil && w.slack.TeamID == id {
+				e.Offline = false
+				if w.chatView != nil {
+					e.Unread, e.Mentions = w.chatView.ChannelsTree.UnreadTotals()
+				}
+			}
+		}
+		return e
+	}
+
+	entries := make([]chat.WorkspaceEntry, 0, len(registry))
+	for _, w := range registry {
+		entries = append(entries, entry(w.ID, w.Name))
+	}
+	for _, w := range apps {
+		if w.slack == nil {
+			continue
+		}
+		if !slices.ContainsFunc(entries, func(e chat.WorkspaceEntry) bool { return e.ID == w.slack.TeamID }) {
+			entries = append(entries, entry(w.slack.TeamID, w.slack.TeamName))
+		}
+	}
+	return entries
+}
+
+// refreshWorkspaceBadges updates the workspace picker and the status bar
+// badges of the workspace on screen. Must be called from the tview event
+// loop.
+func (a *App) refreshWorkspaceBadges() {
+	cur := a.activeApp()
+	if cur.chatView == nil || cur.slack == nil {
+		return
+	}
+	entries := a.workspaceEntries()
+	cur.chatView.WorkspacePicker.SetCurrentWorkspace(cur.slack.TeamID)
+	cur.chatView.WorkspacePicker.SetWorkspaces(entries)
+	others := slices.DeleteFunc(slices.Clone(entries), func(e chat.WorkspaceEntry) bool {
+		return e.ID == cur.slack.TeamID
+	})
+	cur.chatView.StatusBar.SetWorkspaceBadges(others)
+}
+
+// switchWorkspace shows the workspace with the given team ID, connecting to
+// it first when needed. A workspace signed out in the background asks for a
+// new sign-in instead. It returns the workspace's App, or nil when the
+// workspace cannot be reached.
+func (a *App) switchWorkspace(workspaceID string) *App {
+	target := a.workspaces.find(workspaceID)
+	if target == nil {
+		ws, err := keyring.ListWorkspaces()
+		if err != nil {
+			slog.Error("failed to list workspaces", "error", err)
+			a.showCommandFeedback("Failed to list workspaces")
+			return nil
+		}
+		i := slices.IndexFunc(ws, func(w keyring.Workspace) bool { return w.ID == workspaceID })
+		if i < 0 {
+			a.showCommandFeedback("Workspace not found: " + workspaceID)
+			return nil
+		}
+		if a.workspaces.isExpired(workspaceID) {
+			a.tview.QueueUpdateDraw(func() { a.activeApp().signInAgain(ws[i]) })
+			return nil
+		}
+
+		target, err = a.connectWorkspace(ws[i])
+		if errors.Is(err, errConnecting) {
+			a.showCommandFeedback("Still connecting to " + ws[i].Name)
+			return nil
+		}
+		if err != nil {
+			slog.Error("failed to connect workspace", "workspace", ws[i].Name, "error", err)
+			a.showCommandFeedback(err.Error())
+			return nil
+		}
+	}
+
+	a.tview.QueueUpdateDraw(target.showWorkspace)
+	return target
+}
+
+// cmdWorkspace handles :workspace. Without arguments it opens the workspace
+// picker; "add" signs in to another workspace and "remove [name]" signs out
+// of one, the one on screen by default.
+func (a *App) cmdWorkspace(args string) {
+	sub, ref, _ := strings.Cut(strings.TrimSpace(args), " ")
+	switch sub {
+	case "":
+		a.populateWorkspacePicker()
+		a.tview.QueueUpdateDraw(func() {
+			a.chatView.ShowWorkspacePicker()
+		})
+	case "add":
+		a.addWorkspace()
+	case "remove":
+		a.confirmRemoveWorkspace(strings.TrimSpace(ref))
+	default:
+		a.showCommandFeedback("Usage: :workspace [add|remove [name]]")
+	}
+}
+
+// addWorkspace shows the login form over the chat view to sign in to
+// another workspace. The workspaces already open keep running, and the
+// default tokens are left alone. Must be called from the tview event loop.
+func (a *App) addWorkspace() {
+	form := login.NewAdd(a.tview, a.Config, a.workspaceAdded, a.chatView.HideDialog)
+	a.chatView.ShowDialog(form, 70, 12)
+}
+
+// signInAgain shows the login form over the chat view for w, whose session
+// expired while it was in the background, and shows w once signed in. Must
+// be called from the tview event loop.
+func (a *App) signInAgain(w keyring.Workspace) {
+	form := login.NewAdd(a.tview, a.Config, func(client *slackclient.Client) {
+		if client.TeamID != w.ID {
+			a.workspaceAdded(client)
+			return
+		}
+		a.chatView.HideDialog()
+		if ws, err := keyring.ListWorkspaces(); err == nil {
+			a.workspaces.setRegistry(ws)
+		}
+		a.newWorkspaceApp(client).showMain()
+	}, a.chatView.HideDialog)
+	a.chatView.ShowDialog(form, 70, 12)
+}
+
+// workspaceAdded connects the workspace signed in to with :workspace add in
+// the background and offers to switch to it. Signing in to a workspace that
+// is already open hands its new token to the running client. Must be called
+// from the tview event loop.
+func (a *App) workspaceAdded(client *slackclient.Client) {
+	a.chatView.HideDialog()
+	if ws, err := keyring.ListWorkspaces(); err == nil {
+		a.workspaces.setRegistry(ws)
+	}
+
+	target := a.workspaces.find(client.TeamID)
+	switch {
+	case target == nil:
+		target = a.newWorkspaceApp(client)
+		target.startWorkspace()
+	case target.slack != nil:
+		target.slack.SetUserToken(client.Token())
+	}
+	a.refreshWorkspaceBadges()
+
+	if target == a.activeApp() {
+		a.showCommandFeedback("Signed in to " + client.TeamName + " again")
+		return
+	}
+	a.chatView.ShowConfirm(fmt.Sprintf("Added %s. Switch to it now?", client.TeamName), "Switch", target.showWorkspace)
+}
+
+// confirmRemoveWorkspace asks before removing the registered workspace
+// named ref, or the one on screen when ref is empty. Must be called from the
+// tview event loop.
+func (a *App) confirmRemoveWorkspace(ref string) {
+	if ref == "" && a.slack != nil {
+		ref = a.slack.TeamID
+	}
+	w, ok := keyring.FindWorkspace(ref)
+	if !ok {
+		a.showCommandFeedback("Workspace not found: " + ref)
+		return
+	}
+	a.chatView.ShowConfirm(fmt.Sprintf("Remove %s and delete its stored tokens?", w.Name), "Remove", func() {
+		a.removeWorkspace(w)
+	})
+}
+
+// removeWorkspace disconnects w and deletes it and its tokens, like
+// `slacko workspace remove`. When it was on screen, another open workspace
+// is shown, or the login form when none is left. Must be called from the
+// tview event loop.
+func (a *App) removeWorkspace(w keyring.Workspace) {
+	wasDefault := keyring.DefaultWorkspaceID() == w.ID
+	if err := keyring.RemoveWorkspace(w.ID); err != nil {
+		slog.Error("failed to remove workspace", "workspace", w.Name, "error", err)
+		a.showCommandFeedback("Failed to remove " + w.Name + ": " + err.Error())
+		return
+	}
+	if wasDefault {
+		_ = keyring.DeleteUserToken()
+		_ = keyring.DeleteAppToken()
+	}
+	if ws, err := keyring.ListWorkspaces(); err == nil {
+		a.workspaces.setRegistry(ws)
+	}
+
+	s := a.workspaces.find(w.ID)
+	shown := s != nil && s == a.workspaces.current()
+	if s != nil {
+		if s.cancel != nil {
+			s.cancel()
+			s.cancel = nil
+		}
+		a.workspaces.remove(s)
+	}
+	slog.Info("workspace removed", "workspace", w.Name)
+
+	if !shown {
+		a.refreshWorkspaceBadges()
+		a.showCommandFeedback("Removed " + w.Name)
+		return
+	}
+	for _, other := range a.workspaces.all() {
+		other.showWorkspace()
+		other.showCommandFeedback("Removed " + w.Name)
+		return
+	}
+	a.showLogin()
+}
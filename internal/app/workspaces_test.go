@@ -0,0 +1,283 @@
+package app
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+	gokeyring "github.com/zalando/go-keyring"
+
+	"github.com/m96-chan/Slacko/internal/consts"
+	"github.com/m96-chan/Slacko/internal/keyring"
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
+	"github.com/m96-chan/Slacko/internal/ui/chat"
+)
+
+// newWorkspaceTestApps returns a root App with two connected workspaces,
+// Alpha (T1) and Beta (T2), and a registered but unconnected Gamma (T3).
+func newWorkspaceTestApps(t *testing.T) (root, alpha, beta *App) {
+	t.Helper()
+	root = newThemeTestApp(t, "")
+	root.chatView = nil
+	root.workspaces = newWorkspaceSet()
+	root.workspaces.setRegistry([]keyring.Workspace{
+		{ID: "T1", Name: "Alpha"},
+		{ID: "T2", Name: "Beta"},
+		{ID: "T3", Name: "Gamma"},
+	})
+
+	newWorkspace := func(id, name string) *App {
+		w := root.newWorkspaceApp(&slackclient.Client{TeamID: id, TeamName: name, UserID: "U1"})
+		w.chatView = chat.New(root.tview, root.Config)
+		root.workspaces.add(w)
+		return w
+	}
+	alpha = newWorkspace("T1", "Alpha")
+	beta = newWorkspace("T2", "Beta")
+
+	general := slack.Channel{}
+	general.ID, general.Name, general.IsChannel = "C1", "general", true
+	beta.chatView.ChannelsTree.Populate([]slack.Channel{general}, nil, "U1")
+	beta.chatView.ChannelsTree.SetUnreadCount("C1", 3)
+	beta.chatView.ChannelsTree.SetMentionCount("C1", 1)
+	return root, alpha, beta
+}
+
+func TestWorkspaceEntries(t *testing.T) {
+	root, _, _ := newWorkspaceTestApps(t)
+
+	got := root.workspaceEntries()
+	want := []chat.WorkspaceEntry{
+		{ID: "T1", Name: "Alpha"},
+		{ID: "T2", Name: "Beta", Unread: 3, Mentions: 1},
+		{ID: "T3", Name: "Gamma", Offline: true},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("workspaceEntries() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestRefreshWorkspaceBadges(t *testing.T) {
+	root, alpha, beta := newWorkspaceTestApps(t)
+	root.workspaces.setActive(alpha)
+
+	root.refreshWorkspaceBadges()
+	if got := alpha.chatView.StatusBar.GetText(false); !strings.Contains(got, "Beta (3, @1)") {
+		t.Errorf("status bar = %q, want Beta's badge", got)
+	}
+
+	// Badges follow unread changes in background workspaces.
+	beta.chatView.ChannelsTree.SetOnUnreadChanged(root.refreshWorkspaceBadges)
+	beta.chatView.ChannelsTree.SetUnreadCount("C1", 0)
+	if got := alpha.chatView.StatusBar.GetText(false); strings.Contains(got, "Beta") {
+		t.Errorf("status bar = %q, want no badge once Beta is read", got)
+	}
+}
+
+func TestShowWorkspace(t *testing.T) {
+	root, alpha, beta := newWorkspaceTestApps(t)
+	root.workspaces.setActive(alpha)
+
+	beta.showWorkspace()
+	if root.activeApp() != beta {
+		t.Fatal("Beta is not the active workspace")
+	}
+	if !root.workspaces.isActive(beta) || root.workspaces.isActive(alpha) {
+		t.Error("isActive does not follow the switch")
+	}
+	// Beta's own status bar shows the other workspaces, which have no unreads.
+	if got := beta.chatView.StatusBar.GetText(false); strings.Contains(got, "Alpha") {
+		t.Errorf("status bar = %q, want no badges", got)
+	}
+}
+
+func TestWorkspaceSetConnecting(t *testing.T) {
+	root, _, _ := newWorkspaceTestApps(t)
+	ws := root.workspaces
+
+	unconnected := func() []string {
+		var ids []string
+		for _, w := range ws.unconnected() {
+			ids = append(ids, w.ID)
+		}
+		return ids
+	}
+	if got := unconnected(); !slices.Equal(got, []string{"T3"}) {
+		t.Fatalf("unconnected = %v, want [T3]", got)
+	}
+
+	if !ws.claim("T3") {
+		t.Fatal("claim failed")
+	}
+	if ws.claim("T3") {
+		t.Error("second claim succeeded while connecting")
+	}
+	if got := unconnected(); len(got) != 0 {
+		t.Errorf("unconnected while connecting = %v", got)
+	}
+
+	// A failed workspace is not retried in the background.
+	ws.release("T3", true)
+	if got := unconnected(); len(got) != 0 {
+		t.Errorf("unconnected after failure = %v", got)
+	}
+
+	if apps := ws.reset(); len(apps) != 2 {
+		t.Errorf("reset returned %d apps, want 2", len(apps))
+	}
+	if ws.current() != nil || ws.find("T1") != nil {
+		t.Error("reset kept workspaces")
+	}
+}
+
+func TestWorkspaceSetRemove(t *testing.T) {
+	root, alpha, beta := newWorkspaceTestApps(t)
+	ws := root.workspaces
+	ws.setActive(alpha)
+
+	ws.remove(alpha)
+	if ws.find("T1") != nil {
+		t.Error("removed workspace still connected")
+	}
+	if ws.current() != nil {
+		t.Error("removed workspace still active")
+	}
+	if ws.find("T2") != beta {
+		t.Error("other workspace was removed too")
+	}
+
+	// A stale App does not remove the one that replaced it.
+	replacement := root.newWorkspaceApp(beta.slack)
+	ws.add(replacement)
+	ws.remove(beta)
+	if ws.find("T2") != replacement {
+		t.Error("replacement workspace was removed")
+	}
+}
+
+// useTestRegistry stores the registered workspaces of newWorkspaceTestApps
+// in a mock keyring and a temporary registry file.
+func useTestRegistry(t *testing.T) {
+	t.Helper()
+	gokeyring.MockInit()
+	orig := consts.CacheDir
+	consts.CacheDir = t.TempDir()
+	t.Cleanup(func() { consts.CacheDir = orig })
+	for _, w := range []struct{ id, name string }{{"T1", "Alpha"}, {"T2", "Beta"}, {"T3", "Gamma"}} {
+		if err := keyring.AddWorkspace(w.id, w.name, "xoxp-"+w.id, "xapp-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRemoveBackgroundWorkspace(t *testing.T) {
+	useTestRegistry(t)
+	root, alpha, _ := newWorkspaceTestApps(t)
+	alpha.showWorkspace()
+
+	w, _ := keyring.FindWorkspace("Beta")
+	alpha.removeWorkspace(w)
+
+	if root.workspaces.find("T2") != nil {
+		t.Error("removed workspace still connected")
+	}
+	if _, ok := keyring.FindWorkspace("T2"); ok {
+		t.Error("removed workspace still registered")
+	}
+	if root.activeApp() != alpha {
+		t.Error("the workspace on screen changed")
+	}
+	for _, e := range root.workspaceEntries() {
+		if e.ID == "T2" {
+			t.Error("removed workspace still listed")
+		}
+	}
+}
+
+func TestRemoveShownWorkspace(t *testing.T) {
+	useTestRegistry(t)
+	root, alpha, beta := newWorkspaceTestApps(t)
+	alpha.showWorkspace()
+
+	w, _ := keyring.FindWorkspace("Alpha")
+	alpha.removeWorkspace(w)
+
+	if root.workspaces.find("T1") != nil {
+		t.Error("removed workspace still connected")
+	}
+	if root.activeApp() != beta {
+		t.Error("another open workspace is not shown")
+	}
+}
+
+func TestConfirmRemoveWorkspace(t *testing.T) {
+	useTestRegistry(t)
+	_, alpha, _ := newWorkspaceTestApps(t)
+	alpha.showWorkspace()
+
+	alpha.confirmRemoveWorkspace("gamma")
+	if !alpha.chatView.HasPage("dialog") {
+		t.Fatal("no confirmation shown")
+	}
+	if _, ok := keyring.FindWorkspace("T3"); !ok {
+		t.Error("workspace removed before confirming")
+	}
+}
+
+func TestWorkspaceAddedAlreadyOpen(t *testing.T) {
+	useTestRegistry(t)
+	root, alpha, beta := newWorkspaceTestApps(t)
+	alpha.showWorkspace()
+
+	// Signing in to an open workspace again hands the new token to it.
+	client := &slackclient.Client{TeamID: "T2", TeamName: "Beta", UserID: "U1"}
+	client.SetUserToken("xoxp-new")
+	alpha.workspaceAdded(client)
+
+	if got := beta.slack.Token(); got != "xoxp-new" {
+		t.Errorf("token = %q, want the new one", got)
+	}
+	if root.workspaces.find("T2") != beta {
+		t.Error("open workspace was replaced")
+	}
+	if root.activeApp() != alpha || !alpha.chatView.HasPage("dialog") {
+		t.Error("want the switch offered over the workspace on screen")
+	}
+}
+
+func TestReauthenticateBackgroundWorkspace(t *testing.T) {
+	useTestRegistry(t)
+	root, alpha, _ := newWorkspaceTestApps(t)
+	alpha.showWorkspace()
+
+	beta := root.workspaces.find("T2")
+	beta.reauthenticate(errors.New("invalid_refresh_token"))
+
+	if root.activeApp() != alpha {
+		t.Error("a background workspace took over the screen")
+	}
+	if root.workspaces.find("T2") != nil {
+		t.Error("signed out workspace still connected")
+	}
+	i := slices.IndexFunc(root.workspaceEntries(), func(e chat.WorkspaceEntry) bool { return e.ID == "T2" })
+	if e := root.workspaceEntries()[i]; !e.Expired || !e.Offline {
+		t.Errorf("entry = %+v, want offline and expired", e)
+	}
+	if got := alpha.chatView.StatusBar.GetText(false); !strings.Contains(got, "Beta (signed out)") {
+		t.Errorf("status bar = %q, want Beta's signed out badge", got)
+	}
+	for _, w := range root.workspaces.unconnected() {
+		if w.ID == "T2" {
+			t.Error("signed out workspace is reconnected in the background")
+		}
+	}
+
+	// Switching to it asks for a new sign-in over the workspace on screen.
+	w, _ := keyring.FindWorkspace("T2")
+	alpha.signInAgain(w)
+	if !alpha.chatView.HasPage("dialog") {
+		t.Error("no sign-in form shown")
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Alias is the list of commands run by a user-defined alias. Each step is a
+// ":" command, a "/" command or plain text sent as a message. In TOML it is
+// either a single string or an array of strings.
+type Alias []string
+
+// UnmarshalTOML implements the toml.Unmarshaler interface.
+func (a *Alias) UnmarshalTOML(data any) error {
+	switch v := data.(type) {
+	case string:
+		*a = Alias{v}
+	case []any:
+		steps := make(Alias, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("expected command string, got %T", item)
+			}
+			steps = append(steps, s)
+		}
+		*a = steps
+	default:
+		return fmt.Errorf("expected string or array of strings for alias, got %T", data)
+	}
+	return nil
+}
+
+// aliasParamRe matches the positional parameters $1-$9, $* and the escape $$.
+var aliasParamRe = regexp.MustCompile(`\$[1-9*$]`)
+
+// Expand returns the alias steps with positional parameters replaced: $1 to
+// $9 become the whitespace-separated arguments (empty when missing), $* the
+// whole argument string and $$ a literal "$".
+func (a Alias) Expand(args string) []string {
+	args = strings.TrimSpace(args)
+	fields := strings.Fields(args)
+
+	steps := make([]string, len(a))
+	for i, step := range a {
+		steps[i] = aliasParamRe.ReplaceAllStringFunc(step, func(param string) string {
+			switch c := param[1]; c {
+			case '*':
+				return args
+			case '$':
+				return "$"
+			default:
+				if n := int(c - '1'); n < len(fields) {
+					return fields[n]
+				}
+				return ""
+			}
+		})
+	}
+	return steps
+}
+
+// aliasNameRe matches valid alias names. Commands are matched in lower case,
+// so names are too.
+var aliasNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// validateAliases checks alias names and steps. Builtin command names are
+// taken.
+func validateAliases(aliases map[string]Alias) error {
+	for name, steps := range aliases {
+		if !aliasNameRe.MatchString(name) {
+			return fmt.Errorf("aliases.%s: name must be lower case letters, digits, '-' or '_'", name)
+		}
+		if slices.Contains(BuiltinSlashCommands, name) || slices.Contains(BuiltinVimCommands, name) {
+			return fmt.Errorf("aliases.%s: %q is a builtin command", name, name)
+		}
+		if len(steps) == 0 {
+			return fmt.Errorf("aliases.%s: no commands", name)
+		}
+		for _, step := range steps {
+			if strings.TrimSpace(step) == "" {
+				return fmt.Errorf("aliases.%s: empty command", name)
+			}
+		}
+	}
+	return nil
+}
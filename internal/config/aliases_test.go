@@ -0,0 +1,56 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadAliases(t *testing.T) {
+	path := writeConfig(t, `[aliases]
+standup = ["/status :calendar: Standup for 15m", ":join #standup"]
+j = ":join #$1"
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := cfg.Aliases["standup"]; len(got) != 2 || got[1] != ":join #standup" {
+		t.Errorf("standup = %q", got)
+	}
+	if got := cfg.Aliases["j"]; len(got) != 1 || got[0] != ":join #$1" {
+		t.Errorf("j = %q, want a single step", got)
+	}
+}
+
+func TestLoadAliasesRejectsInvalid(t *testing.T) {
+	tests := map[string]string{
+		"upper case name": "[aliases]\nStandup = \":join #standup\"\n",
+		"empty list":      "[aliases]\nx = []\n",
+		"empty step":      "[aliases]\nx = [\":join #a\", \" \"]\n",
+		"wrong type":      "[aliases]\nx = 1\n",
+		"builtin name":    "[aliases]\njoin = \":join #a\"\n",
+		"builtin alias":   "[aliases]\nw = \":q\"\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, content)); err == nil {
+				t.Error("Load should fail")
+			}
+		})
+	}
+}
+
+func TestAliasExpand(t *testing.T) {
+	alias := Alias{"/status $1 $*", ":join #$2", "costs $$5 $9"}
+
+	got := alias.Expand("  :coffee:  general  ")
+	want := []string{"/status :coffee: :coffee:  general", ":join #general", "costs $5 "}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Expand = %q, want %q", got, want)
+	}
+
+	if got := alias.Expand(""); got[1] != ":join #" {
+		t.Errorf("missing argument expanded to %q, want empty", got[1])
+	}
+}
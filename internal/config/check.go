@@ -0,0 +1,222 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/m96-chan/Slacko/internal/ui/keys"
+)
+
+// Problem is an issue in a config file that Load accepts silently.
+type Problem struct {
+	Key     string // dotted TOML key, e.g. "keybinds.messages_list.up"; empty for file-wide problems
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Key == "" {
+		return p.Message
+	}
+	return p.Key + ": " + p.Message
+}
+
+// Defaults returns the embedded default config file.
+func Defaults() []byte {
+	return defaultConfig
+}
+
+// Check reads the config file at path and reports unknown keys, invalid key
+// names, conflicting bindings within a panel, unknown theme presets, invalid
+// colors and out-of-range values. A file that cannot be read or parsed is
+// returned as an error.
+func Check(path string) ([]Problem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := toml.Unmarshal(defaultConfig, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing embedded config: %w", err)
+	}
+	md, err := toml.Decode(string(data), &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("parsing config file: %w", err)
+	}
+
+	var problems []Problem
+	for _, key := range md.Undecoded() {
+		problems = append(problems, Problem{Key: key.String(), Message: "unknown key"})
+	}
+
+	cfg.Keybinds.expandLeader()
+	problems = append(problems, checkKeybinds(cfg.Keybinds)...)
+
+	if !IsThemePreset(cfg.Theme.Preset) {
+		problems = append(problems, Problem{
+			Key:     "theme.preset",
+			Message: fmt.Sprintf("unknown preset %q (available: %s)", cfg.Theme.Preset, strings.Join(ThemePresets, ", ")),
+		})
+	}
+
+	var raw map[string]any
+	if _, err := toml.Decode(string(data), &raw); err == nil {
+		if theme, ok := raw["theme"].(map[string]any); ok {
+			problems = append(problems, checkTheme("theme", theme)...)
+		}
+	}
+
+	cfg.Theme = resolveTheme(path, cfg.Theme)
+	applyDefaults(&cfg)
+	if err := validate(&cfg); err != nil {
+		problems = append(problems, Problem{Message: err.Error()})
+	}
+
+	return problems, nil
+}
+
+// checkKeybinds validates every key name and reports sequences bound to
+// more than one action within the same panel, or shadowed by a shorter
+// sequence that completes first.
+func checkKeybinds(kb Keybinds) []Problem {
+	var problems []Problem
+	if err := keys.Validate(kb.Leader); err != nil {
+		problems = append(problems, Problem{Key: "keybinds.leader", Message: err.Error()})
+	}
+
+	for _, panel := range keybindPanels(kb) {
+		actions := make(map[string][]string)
+		var order []string
+		for _, b := range panel.binds {
+			valid := true
+			for _, name := range keys.Split(b.seq) {
+				if err := keys.Validate(name); err != nil {
+					problems = append(problems, Problem{Key: panel.name + "." + b.action, Message: err.Error()})
+					valid = false
+				}
+			}
+			if !valid {
+				continue
+			}
+			if _, seen := actions[b.seq]; !seen {
+				order = append(order, b.seq)
+			}
+			if !slices.Contains(actions[b.seq], b.action) {
+				actions[b.seq] = append(actions[b.seq], b.action)
+			}
+		}
+
+		for _, seq := range order {
+			if names := actions[seq]; len(names) > 1 {
+				problems = append(problems, Problem{
+					Key:     panel.name,
+					Message: fmt.Sprintf("%q is bound to more than one action: %s", seq, strings.Join(names, ", ")),
+				})
+			}
+			for _, longer := range order {
+				if strings.HasPrefix(longer, seq+" ") {
+					problems = append(problems, Problem{
+						Key: panel.name,
+						Message: fmt.Sprintf("%q (%s) can never be typed: %q (%s) completes first",
+							longer, strings.Join(actions[longer], ", "), seq, strings.Join(actions[seq], ", ")),
+					})
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// keybindPanel is one table of keybindings: the global [keybinds] table or
+// one of its per-panel sub-tables.
+type keybindPanel struct {
+	name  string // dotted TOML key of the table
+	binds []keybind
+}
+
+// keybind is a single sequence bound to an action.
+type keybind struct {
+	action string // TOML key of the action
+	seq    string
+}
+
+// keybindPanels splits kb into its TOML tables, in declaration order.
+func keybindPanels(kb Keybinds) []keybindPanel {
+	collect := func(name string, v reflect.Value) keybindPanel {
+		panel := keybindPanel{name: name}
+		for i := range v.NumField() {
+			bind, ok := v.Field(i).Interface().(Keybind)
+			if !ok {
+				continue
+			}
+			action := v.Type().Field(i).Tag.Get("toml")
+			for _, seq := range bind {
+				panel.binds = append(panel.binds, keybind{action: action, seq: seq})
+			}
+		}
+		return panel
+	}
+
+	v := reflect.ValueOf(kb)
+	panels := []keybindPanel{collect("keybinds", v)}
+	for i := range v.NumField() {
+		if v.Field(i).Kind() == reflect.Struct {
+			panels = append(panels, collect("keybinds."+v.Type().Field(i).Tag.Get("toml"), v.Field(i)))
+		}
+	}
+	return panels
+}
+
+// checkTheme walks a raw [theme] table and reports foreground/background
+// values that tcell does not recognize (they silently render as the terminal
+// default) and unknown keys inside style tables, which the TOML metadata
+// cannot see because styles are decoded by StyleWrapper.
+func checkTheme(prefix string, table map[string]any) []Problem {
+	names := make([]string, 0, len(table))
+	isStyle := true
+	for name, v := range table {
+		names = append(names, name)
+		if _, ok := v.(map[string]any); ok {
+			isStyle = false
+		}
+	}
+	sort.Strings(names)
+
+	var problems []Problem
+	for _, name := range names {
+		key := prefix + "." + name
+		switch v := table[name].(type) {
+		case map[string]any:
+			problems = append(problems, checkTheme(key, v)...)
+		case string:
+			switch {
+			case !isStyle || prefix == "theme":
+				// theme.preset, or a container table whose unknown keys
+				// the TOML metadata already reports.
+			case name == "foreground" || name == "background":
+				if !validColor(v) {
+					problems = append(problems, Problem{Key: key, Message: fmt.Sprintf("unknown color %q", v)})
+				}
+			case name != "attributes":
+				problems = append(problems, Problem{Key: key, Message: "unknown key"})
+			}
+		}
+	}
+	return problems
+}
+
+// validColor reports whether s is a color name or hex code tcell understands.
+func validColor(s string) bool {
+	switch strings.ToLower(s) {
+	case "", "default", "-":
+		return true
+	}
+	return tcell.GetColor(s) != tcell.ColorDefault
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckDefaultsAreClean(t *testing.T) {
+	path := writeConfig(t, string(Defaults()))
+	problems, err := Check(path)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	for _, p := range problems {
+		t.Errorf("unexpected problem in defaults: %s", p)
+	}
+}
+
+func TestCheckReportsProblems(t *testing.T) {
+	path := writeConfig(t, `messages_limit = 500
+bogus = 1
+
+[keybinds]
+quit = "Ctrl-Q"
+
+[keybinds.messages_list]
+reply = "Rune[e]"
+
+[theme]
+preset = "nord"
+
+[theme.border.focused]
+foreground = "notacolor"
+colour = "red"
+`)
+	problems, err := Check(path)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	joined := strings.Join(got, "\n")
+
+	for _, want := range []string{
+		"bogus: unknown key",
+		`keybinds.quit: unknown key "Ctrl-Q"`,
+		`keybinds.messages_list: "Rune[e]" is bound to more than one action: reply, edit`,
+		`theme.preset: unknown preset "nord"`,
+		`theme.border.focused.foreground: unknown color "notacolor"`,
+		"theme.border.focused.colour: unknown key",
+		"messages_limit must be between 1 and 100",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing problem %q in:\n%s", want, joined)
+		}
+	}
+}
+
+func TestCheckParseError(t *testing.T) {
+	path := writeConfig(t, "messages_limit = \n")
+	if _, err := Check(path); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	path := writeConfig(t, `messages_limit = 50
+autocomplete_limit = 5
+custom = "x"
+
+[keybinds.messages_list]
+reply = "Rune[R]"
+edit = "Rune[e]"
+`)
+	overrides, err := Diff(path)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+
+	want := []Override{
+		{Key: "autocomplete_limit", Value: int64(5), Default: int64(10), HasDefault: true},
+		{Key: "custom", Value: "x"},
+		{Key: "keybinds.messages_list.reply", Value: "Rune[R]", Default: "Rune[r]", HasDefault: true},
+	}
+	if len(overrides) != len(want) {
+		t.Fatalf("Diff = %+v, want %+v", overrides, want)
+	}
+	for i := range want {
+		if overrides[i] != want[i] {
+			t.Errorf("overrides[%d] = %+v, want %+v", i, overrides[i], want[i])
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		in   any
+		want string
+	}{
+		{"a\"b", `"a\"b"`},
+		{int64(3), "3"},
+		{true, "true"},
+		{[]any{"x", int64(1)}, `["x", 1]`},
+		{map[string]any{"b": int64(2), "a": "1"}, `{a = "1", b = 2}`},
+	}
+	for _, tt := range tests {
+		if got := FormatValue(tt.in); got != tt.want {
+			t.Errorf("FormatValue(%v) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCheckSequenceShadowing(t *testing.T) {
+	path := writeConfig(t, `[keybinds.channels_tree]
+top = "Rune[g] Rune[g]"
+move_to_parent = "Rune[g]"
+`)
+	problems, err := Check(path)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0].Message, `"Rune[g] Rune[g]" (top) can never be typed`) {
+		t.Errorf("problems = %v, want one shadowing problem", problems)
+	}
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Custom command output modes.
+const (
+	// CommandModePreview puts the command's output in the message input
+	// for editing before it is sent.
+	CommandModePreview = "preview"
+	// CommandModeSend sends the command's output as a message.
+	CommandModeSend = "send"
+	// CommandModeFeedback shows the command's output in the status bar.
+	CommandModeFeedback = "feedback"
+)
+
+// CommandModes lists the valid custom command output modes.
+var CommandModes = []string{CommandModePreview, CommandModeSend, CommandModeFeedback}
+
+// BuiltinSlashCommands and BuiltinVimCommands are the names, including
+// short forms, of Slacko's own slash and vim-style commands. They always run
+// before custom commands and aliases, so those can't reuse the names. Both
+// lists mirror the commands in internal/ui/chat.
+var (
+	BuiltinSlashCommands = []string{
+		"help", "status", "clear-status", "topic", "leave", "search", "who",
+		"mute", "unmute", "schedule", "scheduled", "remind", "reminders", "me",
+		"create-channel", "logout", "invite",
+	}
+	BuiltinVimCommands = []string{
+		"q", "quit", "theme", "join", "leave", "search", "mark-read",
+		"mark-all-read", "open", "reconnect", "debug", "set", "source", "reload",
+		"mkconfig", "w", "bookmarks", "activity", "export", "workspace", "ws",
+		"members", "who", "create-channel", "invite", "group-dm", "gdm", "logout",
+	}
+)
+
+// defaultCommandTimeout is used when a custom command sets no timeout.
+const defaultCommandTimeout = 10 * time.Second
+
+// CustomCommand is a slash command backed by a local executable, defined in
+// a [[commands]] table. The arguments typed after the command are appended
+// to Args.
+type CustomCommand struct {
+	Name        string   `toml:"name"`
+	Description string   `toml:"description"`
+	Exec        string   `toml:"exec"`
+	Args        []string `toml:"args"`
+	// Mode is one of CommandModes; empty means CommandModePreview.
+	Mode string `toml:"mode"`
+	// Timeout is how long, in milliseconds, the command may run; 0 means
+	// the default of 10 seconds.
+	Timeout int `toml:"timeout"`
+}
+
+// OutputMode returns the command's mode, applying the default.
+func (c CustomCommand) OutputMode() string {
+	if c.Mode == "" {
+		return CommandModePreview
+	}
+	return c.Mode
+}
+
+// TimeoutDuration returns the command's timeout, applying the default.
+func (c CustomCommand) TimeoutDuration() time.Duration {
+	if c.Timeout == 0 {
+		return defaultCommandTimeout
+	}
+	return time.Duration(c.Timeout) * time.Millisecond
+}
+
+// FindCommand returns the custom command with the given name.
+func (c *Config) FindCommand(name string) (CustomCommand, bool) {
+	for _, cmd := range c.Commands {
+		if cmd.Name == name {
+			return cmd, true
+		}
+	}
+	return CustomCommand{}, false
+}
+
+// validateCommands checks custom command names, executables, modes and
+// timeouts. A name may not be used by two commands, by an alias or by a
+// builtin slash command.
+func validateCommands(cmds []CustomCommand, aliases map[string]Alias) error {
+	seen := make(map[string]bool, len(cmds))
+	for i, cmd := range cmds {
+		if !aliasNameRe.MatchString(cmd.Name) {
+			return fmt.Errorf("commands[%d]: name %q must be lower case letters, digits, '-' or '_'", i, cmd.Name)
+		}
+		if seen[cmd.Name] {
+			return fmt.Errorf("commands[%d]: duplicate command %q", i, cmd.Name)
+		}
+		seen[cmd.Name] = true
+		if slices.Contains(BuiltinSlashCommands, cmd.Name) {
+			return fmt.Errorf("commands[%d]: %q is a builtin command", i, cmd.Name)
+		}
+		if _, ok := aliases[cmd.Name]; ok {
+			return fmt.Errorf("commands[%d]: %q is also defined as an alias", i, cmd.Name)
+		}
+		if cmd.Exec == "" {
+			return fmt.Errorf("commands[%d] (%s): exec is required", i, cmd.Name)
+		}
+		if cmd.Mode != "" && !slices.Contains(CommandModes, cmd.Mode) {
+			return fmt.Errorf("commands[%d] (%s): mode must be one of %v, got %q", i, cmd.Name, CommandModes, cmd.Mode)
+		}
+		if cmd.Timeout < 0 {
+			return fmt.Errorf("commands[%d] (%s): timeout must be >= 0, got %d", i, cmd.Name, cmd.Timeout)
+		}
+	}
+	return nil
+}
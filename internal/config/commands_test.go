@@ -0,0 +1,63 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadCommands(t *testing.T) {
+	path := writeConfig(t, `[[commands]]
+name = "jira"
+exec = "jira-link"
+args = ["--format", "slack"]
+mode = "send"
+timeout = 500
+
+[[commands]]
+name = "oncall"
+exec = "oncall"
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	jira, ok := cfg.FindCommand("jira")
+	if !ok {
+		t.Fatal("jira command not found")
+	}
+	if jira.OutputMode() != CommandModeSend || jira.TimeoutDuration() != 500*time.Millisecond {
+		t.Errorf("jira mode/timeout = %q/%v", jira.OutputMode(), jira.TimeoutDuration())
+	}
+	if len(jira.Args) != 2 || jira.Args[1] != "slack" {
+		t.Errorf("jira args = %q", jira.Args)
+	}
+
+	oncall, _ := cfg.FindCommand("oncall")
+	if oncall.OutputMode() != CommandModePreview || oncall.TimeoutDuration() != defaultCommandTimeout {
+		t.Errorf("oncall defaults = %q/%v", oncall.OutputMode(), oncall.TimeoutDuration())
+	}
+
+	if _, ok := cfg.FindCommand("nope"); ok {
+		t.Error("FindCommand found an undefined command")
+	}
+}
+
+func TestLoadCommandsRejectsInvalid(t *testing.T) {
+	tests := map[string]string{
+		"missing exec":   "[[commands]]\nname = \"x\"\n",
+		"bad name":       "[[commands]]\nname = \"X Y\"\nexec = \"x\"\n",
+		"bad mode":       "[[commands]]\nname = \"x\"\nexec = \"x\"\nmode = \"loud\"\n",
+		"negative":       "[[commands]]\nname = \"x\"\nexec = \"x\"\ntimeout = -1\n",
+		"duplicate":      "[[commands]]\nname = \"x\"\nexec = \"x\"\n[[commands]]\nname = \"x\"\nexec = \"y\"\n",
+		"alias conflict": "[aliases]\nx = \":q\"\n[[commands]]\nname = \"x\"\nexec = \"x\"\n",
+		"builtin name":   "[[commands]]\nname = \"search\"\nexec = \"x\"\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, content)); err == nil {
+				t.Error("Load should fail")
+			}
+		})
+	}
+}
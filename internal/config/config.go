@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 	"github.com/m96-chan/Slacko/internal/consts"
+	"github.com/m96-chan/Slacko/internal/keyring"
 )
 
 //go:embed config.toml
@@ -16,14 +19,31 @@ var defaultConfig []byte
 
 // OAuthConfig holds OAuth credentials for browser-based login.
 // ProxyURL is used for public distribution (Cloudflare Worker holds the secret).
-// ClientSecret is used for self-hosted setups (direct exchange with Slack).
+// ClientSecret is used for self-hosted setups (direct exchange with Slack);
+// without it, the direct exchange uses PKCE.
 type OAuthConfig struct {
 	ClientID     string `toml:"client_id"`
 	ClientSecret string `toml:"client_secret"`
 	AppToken     string `toml:"app_token"`
 	ProxyURL     string `toml:"proxy_url"`
+	Port         int    `toml:"port"` // fixed callback port; 0 picks a free one
 }
 
+// Credentials controls where tokens are stored. Backend is one of
+// keyring.Backends; Command is the helper program for the "command" backend.
+type Credentials struct {
+	Backend string `toml:"backend"`
+	Command string `toml:"command"`
+}
+
+// Event transports for the transport setting. RTM additionally carries
+// typing indicators and live presence, but only works with tokens from
+// classic Slack apps.
+const (
+	TransportSocketMode = "socket_mode"
+	TransportRTM        = "rtm"
+)
+
 // Config holds the application configuration.
 type Config struct {
 	Mouse               bool   `toml:"mouse"`
@@ -36,6 +56,10 @@ type Config struct {
 
 	AsciiIcons bool `toml:"ascii_icons"`
 
+	// Transport selects how events are received: TransportSocketMode or
+	// TransportRTM.
+	Transport string `toml:"transport"`
+
 	Markdown        MarkdownConfig  `toml:"markdown"`
 	Timestamps      Timestamps      `toml:"timestamps"`
 	DateSeparator   DateSeparator   `toml:"date_separator"`
@@ -44,9 +68,17 @@ type Config struct {
 	Threads         Threads         `toml:"threads"`
 	Presence        Presence        `toml:"presence"`
 	OAuth           OAuthConfig     `toml:"oauth"`
+	Credentials     Credentials     `toml:"credentials"`
+
+	Aliases  map[string]Alias `toml:"aliases"`
+	Commands []CustomCommand  `toml:"commands"`
+	Hooks    []Hook           `toml:"hooks"`
 
 	Keybinds Keybinds `toml:"keybinds"`
 	Theme    Theme    `toml:"theme"`
+
+	// Path is the file the config was loaded from.
+	Path string `toml:"-"`
 }
 
 // MarkdownConfig controls markdown rendering in messages.
@@ -134,11 +166,13 @@ func Load(path string) (*Config, error) {
 	cfg.Theme = resolveTheme(path, cfg.Theme)
 
 	applyDefaults(&cfg)
+	cfg.Keybinds.expandLeader()
 
 	if err := validate(&cfg); err != nil {
 		return nil, fmt.Errorf("config validation: %w", err)
 	}
 
+	cfg.Path = path
 	return &cfg, nil
 }
 
@@ -190,6 +224,30 @@ func validate(cfg *Config) error {
 	if cfg.AutocompleteLimit < 0 {
 		return fmt.Errorf("autocomplete_limit must be >= 0, got %d", cfg.AutocompleteLimit)
 	}
+	if cfg.Transport != TransportSocketMode && cfg.Transport != TransportRTM {
+		return fmt.Errorf("transport must be %q or %q, got %q", TransportSocketMode, TransportRTM, cfg.Transport)
+	}
+	if cfg.OAuth.Port < 0 || cfg.OAuth.Port > 65535 {
+		return fmt.Errorf("oauth.port must be between 0 and 65535, got %d", cfg.OAuth.Port)
+	}
+	if !slices.Contains(keyring.Backends, cfg.Credentials.Backend) {
+		return fmt.Errorf("credentials.backend must be one of %s, got %q", strings.Join(keyring.Backends, ", "), cfg.Credentials.Backend)
+	}
+	if cfg.Credentials.Backend == keyring.BackendCommand && cfg.Credentials.Command == "" {
+		return fmt.Errorf("credentials.command is required when credentials.backend is %q", keyring.BackendCommand)
+	}
+	if cfg.Keybinds.Timeout < 0 {
+		return fmt.Errorf("keybinds.timeout must be >= 0, got %d", cfg.Keybinds.Timeout)
+	}
+	if err := validateAliases(cfg.Aliases); err != nil {
+		return err
+	}
+	if err := validateCommands(cfg.Commands, cfg.Aliases); err != nil {
+		return err
+	}
+	if err := validateHooks(cfg.Hooks); err != nil {
+		return err
+	}
 	return nil
 }
 
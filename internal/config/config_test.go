@@ -42,7 +42,7 @@ func TestLoadMissingFileWritesDefaults(t *testing.T) {
 	if !cfg.Timestamps.Enabled {
 		t.Error("expected timestamps.enabled=true from defaults")
 	}
-	if cfg.Keybinds.Quit != "Ctrl+C" {
+	if !cfg.Keybinds.Quit.Matches("Ctrl+C") {
 		t.Errorf("expected keybinds.quit=Ctrl+C, got %s", cfg.Keybinds.Quit)
 	}
 }
@@ -74,7 +74,7 @@ func TestLoadPartialOverridePreservesDefaults(t *testing.T) {
 	if cfg.Timestamps.Format != "3:04PM" {
 		t.Errorf("expected timestamps.format=3:04PM, got %s", cfg.Timestamps.Format)
 	}
-	if cfg.Keybinds.ChannelsTree.Up != "Rune[k]" {
+	if !cfg.Keybinds.ChannelsTree.Up.Matches("Rune[k]") {
 		t.Errorf("expected keybinds.channels_tree.up=Rune[k], got %s", cfg.Keybinds.ChannelsTree.Up)
 	}
 }
@@ -87,6 +87,10 @@ func TestValidationRejectsOutOfRange(t *testing.T) {
 		{"messages_limit too low", "messages_limit = 0\n"},
 		{"messages_limit too high", "messages_limit = 200\n"},
 		{"autocomplete_limit negative", "autocomplete_limit = -1\n"},
+		{"unknown transport", "transport = \"irc\"\n"},
+		{"oauth port out of range", "[oauth]\nport = 70000\n"},
+		{"unknown credential backend", "[credentials]\nbackend = \"vault\"\n"},
+		{"command backend without command", "[credentials]\nbackend = \"command\"\n"},
 	}
 
 	for _, tt := range tests {
@@ -232,3 +236,77 @@ attributes = "bold"
 			cfg.Theme.Markdown.UserMention.Tag(), monokai.Markdown.UserMention.Tag())
 	}
 }
+
+func TestKeybindStringOrArray(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	content := []byte(`[keybinds]
+leader = "Rune[ ]"
+mark_read = ["Rune[m]", "Leader  Rune[r]"]
+quit = ""
+
+[keybinds.channels_tree]
+top = "Rune[g] Rune[g]"
+`)
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := []string(cfg.Keybinds.MarkRead); len(got) != 2 || got[0] != "Rune[m]" || got[1] != "Rune[ ] Rune[r]" {
+		t.Errorf("mark_read = %q, want [Rune[m] \"Rune[ ] Rune[r]\"]", got)
+	}
+	if len(cfg.Keybinds.Quit) != 0 {
+		t.Errorf("quit = %q, want unbound", cfg.Keybinds.Quit)
+	}
+	if !cfg.Keybinds.ChannelsTree.Top.Matches("Rune[g] Rune[g]") {
+		t.Errorf("top = %q, want the g g sequence", cfg.Keybinds.ChannelsTree.Top)
+	}
+	if !cfg.Keybinds.FocusNext.Matches("Ctrl+L") {
+		t.Errorf("focus_next default lost: %q", cfg.Keybinds.FocusNext)
+	}
+}
+
+func TestKeybindEscSpelling(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	content := []byte(`[keybinds]
+leader = "Esc"
+mark_read = "Leader Rune[r]"
+
+[keybinds.channels_picker]
+close = ["Esc", "Alt+Esc"]
+`)
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if close := cfg.Keybinds.ChannelsPicker.Close; !close.Matches("Escape") || !close.Matches("Alt+Escape") {
+		t.Errorf("close = %q, want the Escape events", close)
+	}
+	if !cfg.Keybinds.MarkRead.Matches("Escape Rune[r]") {
+		t.Errorf("mark_read = %q, want the leader normalized", cfg.Keybinds.MarkRead)
+	}
+	if problems := checkKeybinds(cfg.Keybinds); len(problems) != 0 {
+		t.Errorf("checkKeybinds = %v, want no problems", problems)
+	}
+}
+
+func TestKeybindRejectsOtherTypes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(path, []byte("[keybinds]\nquit = 3\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected an error for a numeric keybind")
+	}
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Override is a value in the user's config file that differs from the
+// embedded defaults.
+type Override struct {
+	Key        string // dotted TOML key
+	Value      any
+	Default    any
+	HasDefault bool // false when the key does not exist in the defaults
+}
+
+// Diff returns the values in the config file at path that differ from the
+// embedded defaults, in file order. Values equal to the default are omitted.
+func Diff(path string) ([]Override, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var defaults map[string]any
+	if _, err := toml.Decode(string(defaultConfig), &defaults); err != nil {
+		return nil, fmt.Errorf("parsing embedded config: %w", err)
+	}
+	var user map[string]any
+	md, err := toml.Decode(string(data), &user)
+	if err != nil {
+		return nil, fmt.Errorf("parsing config file: %w", err)
+	}
+
+	var overrides []Override
+	for _, key := range md.Keys() {
+		value, ok := lookup(user, key)
+		if !ok {
+			continue
+		}
+		if _, isTable := value.(map[string]any); isTable {
+			continue
+		}
+		def, hasDefault := lookup(defaults, key)
+		if hasDefault && reflect.DeepEqual(value, def) {
+			continue
+		}
+		overrides = append(overrides, Override{
+			Key:        key.String(),
+			Value:      value,
+			Default:    def,
+			HasDefault: hasDefault,
+		})
+	}
+	return overrides, nil
+}
+
+// lookup returns the value at key in a decoded TOML document.
+func lookup(doc map[string]any, key toml.Key) (any, bool) {
+	var cur any = doc
+	for _, part := range key {
+		table, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = table[part]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// FormatValue renders a decoded TOML value as TOML source.
+func FormatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = FormatValue(item)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case []map[string]any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = FormatValue(item)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case map[string]any:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fields := make([]string, len(names))
+		for i, name := range names {
+			fields[i] = name + " = " + FormatValue(v[name])
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return fmt.Sprint(v)
+	}
+}
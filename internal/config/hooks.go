@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Hook events, one per slack.EventHandler callback.
+const (
+	HookMessage             = "message"
+	HookMessageChanged      = "message_changed"
+	HookMessageDeleted      = "message_deleted"
+	HookReactionAdded       = "reaction_added"
+	HookReactionRemoved     = "reaction_removed"
+	HookChannelCreated      = "channel_created"
+	HookChannelArchive      = "channel_archive"
+	HookChannelUnarchive    = "channel_unarchive"
+	HookChannelRename       = "channel_rename"
+	HookMemberJoinedChannel = "member_joined_channel"
+	HookMemberLeftChannel   = "member_left_channel"
+	HookTeamJoin            = "team_join"
+	HookPinAdded            = "pin_added"
+	HookPinRemoved          = "pin_removed"
+	HookFileShared          = "file_shared"
+	HookUserStatusChanged   = "user_status_changed"
+	HookTyping              = "typing"
+	HookPresenceChange      = "presence_change"
+	HookConnected           = "connected"
+	HookDisconnected        = "disconnected"
+	HookError               = "error"
+)
+
+// HookEvents lists the events a hook can be attached to.
+var HookEvents = []string{
+	HookMessage, HookMessageChanged, HookMessageDeleted,
+	HookReactionAdded, HookReactionRemoved,
+	HookChannelCreated, HookChannelArchive, HookChannelUnarchive, HookChannelRename,
+	HookMemberJoinedChannel, HookMemberLeftChannel, HookTeamJoin,
+	HookPinAdded, HookPinRemoved, HookFileShared, HookUserStatusChanged,
+	HookTyping, HookPresenceChange, HookConnected, HookDisconnected, HookError,
+}
+
+// Hook runs a local executable whenever a Slack event arrives, defined in a
+// [[hooks]] table.
+type Hook struct {
+	Event string   `toml:"event"`
+	Exec  string   `toml:"exec"`
+	Args  []string `toml:"args"`
+	// Timeout is how long, in milliseconds, the hook may run; 0 means the
+	// default of 10 seconds.
+	Timeout int `toml:"timeout"`
+}
+
+// TimeoutDuration returns the hook's timeout, applying the default.
+func (h Hook) TimeoutDuration() time.Duration {
+	if h.Timeout == 0 {
+		return defaultCommandTimeout
+	}
+	return time.Duration(h.Timeout) * time.Millisecond
+}
+
+// validateHooks checks hook events, executables and timeouts.
+func validateHooks(hooks []Hook) error {
+	for i, h := range hooks {
+		if !slices.Contains(HookEvents, h.Event) {
+			return fmt.Errorf("hooks[%d]: unknown event %q", i, h.Event)
+		}
+		if h.Exec == "" {
+			return fmt.Errorf("hooks[%d] (%s): exec is required", i, h.Event)
+		}
+		if h.Timeout < 0 {
+			return fmt.Errorf("hooks[%d] (%s): timeout must be >= 0, got %d", i, h.Event, h.Timeout)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadHooks(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `[[hooks]]
+event = "message"
+exec = "log-dms"
+
+[[hooks]]
+event = "reaction_added"
+exec = "on-react"
+timeout = 250
+`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Hooks) != 2 || cfg.Hooks[1].Exec != "on-react" {
+		t.Fatalf("Hooks = %+v", cfg.Hooks)
+	}
+	if cfg.Hooks[0].TimeoutDuration() != defaultCommandTimeout || cfg.Hooks[1].TimeoutDuration() != 250*time.Millisecond {
+		t.Errorf("timeouts = %v, %v", cfg.Hooks[0].TimeoutDuration(), cfg.Hooks[1].TimeoutDuration())
+	}
+
+	for name, content := range map[string]string{
+		"unknown event": "[[hooks]]\nevent = \"sneeze\"\nexec = \"x\"\n",
+		"missing exec":  "[[hooks]]\nevent = \"message\"\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, content)); err == nil {
+				t.Error("Load should fail")
+			}
+		})
+	}
+}
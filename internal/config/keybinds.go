@@ -1,26 +1,42 @@
 package config
 
-// Keybinds holds all keybinding configuration. Values are plain strings
-// matching the tcell.EventKey.Name() format (e.g. "Rune[j]", "Ctrl+W", "Enter").
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/m96-chan/Slacko/internal/ui/keys"
+)
+
+// Keybinds holds all keybinding configuration. Key names match the
+// tcell.EventKey.Name() format (e.g. "Rune[j]", "Ctrl+W", "Enter"); see
+// Keybind for sequences and multiple bindings.
 type Keybinds struct {
-	FocusChannels  string `toml:"focus_channels"`
-	FocusMessages  string `toml:"focus_messages"`
-	FocusInput     string `toml:"focus_input"`
-	FocusPrevious  string `toml:"focus_previous"`
-	FocusNext      string `toml:"focus_next"`
-	ToggleThread   string `toml:"toggle_thread"`
-	ToggleChannels string `toml:"toggle_channels"`
-	ChannelPicker  string `toml:"channel_picker"`
-	Search         string `toml:"search"`
-	Quit           string `toml:"quit"`
-	Help           string `toml:"help"`
-	SwitchTeam     string `toml:"switch_team"`
-	CommandMode    string `toml:"command_mode"`
-	MarkRead       string `toml:"mark_read"`
-	MarkAllRead    string `toml:"mark_all_read"`
-	PinnedMessages string `toml:"pinned_messages"`
-	StarredItems   string `toml:"starred_items"`
-	ChannelInfo    string `toml:"channel_info"`
+	// Leader is the key substituted for the "Leader" token in sequences.
+	Leader string `toml:"leader"`
+	// Timeout is how long, in milliseconds, a partially typed sequence or
+	// count waits for the next key.
+	Timeout int `toml:"timeout"`
+
+	FocusChannels  Keybind `toml:"focus_channels"`
+	FocusMessages  Keybind `toml:"focus_messages"`
+	FocusInput     Keybind `toml:"focus_input"`
+	FocusPrevious  Keybind `toml:"focus_previous"`
+	FocusNext      Keybind `toml:"focus_next"`
+	ToggleThread   Keybind `toml:"toggle_thread"`
+	ToggleChannels Keybind `toml:"toggle_channels"`
+	ChannelPicker  Keybind `toml:"channel_picker"`
+	Search         Keybind `toml:"search"`
+	Quit           Keybind `toml:"quit"`
+	Help           Keybind `toml:"help"`
+	SwitchTeam     Keybind `toml:"switch_team"`
+	CommandMode    Keybind `toml:"command_mode"`
+	MarkRead       Keybind `toml:"mark_read"`
+	MarkAllRead    Keybind `toml:"mark_all_read"`
+	PinnedMessages Keybind `toml:"pinned_messages"`
+	StarredItems   Keybind `toml:"starred_items"`
+	ChannelInfo    Keybind `toml:"channel_info"`
+	Activity       Keybind `toml:"activity"`
 
 	ChannelsTree     ChannelsTreeKeybinds    `toml:"channels_tree"`
 	MessagesList     MessagesListKeybinds    `toml:"messages_list"`
@@ -37,148 +53,272 @@ type Keybinds struct {
 	MembersPicker    MembersPickerKeybinds   `toml:"members_picker"`
 	InvitePicker     InvitePickerKeybinds    `toml:"invite_picker"`
 	GroupDMPicker    GroupDMPickerKeybinds   `toml:"group_dm_picker"`
+	ActivityPanel    ActivityPanelKeybinds   `toml:"activity_panel"`
+	ThemePicker      ThemePickerKeybinds     `toml:"theme_picker"`
 }
 
 // ChannelsTreeKeybinds holds keybindings for the channels tree panel.
 type ChannelsTreeKeybinds struct {
-	Up            string `toml:"up"`
-	Down          string `toml:"down"`
-	Top           string `toml:"top"`
-	Bottom        string `toml:"bottom"`
-	SelectCurrent string `toml:"select_current"`
-	Collapse      string `toml:"collapse"`
-	MoveToParent  string `toml:"move_to_parent"`
-	CopyChannelID string `toml:"copy_channel_id"`
+	Up            Keybind `toml:"up"`
+	Down          Keybind `toml:"down"`
+	Top           Keybind `toml:"top"`
+	Bottom        Keybind `toml:"bottom"`
+	SelectCurrent Keybind `toml:"select_current"`
+	Collapse      Keybind `toml:"collapse"`
+	MoveToParent  Keybind `toml:"move_to_parent"`
+	CopyChannelID Keybind `toml:"copy_channel_id"`
 }
 
 // MessagesListKeybinds holds keybindings for the messages list panel.
 type MessagesListKeybinds struct {
-	Up             string `toml:"up"`
-	Down           string `toml:"down"`
-	SelectCurrent  string `toml:"select_current"`
-	ScrollUp       string `toml:"scroll_up"`
-	ScrollDown     string `toml:"scroll_down"`
-	Reply          string `toml:"reply"`
-	Edit           string `toml:"edit"`
-	Delete         string `toml:"delete"`
-	Reactions      string `toml:"reactions"`
-	RemoveReaction string `toml:"remove_reaction"`
-	Thread         string `toml:"thread"`
-	Yank           string `toml:"yank"`
-	CopyPermalink  string `toml:"copy_permalink"`
-	OpenFile       string `toml:"open_file"`
-	Pin            string `toml:"pin"`
-	Star           string `toml:"star"`
-	UserProfile    string `toml:"user_profile"`
-	ViewReactions  string `toml:"view_reactions"`
-	Cancel         string `toml:"cancel"`
+	Up             Keybind `toml:"up"`
+	Down           Keybind `toml:"down"`
+	SelectCurrent  Keybind `toml:"select_current"`
+	ScrollUp       Keybind `toml:"scroll_up"`
+	ScrollDown     Keybind `toml:"scroll_down"`
+	Reply          Keybind `toml:"reply"`
+	Edit           Keybind `toml:"edit"`
+	Delete         Keybind `toml:"delete"`
+	Reactions      Keybind `toml:"reactions"`
+	RemoveReaction Keybind `toml:"remove_reaction"`
+	Thread         Keybind `toml:"thread"`
+	Yank           Keybind `toml:"yank"`
+	CopyPermalink  Keybind `toml:"copy_permalink"`
+	OpenFile       Keybind `toml:"open_file"`
+	Pin            Keybind `toml:"pin"`
+	Star           Keybind `toml:"star"`
+	UserProfile    Keybind `toml:"user_profile"`
+	ViewReactions  Keybind `toml:"view_reactions"`
+	Cancel         Keybind `toml:"cancel"`
 }
 
 // MessageInputKeybinds holds keybindings for the message input area.
 type MessageInputKeybinds struct {
-	Send           string `toml:"send"`
-	Newline        string `toml:"newline"`
-	TabComplete    string `toml:"tab_complete"`
-	OpenEditor     string `toml:"open_editor"`
-	OpenFilePicker string `toml:"open_file_picker"`
-	Paste          string `toml:"paste"`
-	Cancel         string `toml:"cancel"`
+	Send           Keybind `toml:"send"`
+	Newline        Keybind `toml:"newline"`
+	TabComplete    Keybind `toml:"tab_complete"`
+	OpenEditor     Keybind `toml:"open_editor"`
+	OpenFilePicker Keybind `toml:"open_file_picker"`
+	Paste          Keybind `toml:"paste"`
+	Cancel         Keybind `toml:"cancel"`
 }
 
 // ThreadViewKeybinds holds keybindings for the thread view panel.
 type ThreadViewKeybinds struct {
-	Up    string `toml:"up"`
-	Down  string `toml:"down"`
-	Reply string `toml:"reply"`
-	Close string `toml:"close"`
+	Up    Keybind `toml:"up"`
+	Down  Keybind `toml:"down"`
+	Reply Keybind `toml:"reply"`
+	Close Keybind `toml:"close"`
 }
 
 // ChannelsPickerKeybinds holds keybindings for the channel picker popup.
 type ChannelsPickerKeybinds struct {
-	Close  string `toml:"close"`
-	Up     string `toml:"up"`
-	Down   string `toml:"down"`
-	Select string `toml:"select"`
+	Close  Keybind `toml:"close"`
+	Up     Keybind `toml:"up"`
+	Down   Keybind `toml:"down"`
+	Select Keybind `toml:"select"`
 }
 
 // FilePickerKeybinds holds keybindings for the file picker popup.
 type FilePickerKeybinds struct {
-	Close  string `toml:"close"`
-	Up     string `toml:"up"`
-	Down   string `toml:"down"`
-	Select string `toml:"select"`
+	Close  Keybind `toml:"close"`
+	Up     Keybind `toml:"up"`
+	Down   Keybind `toml:"down"`
+	Select Keybind `toml:"select"`
 }
 
 // SearchPickerKeybinds holds keybindings for the search picker popup.
 type SearchPickerKeybinds struct {
-	Close  string `toml:"close"`
-	Up     string `toml:"up"`
-	Down   string `toml:"down"`
-	Select string `toml:"select"`
+	Close       Keybind `toml:"close"`
+	Up          Keybind `toml:"up"`
+	Down        Keybind `toml:"down"`
+	Select      Keybind `toml:"select"`
+	ToggleFiles Keybind `toml:"toggle_files"`
+	ToggleSort  Keybind `toml:"toggle_sort"`
+	Complete    Keybind `toml:"complete"`
 }
 
 // PinsPickerKeybinds holds keybindings for the pinned messages picker popup.
 type PinsPickerKeybinds struct {
-	Close  string `toml:"close"`
-	Up     string `toml:"up"`
-	Down   string `toml:"down"`
-	Select string `toml:"select"`
+	Close  Keybind `toml:"close"`
+	Up     Keybind `toml:"up"`
+	Down   Keybind `toml:"down"`
+	Select Keybind `toml:"select"`
 }
 
 // BookmarksPickerKeybinds holds keybindings for the channel bookmarks picker popup.
 type BookmarksPickerKeybinds struct {
-	Close  string `toml:"close"`
-	Up     string `toml:"up"`
-	Down   string `toml:"down"`
-	Select string `toml:"select"`
+	Close  Keybind `toml:"close"`
+	Up     Keybind `toml:"up"`
+	Down   Keybind `toml:"down"`
+	Select Keybind `toml:"select"`
 }
 
 // StarredPickerKeybinds holds keybindings for the starred items picker popup.
 type StarredPickerKeybinds struct {
-	Close  string `toml:"close"`
-	Up     string `toml:"up"`
-	Down   string `toml:"down"`
-	Select string `toml:"select"`
-	Unstar string `toml:"unstar"`
+	Close  Keybind `toml:"close"`
+	Up     Keybind `toml:"up"`
+	Down   Keybind `toml:"down"`
+	Select Keybind `toml:"select"`
+	Unstar Keybind `toml:"unstar"`
 }
 
 // UserProfileKeybinds holds keybindings for the user profile panel.
 type UserProfileKeybinds struct {
-	Close  string `toml:"close"`
-	OpenDM string `toml:"open_dm"`
-	CopyID string `toml:"copy_id"`
+	Close  Keybind `toml:"close"`
+	OpenDM Keybind `toml:"open_dm"`
+	CopyID Keybind `toml:"copy_id"`
 }
 
 // ChannelInfoKeybinds holds keybindings for the channel info panel.
 type ChannelInfoKeybinds struct {
-	Close      string `toml:"close"`
-	SetTopic   string `toml:"set_topic"`
-	SetPurpose string `toml:"set_purpose"`
-	Leave      string `toml:"leave"`
+	Close      Keybind `toml:"close"`
+	SetTopic   Keybind `toml:"set_topic"`
+	SetPurpose Keybind `toml:"set_purpose"`
+	Leave      Keybind `toml:"leave"`
 }
 
 // MembersPickerKeybinds holds keybindings for the channel members picker popup.
 type MembersPickerKeybinds struct {
-	Close  string `toml:"close"`
-	Up     string `toml:"up"`
-	Down   string `toml:"down"`
-	Select string `toml:"select"`
+	Close  Keybind `toml:"close"`
+	Up     Keybind `toml:"up"`
+	Down   Keybind `toml:"down"`
+	Select Keybind `toml:"select"`
 }
 
 // InvitePickerKeybinds holds keybindings for the invite user picker popup.
 type InvitePickerKeybinds struct {
-	Close  string `toml:"close"`
-	Up     string `toml:"up"`
-	Down   string `toml:"down"`
-	Select string `toml:"select"`
+	Close  Keybind `toml:"close"`
+	Up     Keybind `toml:"up"`
+	Down   Keybind `toml:"down"`
+	Select Keybind `toml:"select"`
 }
 
 // GroupDMPickerKeybinds holds keybindings for the group DM creation picker popup.
 type GroupDMPickerKeybinds struct {
-	Close   string `toml:"close"`
-	Up      string `toml:"up"`
-	Down    string `toml:"down"`
-	Add     string `toml:"add"`
-	Remove  string `toml:"remove"`
-	Confirm string `toml:"confirm"`
+	Close   Keybind `toml:"close"`
+	Up      Keybind `toml:"up"`
+	Down    Keybind `toml:"down"`
+	Add     Keybind `toml:"add"`
+	Remove  Keybind `toml:"remove"`
+	Confirm Keybind `toml:"confirm"`
+}
+
+// ActivityPanelKeybinds holds keybindings for the activity inbox popup.
+type ActivityPanelKeybinds struct {
+	Close       Keybind `toml:"close"`
+	Up          Keybind `toml:"up"`
+	Down        Keybind `toml:"down"`
+	Select      Keybind `toml:"select"`
+	ToggleRead  Keybind `toml:"toggle_read"`
+	MarkAllRead Keybind `toml:"mark_all_read"`
+}
+
+// ThemePickerKeybinds holds keybindings for the theme picker popup.
+type ThemePickerKeybinds struct {
+	Close  Keybind `toml:"close"`
+	Up     Keybind `toml:"up"`
+	Down   Keybind `toml:"down"`
+	Select Keybind `toml:"select"`
+	Save   Keybind `toml:"save"`
+}
+
+// Keybind is the list of key sequences bound to an action. In TOML it is
+// either a single string or an array of strings, so existing single-key
+// configs keep working. Each string is one sequence of space-separated key
+// names, e.g. "Rune[g] Rune[g]" or "Leader Rune[r]"; the token "Leader" stands for
+// Keybinds.Leader. An empty string or array leaves the action unbound. "Esc"
+// is stored as "Escape", the name events are matched by.
+type Keybind []string
+
+// UnmarshalTOML implements the toml.Unmarshaler interface.
+func (k *Keybind) UnmarshalTOML(data any) error {
+	var seqs []string
+	switch v := data.(type) {
+	case string:
+		seqs = []string{v}
+	case []any:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("expected key name string, got %T", item)
+			}
+			seqs = append(seqs, s)
+		}
+	default:
+		return fmt.Errorf("expected string or array of strings for keybind, got %T", data)
+	}
+
+	*k = nil
+	for _, seq := range seqs {
+		names := keys.Split(seq)
+		for i, name := range names {
+			names[i] = keys.Canonical(name)
+		}
+		if seq = strings.Join(names, " "); seq != "" {
+			*k = append(*k, seq)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether name, a key name or a completed sequence of key
+// names separated by spaces, is bound to this action.
+func (k Keybind) Matches(name string) bool {
+	for _, seq := range k {
+		if seq == name {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the bindings in config form, separated by " / ".
+func (k Keybind) String() string {
+	return strings.Join(k, " / ")
+}
+
+// Sequences returns every key sequence bound in a keybinds table: Keybinds
+// itself (global keys only) or one of its per-panel structs.
+func Sequences(table any) []string {
+	var seqs []string
+	v := reflect.ValueOf(table)
+	for i := range v.NumField() {
+		if kb, ok := v.Field(i).Interface().(Keybind); ok {
+			seqs = append(seqs, kb...)
+		}
+	}
+	return seqs
+}
+
+// expandLeader replaces the "Leader" token in every sequence with the
+// configured leader key.
+func (kb *Keybinds) expandLeader() {
+	if kb.Leader == "" {
+		return
+	}
+	kb.Leader = keys.Canonical(kb.Leader)
+	var expand func(v reflect.Value)
+	expand = func(v reflect.Value) {
+		for i := range v.NumField() {
+			field := v.Field(i)
+			switch bind := field.Interface().(type) {
+			case Keybind:
+				for j, seq := range bind {
+					parts := keys.Split(seq)
+					for p, part := range parts {
+						if part == "Leader" {
+							parts[p] = kb.Leader
+						}
+					}
+					bind[j] = strings.Join(parts, " ")
+				}
+			default:
+				if field.Kind() == reflect.Struct {
+					expand(field)
+				}
+			}
+		}
+	}
+	expand(reflect.ValueOf(kb).Elem())
 }
@@ -1,5 +1,31 @@
 package config
 
+import "slices"
+
+// ThemePresets lists the names of the built-in theme presets.
+var ThemePresets = []string{
+	"default",
+	"dark",
+	"light",
+	"monokai",
+	"solarized_dark",
+	"solarized_light",
+	"high_contrast",
+	"monochrome",
+}
+
+// IsThemePreset reports whether name is a built-in theme preset.
+func IsThemePreset(name string) bool {
+	return slices.Contains(ThemePresets, name)
+}
+
+// LoadTheme returns the theme that loading the config file at path with
+// theme.preset set to name would produce: the preset with the file's own
+// [theme] overrides applied on top.
+func LoadTheme(path, name string) Theme {
+	return resolveTheme(path, Theme{Preset: name})
+}
+
 // BuiltinTheme returns a fully populated Theme for the given preset name.
 // Unknown names fall back to "default".
 func BuiltinTheme(name string) Theme {
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// SetValue sets the dotted key (e.g. "theme.preset" or "mouse") to value in
+// the config file at path. Only the assignment line changes; comments,
+// ordering and every other line are kept. The key is added to its table,
+// and the table to the end of the file, when missing. value must be a
+// string, bool, integer or float.
+func SetValue(path, key string, value any) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	table, name := "", key
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		table, name = key[:i], key[i+1:]
+	}
+	line := name + " = " + FormatValue(value)
+
+	text := string(data)
+	hadNewline := text == "" || strings.HasSuffix(text, "\n")
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if text == "" {
+		lines = nil
+	}
+	lines = setLine(lines, table, name, line)
+	out := strings.Join(lines, "\n")
+	if hadNewline {
+		out += "\n"
+	}
+
+	// Refuse to write a file that no longer parses or where the key ended up
+	// somewhere else, e.g. because the table is defined with dotted keys.
+	var doc map[string]any
+	if _, err := toml.Decode(out, &doc); err != nil {
+		return fmt.Errorf("cannot set %s in %s: %w", key, path, err)
+	}
+	got, ok := lookup(doc, toml.Key(strings.Split(key, ".")))
+	if !ok || FormatValue(got) != FormatValue(value) {
+		return fmt.Errorf("cannot set %s in %s automatically; edit the file by hand", key, path)
+	}
+
+	return writeFile(path, []byte(out))
+}
+
+// writeFile replaces the file at path with data through a temporary file in
+// the same directory, so readers (like the reload poller) never see a
+// partial file and a crash leaves the old one in place. The mode of an
+// existing file is kept, and a symlinked config is written through the link.
+func writeFile(path string, data []byte) error {
+	mode := os.FileMode(0o600)
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(mode)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+	}
+	return err
+}
+
+// setLine replaces the assignment of name in table with line, or inserts it.
+func setLine(lines []string, table, name, line string) []string {
+	current := ""
+	found := table == ""
+	insertAt := -1 // after the last assignment in the table, or its header
+	for i, l := range lines {
+		trimmed := strings.TrimSpace(l)
+		if header, ok := tableHeader(trimmed); ok {
+			if found && insertAt < 0 && table == "" {
+				insertAt = i
+			}
+			current = header
+			if current == table {
+				found = true
+				insertAt = i + 1
+			}
+			continue
+		}
+		if current != table {
+			continue
+		}
+		if k, ok := assignmentKey(trimmed); ok {
+			if k == name {
+				indent := l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+				lines[i] = indent + line + trailingComment(l)
+				return lines
+			}
+			insertAt = i + 1
+		}
+	}
+
+	if !found {
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		return append(lines, "["+table+"]", line)
+	}
+	if insertAt < 0 {
+		insertAt = len(lines)
+	}
+	if table == "" {
+		// Keep a blank line between the new key and the first table.
+		for insertAt > 0 && strings.TrimSpace(lines[insertAt-1]) == "" {
+			insertAt--
+		}
+	}
+	return append(lines[:insertAt], append([]string{line}, lines[insertAt:]...)...)
+}
+
+// tableHeader returns the table name of a "[table]" line. Array-of-tables
+// headers ("[[table]]") return a name that no plain table matches.
+func tableHeader(line string) (string, bool) {
+	if !strings.HasPrefix(line, "[") {
+		return "", false
+	}
+	if i := strings.Index(line, "#"); i >= 0 {
+		line = strings.TrimSpace(line[:i])
+	}
+	if strings.HasPrefix(line, "[[") {
+		return line, true
+	}
+	name, ok := strings.CutSuffix(strings.TrimPrefix(line, "["), "]")
+	if !ok {
+		return "", false
+	}
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return strings.Join(parts, "."), true
+}
+
+// assignmentKey returns the key of a "key = value" line.
+func assignmentKey(line string) (string, bool) {
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", false
+	}
+	k, _, ok := strings.Cut(line, "=")
+	if !ok {
+		return "", false
+	}
+	return strings.Trim(strings.TrimSpace(k), `"'`), true
+}
+
+// trailingComment returns the "# ..." comment at the end of an assignment
+// line, with the whitespace before it, or "" when there is none.
+func trailingComment(line string) string {
+	_, value, _ := strings.Cut(line, "=")
+	start := len(line) - len(value)
+	var quote byte
+	for i := start; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			j := i
+			for j > start && (line[j-1] == ' ' || line[j-1] == '\t') {
+				j--
+			}
+			return line[j:]
+		}
+	}
+	return ""
+}
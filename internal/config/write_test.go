@@ -0,0 +1,159 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestSetValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		key   string
+		value any
+		want  string
+	}{
+		{
+			name:  "replace keeps comments",
+			input: "# my config\n[theme]\n# pick one\npreset = \"dark\"\n\n[theme.border.focused]\nforeground = \"red\"\n",
+			key:   "theme.preset",
+			value: "light",
+			want:  "# my config\n[theme]\n# pick one\npreset = \"light\"\n\n[theme.border.focused]\nforeground = \"red\"\n",
+		},
+		{
+			name:  "replace keeps trailing comment",
+			input: "[theme]\npreset = \"da#rk\"   # mine\n",
+			key:   "theme.preset",
+			value: "light",
+			want:  "[theme]\npreset = \"light\"   # mine\n",
+		},
+		{
+			name:  "insert into existing table",
+			input: "[theme]\n\n[theme.border.focused]\nforeground = \"red\"\n",
+			key:   "theme.preset",
+			value: "monokai",
+			want:  "[theme]\npreset = \"monokai\"\n\n[theme.border.focused]\nforeground = \"red\"\n",
+		},
+		{
+			name:  "append missing table",
+			input: "mouse = true\n",
+			key:   "theme.preset",
+			value: "dark",
+			want:  "mouse = true\n\n[theme]\npreset = \"dark\"\n",
+		},
+		{
+			name:  "top-level key before first table",
+			input: "# comment\nmouse = true\n\n[theme]\npreset = \"dark\"\n",
+			key:   "messages_limit",
+			value: 75,
+			want:  "# comment\nmouse = true\nmessages_limit = 75\n\n[theme]\npreset = \"dark\"\n",
+		},
+		{
+			name:  "replace top-level key keeps indent",
+			input: "  mouse = true\n[theme]\nmouse = 1\n",
+			key:   "mouse",
+			value: false,
+			want:  "  mouse = false\n[theme]\nmouse = 1\n",
+		},
+		{
+			name:  "empty file",
+			input: "",
+			key:   "theme.preset",
+			value: "light",
+			want:  "[theme]\npreset = \"light\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.input)
+			if err := SetValue(path, tt.key, tt.value); err != nil {
+				t.Fatalf("SetValue: %v", err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetValueMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "config.toml")
+	if err := SetValue(path, "theme.preset", "dark"); err != nil {
+		t.Fatalf("SetValue: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "[theme]\npreset = \"dark\"\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestSetValueReplacesFile(t *testing.T) {
+	path := writeConfig(t, "mouse = true\n")
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.Symlink(path, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetValue(link, "mouse", false); err != nil {
+		t.Fatalf("SetValue: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "mouse = false\n" {
+		t.Errorf("got %q", got)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o644 {
+		t.Errorf("mode = %v, want the original 0644", info.Mode().Perm())
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("symlink replaced by a file")
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Errorf("temporary file %s left behind", e.Name())
+		}
+	}
+}
+
+func TestSetValueRefusesUnsafeEdit(t *testing.T) {
+	// A dotted key defines theme.preset outside a [theme] header; adding
+	// a [theme] table would be a duplicate definition.
+	input := "theme.preset = \"dark\"\n"
+	path := writeConfig(t, input)
+	err := SetValue(path, "theme.preset", "light")
+	if err == nil || !strings.Contains(err.Error(), "theme.preset") {
+		t.Fatalf("SetValue error = %v, want an error naming the key", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != input {
+		t.Errorf("file was modified: %q", got)
+	}
+}
+
+func TestLoadThemeAppliesOverrides(t *testing.T) {
+	path := writeConfig(t, "[theme]\npreset = \"dark\"\n\n[theme.messages_list.author]\nforeground = \"red\"\n")
+
+	theme := LoadTheme(path, "light")
+	if theme.Preset != "light" {
+		t.Errorf("Preset = %q, want light", theme.Preset)
+	}
+	light := BuiltinTheme("light")
+	if theme.StatusBar != light.StatusBar {
+		t.Error("status bar should come from the light preset")
+	}
+	if fg := theme.MessagesList.Author.Foreground(); fg != tcell.ColorRed {
+		t.Errorf("author foreground = %v, want the red override", fg)
+	}
+}
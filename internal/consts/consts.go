@@ -1,6 +1,7 @@
 package consts
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 )
@@ -17,3 +18,14 @@ func init() {
 	CacheDir = filepath.Join(dir, Name)
 	_ = os.MkdirAll(CacheDir, 0o700)
 }
+
+// RuntimeDir returns the directory for sockets and other per-session files:
+// $XDG_RUNTIME_DIR/slacko, or a per-user directory in the temp dir when
+// XDG_RUNTIME_DIR is not set. The directory is not created; use MkdirPrivate
+// before putting anything in it.
+func RuntimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, Name)
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("%s-%d", Name, os.Getuid()))
+}
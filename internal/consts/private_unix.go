@@ -0,0 +1,34 @@
+//go:build !windows
+
+package consts
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// MkdirPrivate creates dir, and any missing parents, with mode 0700 and
+// checks that it is a real directory owned by the current user that nobody
+// else can access. An existing directory that fails the check is an error,
+// so another user can't pre-create a predictable path such as
+// /tmp/slacko-<uid> and plant sockets or files in it.
+func MkdirPrivate(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok && int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("%s is owned by another user (uid %d)", dir, st.Uid)
+	}
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		return fmt.Errorf("%s has mode %#o, want 0700", dir, perm)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+//go:build !windows
+
+package consts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMkdirPrivate(t *testing.T) {
+	base := t.TempDir()
+
+	dir := filepath.Join(base, "new", "run")
+	if err := MkdirPrivate(dir); err != nil {
+		t.Fatalf("MkdirPrivate on a new directory: %v", err)
+	}
+	if err := MkdirPrivate(dir); err != nil {
+		t.Errorf("MkdirPrivate on its own directory: %v", err)
+	}
+
+	open := filepath.Join(base, "open")
+	if err := os.Mkdir(open, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(open, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := MkdirPrivate(open); err == nil {
+		t.Error("MkdirPrivate accepted a directory others can read")
+	}
+
+	link := filepath.Join(base, "link")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Fatal(err)
+	}
+	if err := MkdirPrivate(link); err == nil {
+		t.Error("MkdirPrivate accepted a symlink")
+	}
+}
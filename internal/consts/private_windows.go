@@ -0,0 +1,11 @@
+//go:build windows
+
+package consts
+
+import "os"
+
+// MkdirPrivate creates dir, and any missing parents. The temp and cache
+// directories it is used under are per-user on Windows.
+func MkdirPrivate(dir string) error {
+	return os.MkdirAll(dir, 0o700)
+}
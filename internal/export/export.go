@@ -0,0 +1,332 @@
+// Package export writes conversation history to JSON (Slack export layout),
+// Markdown or self-contained HTML.
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// Format is an export output format.
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat parses a format name ("json", "markdown"/"md", "html").
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "json":
+		return FormatJSON, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "html", "htm":
+		return FormatHTML, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (want json, markdown or html)", s)
+	}
+}
+
+// Extension returns the file extension used for the format; JSON exports
+// are directories and have none.
+func (f Format) Extension() string {
+	switch f {
+	case FormatMarkdown:
+		return ".md"
+	case FormatHTML:
+		return ".html"
+	default:
+		return ""
+	}
+}
+
+// Source is the part of the Slack client used to read history.
+type Source interface {
+	GetConversationHistory(params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error)
+	GetConversationReplies(params *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error)
+}
+
+// Downloader fetches a private file URL with the user's credentials.
+type Downloader interface {
+	GetFile(url string, w io.Writer) error
+}
+
+// Thread is a top-level message with its replies, oldest first.
+type Thread struct {
+	Message slack.Message
+	Replies []slack.Message
+}
+
+// Conversation is the collected history of a channel, DM or single thread.
+type Conversation struct {
+	ChannelID string
+	Name      string // channel name or DM partner, without prefix
+	ThreadTS  string // set when only one thread was exported
+	Threads   []Thread
+}
+
+// Names holds the lookups used to resolve mentions, shared with
+// markdown.Render.
+type Names struct {
+	Users    map[string]slack.User
+	Channels map[string]string // channel ID → name
+}
+
+// Range bounds an export by Slack timestamps. Empty fields are unbounded.
+type Range struct {
+	Oldest string
+	Latest string
+}
+
+// ParseRange builds a Range from "since" and "until" dates. Dates are
+// YYYY-MM-DD (local time; "until" includes the whole day) or RFC 3339.
+func ParseRange(since, until string) (Range, error) {
+	var r Range
+	if since != "" {
+		t, err := parseDate(since, false)
+		if err != nil {
+			return Range{}, fmt.Errorf("since: %w", err)
+		}
+		r.Oldest = toSlackTS(t)
+	}
+	if until != "" {
+		t, err := parseDate(until, true)
+		if err != nil {
+			return Range{}, fmt.Errorf("until: %w", err)
+		}
+		r.Latest = toSlackTS(t)
+	}
+	if r.Oldest != "" && r.Latest != "" && r.Oldest > r.Latest {
+		return Range{}, fmt.Errorf("since %s is after until %s", since, until)
+	}
+	return r, nil
+}
+
+// parseDate parses YYYY-MM-DD or RFC 3339. With endOfDay, a bare date is
+// moved to the start of the following day.
+func parseDate(s string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (want YYYY-MM-DD or RFC 3339)", s)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// toSlackTS formats t as a Slack timestamp.
+func toSlackTS(t time.Time) string {
+	return fmt.Sprintf("%d.%06d", t.Unix(), t.Nanosecond()/1000)
+}
+
+// parseTS converts a Slack timestamp to a time.
+func parseTS(ts string) time.Time {
+	sec, frac, _ := strings.Cut(ts, ".")
+	s, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	us, _ := strconv.ParseInt(frac, 10, 64)
+	return time.Unix(s, us*1000)
+}
+
+// historyPageSize is the page size used when paging through history.
+const historyPageSize = 200
+
+// Collect pages through the full history of channelID within r, fetching
+// the replies of every thread. With threadTS set only that thread is
+// collected and r is ignored.
+func Collect(src Source, channelID, threadTS string, r Range) (*Conversation, error) {
+	conv := &Conversation{ChannelID: channelID, ThreadTS: threadTS}
+
+	if threadTS != "" {
+		msgs, err := collectReplies(src, channelID, threadTS)
+		if err != nil {
+			return nil, err
+		}
+		if len(msgs) == 0 {
+			return nil, fmt.Errorf("thread %s not found", threadTS)
+		}
+		conv.Threads = []Thread{{Message: msgs[0], Replies: msgs[1:]}}
+		return conv, nil
+	}
+
+	params := &slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Oldest:    r.Oldest,
+		Latest:    r.Latest,
+		Inclusive: true,
+		Limit:     historyPageSize,
+	}
+	var msgs []slack.Message
+	for {
+		resp, err := src.GetConversationHistory(params)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, resp.Messages...)
+		if !resp.HasMore || resp.ResponseMetaData.NextCursor == "" {
+			break
+		}
+		params.Cursor = resp.ResponseMetaData.NextCursor
+	}
+
+	// History is newest-first.
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].Timestamp < msgs[j].Timestamp })
+
+	for _, m := range msgs {
+		t := Thread{Message: m}
+		if m.ReplyCount > 0 && (m.ThreadTimestamp == "" || m.ThreadTimestamp == m.Timestamp) {
+			replies, err := collectReplies(src, channelID, m.Timestamp)
+			if err != nil {
+				return nil, fmt.Errorf("replies of %s: %w", m.Timestamp, err)
+			}
+			if len(replies) > 0 {
+				t.Replies = replies[1:]
+			}
+		}
+		conv.Threads = append(conv.Threads, t)
+	}
+	return conv, nil
+}
+
+// collectReplies returns a thread's parent followed by all replies.
+func collectReplies(src Source, channelID, threadTS string) ([]slack.Message, error) {
+	params := &slack.GetConversationRepliesParameters{
+		ChannelID: channelID,
+		Timestamp: threadTS,
+		Limit:     historyPageSize,
+	}
+	var all []slack.Message
+	for {
+		msgs, hasMore, cursor, err := src.GetConversationReplies(params)
+		if err != nil {
+			return nil, err
+		}
+		// Every page starts with the parent message; keep it only once.
+		if len(all) > 0 && len(msgs) > 0 && msgs[0].Timestamp == threadTS {
+			msgs = msgs[1:]
+		}
+		all = append(all, msgs...)
+		if !hasMore || cursor == "" {
+			return all, nil
+		}
+		params.Cursor = cursor
+	}
+}
+
+// Write renders conv in the given format to path and returns the path
+// written. JSON exports are written as a directory in Slack's export layout.
+// When dl is non-nil, attached files are downloaded next to the export and
+// linked from Markdown and HTML output.
+func Write(conv *Conversation, format Format, path string, names Names, dl Downloader) (string, error) {
+	var files map[string]string
+	if dl != nil {
+		dir := strings.TrimSuffix(path, format.Extension()) + "_files"
+		if format == FormatJSON {
+			dir = filepath.Join(path, "files")
+		}
+		var err error
+		files, err = downloadFiles(conv, dl, dir, filepath.Dir(path))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if format == FormatJSON {
+		return path, writeSlackJSON(path, conv, names)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	switch format {
+	case FormatMarkdown:
+		err = writeMarkdown(f, conv, names, files)
+	case FormatHTML:
+		err = writeHTML(f, conv, names, files)
+	default:
+		err = fmt.Errorf("unknown export format %q", format)
+	}
+	if err != nil {
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// DefaultPath returns the default output path for exporting name in format
+// into dir, e.g. dir/general-2026-01-02.md.
+func DefaultPath(dir, name string, format Format, now time.Time) string {
+	base := sanitize(name)
+	if base == "" {
+		base = "export"
+	}
+	return filepath.Join(dir, base+"-"+now.Format("2006-01-02")+format.Extension())
+}
+
+// sanitize makes name safe to use as a file name.
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// messages returns all messages of conv (parents and replies) in ts order.
+func (c *Conversation) messages() []slack.Message {
+	var all []slack.Message
+	for _, t := range c.Threads {
+		all = append(all, t.Message)
+		all = append(all, t.Replies...)
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Timestamp < all[j].Timestamp })
+	return all
+}
+
+// authorName returns the display name for a message author.
+func authorName(m slack.Message, users map[string]slack.User) string {
+	if u, ok := users[m.User]; ok {
+		switch {
+		case u.Profile.DisplayName != "":
+			return u.Profile.DisplayName
+		case u.RealName != "":
+			return u.RealName
+		case u.Name != "":
+			return u.Name
+		}
+	}
+	if m.Username != "" {
+		return m.Username
+	}
+	if m.BotProfile != nil && m.BotProfile.Name != "" {
+		return m.BotProfile.Name
+	}
+	if m.User != "" {
+		return m.User
+	}
+	return "unknown"
+}
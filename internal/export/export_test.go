@@ -0,0 +1,220 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// fakeSource serves history in pages of two messages and replies per thread.
+type fakeSource struct {
+	history []slack.Message            // newest first
+	replies map[string][]slack.Message // thread ts → parent + replies
+}
+
+func (f *fakeSource) GetConversationHistory(p *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	start := 0
+	if p.Cursor != "" {
+		start = int(p.Cursor[0] - '0')
+	}
+	end := start + 2
+	resp := &slack.GetConversationHistoryResponse{}
+	if end < len(f.history) {
+		resp.HasMore = true
+		resp.ResponseMetaData.NextCursor = string(rune('0' + end))
+	} else {
+		end = len(f.history)
+	}
+	resp.Messages = f.history[start:end]
+	return resp, nil
+}
+
+func (f *fakeSource) GetConversationReplies(p *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error) {
+	return f.replies[p.Timestamp], false, "", nil
+}
+
+func msg(ts, user, text string) slack.Message {
+	return slack.Message{Msg: slack.Msg{Timestamp: ts, User: user, Text: text}}
+}
+
+func testSource() *fakeSource {
+	parent := msg("1700000002.000000", "U1", "release <@U2>?")
+	parent.ReplyCount = 1
+	parent.ThreadTimestamp = parent.Timestamp
+	reply := msg("1700000003.000000", "U2", "shipped")
+	reply.ThreadTimestamp = parent.Timestamp
+	return &fakeSource{
+		history: []slack.Message{
+			msg("1700000004.000000", "U2", "see <#C2> and <https://example.com|docs> &lt;3"),
+			parent,
+			msg("1700000001.000000", "U1", "hello"),
+		},
+		replies: map[string][]slack.Message{parent.Timestamp: {parent, reply}},
+	}
+}
+
+var testNames = Names{
+	Users: map[string]slack.User{
+		"U1": {ID: "U1", Name: "alice", Profile: slack.UserProfile{DisplayName: "Alice"}},
+		"U2": {ID: "U2", Name: "bob"},
+	},
+	Channels: map[string]string{"C1": "general", "C2": "random"},
+}
+
+func TestCollect(t *testing.T) {
+	conv, err := Collect(testSource(), "C1", "", Range{})
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(conv.Threads) != 3 {
+		t.Fatalf("threads = %d, want 3", len(conv.Threads))
+	}
+	if conv.Threads[0].Message.Text != "hello" {
+		t.Errorf("first message = %q, want oldest first", conv.Threads[0].Message.Text)
+	}
+	if len(conv.Threads[1].Replies) != 1 || conv.Threads[1].Replies[0].Text != "shipped" {
+		t.Errorf("thread replies = %+v", conv.Threads[1].Replies)
+	}
+	if conv.messageCount() != 4 {
+		t.Errorf("messageCount = %d, want 4", conv.messageCount())
+	}
+}
+
+func TestCollectThread(t *testing.T) {
+	conv, err := Collect(testSource(), "C1", "1700000002.000000", Range{})
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(conv.Threads) != 1 || len(conv.Threads[0].Replies) != 1 {
+		t.Fatalf("threads = %+v", conv.Threads)
+	}
+	if _, err := Collect(testSource(), "C1", "9.0", Range{}); err == nil {
+		t.Error("missing thread should fail")
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	r, err := ParseRange("2026-01-02", "2026-01-02")
+	if err != nil {
+		t.Fatalf("ParseRange: %v", err)
+	}
+	oldest := parseTS(r.Oldest)
+	latest := parseTS(r.Latest)
+	if latest.Sub(oldest) != 24*time.Hour {
+		t.Errorf("until should include the whole day: %v .. %v", oldest, latest)
+	}
+
+	if _, err := ParseRange("2026-01-03", "2026-01-01"); err == nil {
+		t.Error("inverted range should fail")
+	}
+	if _, err := ParseRange("yesterday", ""); err == nil {
+		t.Error("invalid date should fail")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"json": FormatJSON, "md": FormatMarkdown, "HTML": FormatHTML} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("ParseFormat(pdf) should fail")
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	conv, _ := Collect(testSource(), "C1", "", Range{})
+	conv.Name = "general"
+	var buf bytes.Buffer
+	if err := writeMarkdown(&buf, conv, testNames, nil); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"# #general", "**Alice**", "release @bob?", "> **bob**", "> shipped", "see #random and [docs](https://example.com) <3"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	conv, _ := Collect(testSource(), "C1", "", Range{})
+	conv.Name = "general"
+	var buf bytes.Buffer
+	if err := writeHTML(&buf, conv, testNames, nil); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"<title>#general</title>", "<style>", `<a href="https://example.com">docs</a> &lt;3`, `class="replies"`, "release @bob?"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("html missing %q", want)
+		}
+	}
+}
+
+func TestWriteSlackJSON(t *testing.T) {
+	conv, _ := Collect(testSource(), "C1", "", Range{})
+	conv.Name = "general"
+	dir := t.TempDir()
+	if _, err := Write(conv, FormatJSON, dir, testNames, nil); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	for _, f := range []string{"channels.json", "users.json"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("missing %s: %v", f, err)
+		}
+	}
+	days, _ := filepath.Glob(filepath.Join(dir, "general", "*.json"))
+	if len(days) != 1 {
+		t.Fatalf("day files = %v, want 1", days)
+	}
+	data, _ := os.ReadFile(days[0])
+	var msgs []slack.Message
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		t.Fatalf("day file is not a message array: %v", err)
+	}
+	if len(msgs) != 4 {
+		t.Errorf("messages = %d, want 4 (replies included)", len(msgs))
+	}
+}
+
+type fakeDownloader struct{ calls int }
+
+func (d *fakeDownloader) GetFile(url string, w io.Writer) error {
+	d.calls++
+	_, err := io.WriteString(w, "data:"+url)
+	return err
+}
+
+func TestWriteDownloadsFiles(t *testing.T) {
+	m := msg("1700000001.000000", "U1", "report")
+	m.Files = []slack.File{{ID: "F1", Name: "report.pdf", URLPrivateDownload: "https://files/F1"}}
+	conv := &Conversation{ChannelID: "C1", Name: "general", Threads: []Thread{{Message: m}}}
+
+	dir := t.TempDir()
+	dl := &fakeDownloader{}
+	path, err := Write(conv, FormatMarkdown, filepath.Join(dir, "general.md"), testNames, dl)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if dl.calls != 1 {
+		t.Errorf("downloads = %d, want 1", dl.calls)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "general_files", "report.pdf"))
+	if string(data) != "data:https://files/F1" {
+		t.Errorf("downloaded content = %q", data)
+	}
+	md, _ := os.ReadFile(path)
+	if !strings.Contains(string(md), "(general_files/report.pdf)") {
+		t.Errorf("markdown should link the local file:\n%s", md)
+	}
+}
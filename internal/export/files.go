@@ -0,0 +1,77 @@
+package export
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// downloadFiles downloads every file attached to conv into dir and returns
+// file ID → path relative to base. Files that fail to download are logged
+// and skipped.
+func downloadFiles(conv *Conversation, dl Downloader, dir, base string) (map[string]string, error) {
+	files := make(map[string]string)
+	used := make(map[string]bool)
+
+	for _, m := range conv.messages() {
+		for _, f := range m.Files {
+			if _, done := files[f.ID]; done {
+				continue
+			}
+			url := f.URLPrivateDownload
+			if url == "" {
+				url = f.URLPrivate
+			}
+			if url == "" {
+				continue
+			}
+			if len(files) == 0 {
+				if err := os.MkdirAll(dir, 0o755); err != nil {
+					return nil, err
+				}
+			}
+
+			name := uniqueName(sanitize(f.Name), f.ID, used)
+			dest := filepath.Join(dir, name)
+			if err := downloadTo(dl, url, dest); err != nil {
+				slog.Warn("failed to download file for export", "file", f.ID, "error", err)
+				continue
+			}
+			rel, err := filepath.Rel(base, dest)
+			if err != nil {
+				rel = dest
+			}
+			files[f.ID] = filepath.ToSlash(rel)
+		}
+	}
+	return files, nil
+}
+
+// downloadTo writes the file at url to dest.
+func downloadTo(dl Downloader, url, dest string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if err := dl.GetFile(url, out); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return err
+	}
+	return out.Close()
+}
+
+// uniqueName returns name, prefixed with the file ID when another file of
+// the same name was already saved.
+func uniqueName(name, id string, used map[string]bool) string {
+	if name == "" {
+		name = id
+	}
+	if used[strings.ToLower(name)] {
+		name = fmt.Sprintf("%s_%s", id, name)
+	}
+	used[strings.ToLower(name)] = true
+	return name
+}
@@ -0,0 +1,272 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+
+	"github.com/m96-chan/Slacko/internal/markdown"
+)
+
+// linkTokenRe matches the <url> and <url|label> tokens left after mentions
+// have been resolved.
+var linkTokenRe = regexp.MustCompile(`<([^>|]+)(?:\|([^>]*))?>`)
+
+// slackUnescaper reverses the entity escaping Slack applies to message text.
+var slackUnescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// formatText resolves mentions in Slack message text and rewrites link
+// tokens with link; the remaining text is passed through plain.
+func formatText(text string, names Names, link func(url, label string) string, plain func(string) string) string {
+	text = markdown.ResolveMentions(text, names.Users, names.Channels)
+
+	var b strings.Builder
+	last := 0
+	for _, m := range linkTokenRe.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(plain(slackUnescaper.Replace(text[last:m[0]])))
+		url := slackUnescaper.Replace(text[m[2]:m[3]])
+		label := url
+		if m[4] >= 0 && m[5] > m[4] {
+			label = slackUnescaper.Replace(text[m[4]:m[5]])
+		}
+		b.WriteString(link(url, label))
+		last = m[1]
+	}
+	b.WriteString(plain(slackUnescaper.Replace(text[last:])))
+	return b.String()
+}
+
+// title returns the heading for an export: "#general", "@alice" or a
+// thread of either.
+func (c *Conversation) title() string {
+	name := c.Name
+	if name == "" {
+		name = c.ChannelID
+	}
+	prefix := "#"
+	if strings.HasPrefix(c.ChannelID, "D") {
+		prefix = "@"
+	}
+	if c.ThreadTS != "" {
+		return "Thread in " + prefix + name
+	}
+	return prefix + name
+}
+
+// messageCount returns the number of messages including replies.
+func (c *Conversation) messageCount() int {
+	n := 0
+	for _, t := range c.Threads {
+		n += 1 + len(t.Replies)
+	}
+	return n
+}
+
+// fileLink returns the local path of a downloaded file, or its Slack
+// permalink.
+func fileLink(f slack.File, files map[string]string) string {
+	if p, ok := files[f.ID]; ok {
+		return p
+	}
+	return f.Permalink
+}
+
+// writeMarkdown renders conv as a Markdown document grouped by day, with
+// thread replies as block quotes under their parent.
+func writeMarkdown(w io.Writer, conv *Conversation, names Names, files map[string]string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", conv.title())
+	fmt.Fprintf(&b, "_Exported %s · %d messages_\n", time.Now().Format("2006-01-02 15:04"), conv.messageCount())
+
+	mdLink := func(url, label string) string {
+		if label == url {
+			return "<" + url + ">"
+		}
+		return "[" + label + "](" + url + ")"
+	}
+	plain := func(s string) string { return s }
+
+	writeMsg := func(m slack.Message, quote string) {
+		t := parseTS(m.Timestamp)
+		fmt.Fprintf(&b, "%s**%s** · %s\n", quote, authorName(m, names.Users), t.Format("15:04"))
+		text := formatText(m.Text, names, mdLink, plain)
+		if text != "" {
+			if quote != "" {
+				b.WriteString(quote + "\n")
+			} else {
+				b.WriteString("\n")
+			}
+			for _, line := range strings.Split(text, "\n") {
+				b.WriteString(quote + line + "\n")
+			}
+		}
+		for _, f := range m.Files {
+			fmt.Fprintf(&b, "%s📎 [%s](%s)\n", quote, f.Name, fileLink(f, files))
+		}
+	}
+
+	var day string
+	for _, th := range conv.Threads {
+		d := parseTS(th.Message.Timestamp).Format("Monday, January 2, 2006")
+		if d != day {
+			fmt.Fprintf(&b, "\n## %s\n", d)
+			day = d
+		}
+		b.WriteString("\n")
+		writeMsg(th.Message, "")
+		for _, r := range th.Replies {
+			b.WriteString("\n")
+			writeMsg(r, "> ")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// htmlStyle is the inline stylesheet of HTML exports.
+const htmlStyle = `body{font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;max-width:860px;margin:2em auto;padding:0 1em;color:#1d1c1d;background:#fff}
+h1{font-size:1.5em}h2{font-size:1em;color:#616061;border-bottom:1px solid #ddd;padding-bottom:.3em;margin-top:2em}
+.msg{margin:.8em 0}.author{font-weight:bold}.time{color:#616061;font-size:.85em;margin-left:.5em}
+.text{white-space:pre-wrap;margin-top:.2em}.file{margin-top:.2em}.replies{margin-left:1.2em;padding-left:.8em;border-left:3px solid #ddd}
+.meta{color:#616061;font-size:.85em}`
+
+// writeHTML renders conv as a single self-contained HTML page.
+func writeHTML(w io.Writer, conv *Conversation, names Names, files map[string]string) error {
+	var b strings.Builder
+	title := html.EscapeString(conv.title())
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n", title, htmlStyle)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<p class=\"meta\">Exported %s · %d messages</p>\n",
+		title, time.Now().Format("2006-01-02 15:04"), conv.messageCount())
+
+	htmlLink := func(url, label string) string {
+		return `<a href="` + html.EscapeString(url) + `">` + html.EscapeString(label) + `</a>`
+	}
+
+	writeMsg := func(m slack.Message) {
+		t := parseTS(m.Timestamp)
+		fmt.Fprintf(&b, "<div class=\"msg\" id=\"m%s\"><span class=\"author\">%s</span><span class=\"time\">%s</span>\n",
+			strings.ReplaceAll(m.Timestamp, ".", ""), html.EscapeString(authorName(m, names.Users)), t.Format("15:04"))
+		if text := formatText(m.Text, names, htmlLink, html.EscapeString); text != "" {
+			fmt.Fprintf(&b, "<div class=\"text\">%s</div>\n", text)
+		}
+		for _, f := range m.Files {
+			fmt.Fprintf(&b, "<div class=\"file\">📎 %s</div>\n", htmlLink(fileLink(f, files), f.Name))
+		}
+		b.WriteString("</div>\n")
+	}
+
+	var day string
+	for _, th := range conv.Threads {
+		d := parseTS(th.Message.Timestamp).Format("Monday, January 2, 2006")
+		if d != day {
+			fmt.Fprintf(&b, "<h2>%s</h2>\n", d)
+			day = d
+		}
+		writeMsg(th.Message)
+		if len(th.Replies) > 0 {
+			b.WriteString("<div class=\"replies\">\n")
+			for _, r := range th.Replies {
+				writeMsg(r)
+			}
+			b.WriteString("</div>\n")
+		}
+	}
+	b.WriteString("</body>\n</html>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// exportChannel is an entry of channels.json / dms.json in a Slack export.
+type exportChannel struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name,omitempty"`
+	Members []string `json:"members,omitempty"`
+}
+
+// exportUser is an entry of users.json in a Slack export.
+type exportUser struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	RealName string            `json:"real_name,omitempty"`
+	Deleted  bool              `json:"deleted,omitempty"`
+	IsBot    bool              `json:"is_bot,omitempty"`
+	Profile  slack.UserProfile `json:"profile"`
+}
+
+// writeSlackJSON writes conv in Slack's export layout: channels.json (or
+// dms.json), users.json and one <channel>/<YYYY-MM-DD>.json file per day
+// containing that day's messages, replies included.
+func writeSlackJSON(dir string, conv *Conversation, names Names) error {
+	folder := sanitize(conv.Name)
+	if folder == "" {
+		folder = conv.ChannelID
+	}
+	if err := os.MkdirAll(filepath.Join(dir, folder), 0o755); err != nil {
+		return err
+	}
+
+	msgs := conv.messages()
+
+	// Channel list.
+	listFile := "channels.json"
+	if strings.HasPrefix(conv.ChannelID, "D") {
+		listFile = "dms.json"
+	}
+	if err := writeJSONFile(filepath.Join(dir, listFile), []exportChannel{{ID: conv.ChannelID, Name: folder}}); err != nil {
+		return err
+	}
+
+	// Users that authored messages.
+	seen := make(map[string]bool)
+	var users []exportUser
+	for _, m := range msgs {
+		u, ok := names.Users[m.User]
+		if !ok || seen[m.User] {
+			continue
+		}
+		seen[m.User] = true
+		users = append(users, exportUser{ID: u.ID, Name: u.Name, RealName: u.RealName, Deleted: u.Deleted, IsBot: u.IsBot, Profile: u.Profile})
+	}
+	if users == nil {
+		users = []exportUser{}
+	}
+	if err := writeJSONFile(filepath.Join(dir, "users.json"), users); err != nil {
+		return err
+	}
+
+	// One file per day.
+	byDay := make(map[string][]slack.Message)
+	var days []string
+	for _, m := range msgs {
+		d := parseTS(m.Timestamp).UTC().Format("2006-01-02")
+		if _, ok := byDay[d]; !ok {
+			days = append(days, d)
+		}
+		byDay[d] = append(byDay[d], m)
+	}
+	for _, d := range days {
+		if err := writeJSONFile(filepath.Join(dir, folder, d+".json"), byDay[d]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSONFile writes v as indented JSON.
+func writeJSONFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
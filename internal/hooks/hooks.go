@@ -0,0 +1,195 @@
+// Package hooks runs user-configured executables for incoming Slack events
+// and decodes the actions they ask the app to perform.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/m96-chan/Slacko/internal/config"
+)
+
+// maxConcurrent limits how many hook processes run at once, so a burst of
+// events does not fork an unbounded number of processes.
+const maxConcurrent = 8
+
+// waitDelay bounds how long a hook's output pipes are drained after it
+// exits or is killed, e.g. when it left a child running.
+const waitDelay = time.Second
+
+// Action types a hook can return.
+const (
+	ActionSend   = "send"
+	ActionReact  = "react"
+	ActionNotify = "notify"
+)
+
+// Action is something a hook asks the app to do.
+type Action struct {
+	Type string `json:"type"`
+	// Channel is the target channel for send and react.
+	Channel string `json:"channel,omitempty"`
+	// Text is the message for send and the notification body for notify.
+	Text string `json:"text,omitempty"`
+	// ThreadTS makes a send a thread reply.
+	ThreadTS string `json:"thread_ts,omitempty"`
+	// Timestamp is the message to react to.
+	Timestamp string `json:"timestamp,omitempty"`
+	// Name is the reaction emoji name, without colons.
+	Name string `json:"name,omitempty"`
+	// Title is the notification title.
+	Title string `json:"title,omitempty"`
+}
+
+// validate checks that the action has the fields its type needs.
+func (a Action) validate() error {
+	switch a.Type {
+	case ActionSend:
+		if a.Channel == "" || a.Text == "" {
+			return errors.New("send needs channel and text")
+		}
+	case ActionReact:
+		if a.Channel == "" || a.Timestamp == "" || a.Name == "" {
+			return errors.New("react needs channel, timestamp and name")
+		}
+	case ActionNotify:
+		if a.Title == "" && a.Text == "" {
+			return errors.New("notify needs title or text")
+		}
+	default:
+		return fmt.Errorf("unknown action type %q", a.Type)
+	}
+	return nil
+}
+
+// Event is the JSON document written to a hook's stdin.
+type Event struct {
+	Type   string `json:"type"`
+	TeamID string `json:"team_id,omitempty"`
+	UserID string `json:"user_id,omitempty"`
+	// Data is the Slack event, or nil for events without a payload.
+	Data any `json:"event,omitempty"`
+}
+
+// output is the JSON document a hook may print to stdout.
+type output struct {
+	Actions []Action `json:"actions"`
+}
+
+// Runner dispatches events to the hooks in a config. Hooks run concurrently
+// in the background and their actions are passed to onAction.
+type Runner struct {
+	hooks    func() []config.Hook
+	teamID   string
+	userID   string
+	onAction func(Action)
+	sem      chan struct{}
+}
+
+// NewRunner creates a Runner for the configured hooks, which hooks returns.
+// It is called on each event, so a reloaded config takes effect
+// immediately, and must be safe to call from any goroutine. teamID and
+// userID identify the connected workspace in the event JSON. onAction is
+// called from a background goroutine.
+func NewRunner(hooks func() []config.Hook, teamID, userID string, onAction func(Action)) *Runner {
+	return &Runner{
+		hooks:    hooks,
+		teamID:   teamID,
+		userID:   userID,
+		onAction: onAction,
+		sem:      make(chan struct{}, maxConcurrent),
+	}
+}
+
+// Dispatch starts every hook configured for event with data as the payload.
+// It does not wait for the hooks to finish.
+func (r *Runner) Dispatch(event string, data any) {
+	var hooks []config.Hook
+	for _, h := range r.hooks() {
+		if h.Event == event {
+			hooks = append(hooks, h)
+		}
+	}
+	if len(hooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(Event{Type: event, TeamID: r.teamID, UserID: r.userID, Data: data})
+	if err != nil {
+		slog.Warn("failed to encode hook event", "event", event, "error", err)
+		return
+	}
+
+	for _, h := range hooks {
+		go func() {
+			r.sem <- struct{}{}
+			defer func() { <-r.sem }()
+
+			actions, err := Run(h, payload)
+			if err != nil {
+				slog.Warn("hook failed", "event", h.Event, "exec", h.Exec, "error", err)
+				return
+			}
+			for _, act := range actions {
+				if r.onAction != nil {
+					r.onAction(act)
+				}
+			}
+		}()
+	}
+}
+
+// Run executes a hook with payload on stdin and returns the actions it
+// printed. Empty output means no actions. A non-zero exit status is
+// reported with the first line of stderr.
+func Run(h config.Hook, payload []byte) ([]Action, error) {
+	timeout := h.TimeoutDuration()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	c := exec.CommandContext(ctx, h.Exec, h.Args...)
+	c.Env = append(os.Environ(), "SLACKO_EVENT="+h.Event)
+	c.WaitDelay = waitDelay
+	c.Stdin = bytes.NewReader(payload)
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	err := c.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		if msg, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return parseActions(stdout.Bytes())
+}
+
+// parseActions decodes a hook's stdout. Invalid actions are an error so
+// that mistakes in a hook are not silently ignored.
+func parseActions(data []byte) ([]Action, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	var out output
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("invalid output: %w", err)
+	}
+	for i, act := range out.Actions {
+		if err := act.validate(); err != nil {
+			return nil, fmt.Errorf("actions[%d]: %w", i, err)
+		}
+	}
+	return out.Actions, nil
+}
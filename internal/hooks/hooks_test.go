@@ -0,0 +1,106 @@
+package hooks
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack/slackevents"
+
+	"github.com/m96-chan/Slacko/internal/config"
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
+)
+
+func shellHook(t *testing.T, event, script string) config.Hook {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	return config.Hook{Event: event, Exec: "sh", Args: []string{"-c", script}}
+}
+
+func TestRunReturnsActions(t *testing.T) {
+	// Echo the channel from the event back as a reaction target.
+	h := shellHook(t, "message", `
+		input=$(cat)
+		case "$input" in *'"channel":"C1"'*) ;; *) echo "bad input: $input" >&2; exit 1;; esac
+		echo '{"actions": [{"type": "react", "channel": "C1", "timestamp": "1.2", "name": "eyes"}]}'
+	`)
+
+	actions, err := Run(h, []byte(`{"type":"message","event":{"channel":"C1"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 1 || actions[0].Type != ActionReact || actions[0].Name != "eyes" {
+		t.Errorf("actions = %+v", actions)
+	}
+}
+
+func TestRunNoOutput(t *testing.T) {
+	h := shellHook(t, "message", "cat >/dev/null")
+
+	actions, err := Run(h, []byte(`{}`))
+	if err != nil || actions != nil {
+		t.Errorf("Run = %v, %v; want no actions and no error", actions, err)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		timeout int
+		want    string
+	}{
+		{"exit status", "echo broken >&2; exit 2", 0, "exit status 2: broken"},
+		{"timeout", "exec sleep 5", 50, "timed out after 50ms"},
+		{"invalid json", "echo nope", 0, "invalid output"},
+		{"invalid action", `echo '{"actions": [{"type": "send", "channel": "C1"}]}'`, 0, "actions[0]: send needs channel and text"},
+		{"unknown action", `echo '{"actions": [{"type": "explode"}]}'`, 0, `unknown action type "explode"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := shellHook(t, "message", tt.script)
+			h.Timeout = tt.timeout
+			_, err := Run(h, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapDispatchesToMatchingHooks(t *testing.T) {
+	h := shellHook(t, config.HookReactionAdded, `
+		grep -q '"reaction":"tada"' || exit 1
+		echo '{"actions": [{"type": "notify", "title": "'"$SLACKO_EVENT"'", "text": "hi"}]}'
+	`)
+	cfg := &config.Config{Hooks: []config.Hook{
+		h,
+		{Event: config.HookMessage, Exec: "false"},
+	}}
+
+	actions := make(chan Action, 1)
+	runner := NewRunner(func() []config.Hook { return cfg.Hooks }, "T1", "U1", func(act Action) { actions <- act })
+
+	called := false
+	handler := Wrap(&slackclient.EventHandler{
+		OnReactionAdded: func(*slackevents.ReactionAddedEvent) { called = true },
+	}, runner.Dispatch)
+	handler.OnReactionAdded(&slackevents.ReactionAddedEvent{Reaction: "tada"})
+	// Callbacks the app does not handle still run hooks without panicking.
+	handler.OnTeamJoin(&slackevents.TeamJoinEvent{})
+
+	if !called {
+		t.Error("wrapped callback was not called")
+	}
+	select {
+	case act := <-actions:
+		if act.Type != ActionNotify || act.Title != config.HookReactionAdded {
+			t.Errorf("action = %+v", act)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("hook action not delivered")
+	}
+}
@@ -0,0 +1,61 @@
+package hooks
+
+import (
+	"github.com/m96-chan/Slacko/internal/config"
+	slackclient "github.com/m96-chan/Slacko/internal/slack"
+)
+
+// Wrap returns an EventHandler that calls h and then passes each event to
+// dispatch with its hook event name (one of config.HookEvents) and payload.
+// Callbacks that h leaves nil still dispatch.
+func Wrap(h *slackclient.EventHandler, dispatch func(event string, data any)) *slackclient.EventHandler {
+	return &slackclient.EventHandler{
+		OnMessage:             wrap(dispatch, config.HookMessage, h.OnMessage),
+		OnMessageChanged:      wrap(dispatch, config.HookMessageChanged, h.OnMessageChanged),
+		OnMessageDeleted:      wrap(dispatch, config.HookMessageDeleted, h.OnMessageDeleted),
+		OnReactionAdded:       wrap(dispatch, config.HookReactionAdded, h.OnReactionAdded),
+		OnReactionRemoved:     wrap(dispatch, config.HookReactionRemoved, h.OnReactionRemoved),
+		OnChannelCreated:      wrap(dispatch, config.HookChannelCreated, h.OnChannelCreated),
+		OnChannelArchive:      wrap(dispatch, config.HookChannelArchive, h.OnChannelArchive),
+		OnChannelUnarchive:    wrap(dispatch, config.HookChannelUnarchive, h.OnChannelUnarchive),
+		OnChannelRename:       wrap(dispatch, config.HookChannelRename, h.OnChannelRename),
+		OnMemberJoinedChannel: wrap(dispatch, config.HookMemberJoinedChannel, h.OnMemberJoinedChannel),
+		OnMemberLeftChannel:   wrap(dispatch, config.HookMemberLeftChannel, h.OnMemberLeftChannel),
+		OnTeamJoin:            wrap(dispatch, config.HookTeamJoin, h.OnTeamJoin),
+		OnPinAdded:            wrap(dispatch, config.HookPinAdded, h.OnPinAdded),
+		OnPinRemoved:          wrap(dispatch, config.HookPinRemoved, h.OnPinRemoved),
+		OnFileShared:          wrap(dispatch, config.HookFileShared, h.OnFileShared),
+		OnUserStatusChanged:   wrap(dispatch, config.HookUserStatusChanged, h.OnUserStatusChanged),
+		OnTyping:              wrap(dispatch, config.HookTyping, h.OnTyping),
+		OnPresenceChange:      wrap(dispatch, config.HookPresenceChange, h.OnPresenceChange),
+		OnConnected: func() {
+			if h.OnConnected != nil {
+				h.OnConnected()
+			}
+			dispatch(config.HookConnected, nil)
+		},
+		OnDisconnected: func() {
+			if h.OnDisconnected != nil {
+				h.OnDisconnected()
+			}
+			dispatch(config.HookDisconnected, nil)
+		},
+		OnError: func(err error) {
+			if h.OnError != nil {
+				h.OnError(err)
+			}
+			dispatch(config.HookError, map[string]string{"error": err.Error()})
+		},
+	}
+}
+
+// wrap returns a callback that calls next, if set, and then dispatches the
+// event.
+func wrap[T any](dispatch func(event string, data any), event string, next func(*T)) func(*T) {
+	return func(evt *T) {
+		if next != nil {
+			next(evt)
+		}
+		dispatch(event, evt)
+	}
+}
@@ -0,0 +1,97 @@
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/m96-chan/Slacko/internal/consts"
+)
+
+// commandStore keeps tokens with an external program. Each operation is an
+// argv template in which "{key}" is replaced with the token key; the token
+// is read from stdout for get and written to stdin for set.
+type commandStore struct {
+	name          string
+	get, set, del []string
+}
+
+// passStore stores tokens in pass (https://www.passwordstore.org) under
+// "slacko/<key>".
+func passStore() commandStore {
+	entry := consts.Name + "/{key}"
+	return commandStore{
+		name: "pass",
+		get:  []string{"pass", "show", entry},
+		set:  []string{"pass", "insert", "--multiline", "--force", entry},
+		del:  []string{"pass", "rm", "--force", entry},
+	}
+}
+
+// newCommandStore runs command as "<command> get|set|delete <key>". command
+// may include arguments, separated by spaces.
+func newCommandStore(command string) commandStore {
+	argv := strings.Fields(command)
+	with := func(action string) []string {
+		return append(append([]string{}, argv...), action, "{key}")
+	}
+	return commandStore{
+		name: "command " + command,
+		get:  with("get"),
+		set:  with("set"),
+		del:  with("delete"),
+	}
+}
+
+// Get returns the command's output without the trailing newline. A failing
+// command is reported as ErrNotFound, since that is what helpers do for
+// missing entries.
+func (s commandStore) Get(key string) (string, error) {
+	out, err := s.run(s.get, key, "")
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("%w: %v", ErrNotFound, err)
+		}
+		return "", err
+	}
+	return strings.TrimRight(out, "\r\n"), nil
+}
+
+func (s commandStore) Set(key, value string) error {
+	_, err := s.run(s.set, key, value+"\n")
+	return err
+}
+
+func (s commandStore) Delete(key string) error {
+	_, err := s.run(s.del, key, "")
+	return err
+}
+
+func (s commandStore) String() string {
+	return s.name
+}
+
+// run executes the argv template for key, feeding stdin to the command.
+// Errors include the command's stderr.
+func (s commandStore) run(tmpl []string, key, stdin string) (string, error) {
+	argv := make([]string, len(tmpl))
+	for i, arg := range tmpl {
+		argv[i] = strings.ReplaceAll(arg, "{key}", key)
+	}
+
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s %s: %w: %s", argv[0], argv[1], err, msg)
+		}
+		return "", fmt.Errorf("%s %s: %w", argv[0], argv[1], err)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,60 @@
+package keyring
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// helperScript is a credential helper that keeps each key in a file.
+const helperScript = `#!/bin/sh
+dir="$(dirname "$0")/store"
+mkdir -p "$dir"
+case "$1" in
+get) cat "$dir/$2" ;;
+set) cat > "$dir/$2" ;;
+delete) rm "$dir/$2" ;;
+*) exit 2 ;;
+esac
+`
+
+func TestCommandStore(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	helper := filepath.Join(t.TempDir(), "helper")
+	if err := os.WriteFile(helper, []byte(helperScript), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewStore(BackendCommand, Options{Command: helper})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get("user_T1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get missing = %v, want ErrNotFound", err)
+	}
+	if err := s.Set("user_T1", "xoxp-1"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got, err := s.Get("user_T1"); err != nil || got != "xoxp-1" {
+		t.Errorf("Get = %q, %v", got, err)
+	}
+	if err := s.Delete("user_T1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get("user_T1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestNewStoreErrors(t *testing.T) {
+	if _, err := NewStore(BackendCommand, Options{}); err == nil {
+		t.Error("command backend without a command should fail")
+	}
+	if _, err := NewStore("vault", Options{}); err == nil {
+		t.Error("unknown backend should fail")
+	}
+}
@@ -0,0 +1,308 @@
+package keyring
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const credentialsFile = "credentials.enc"
+
+// scrypt parameters for new files. They are stored in the file, so they can
+// be raised later without breaking existing files.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// ErrWrongPassphrase is returned when the encrypted file can't be decrypted.
+var ErrWrongPassphrase = errors.New("wrong passphrase for encrypted credentials")
+
+// encryptedFile is the on-disk format of the token file. Data is the
+// AES-256-GCM encrypted JSON object of keys to tokens.
+type encryptedFile struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	N       int    `json:"n"`
+	R       int    `json:"r"`
+	P       int    `json:"p"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// FileStore keeps tokens in a file encrypted with a key derived from a
+// passphrase. The passphrase is asked for once, on first use. Other
+// processes may share the file: every change re-reads it under a lock file,
+// so their writes aren't lost.
+type FileStore struct {
+	path       string
+	passphrase func(confirm bool) ([]byte, error)
+
+	mu     sync.Mutex
+	key    []byte // derived key, nil until unlocked
+	header encryptedFile
+	tokens map[string]string
+}
+
+// NewFileStore returns a store backed by the encrypted file at path.
+// passphrase is called when the file is first read or created.
+func NewFileStore(path string, passphrase func(confirm bool) ([]byte, error)) *FileStore {
+	return &FileStore{path: path, passphrase: passphrase}
+}
+
+// Unlock asks for the passphrase and decrypts the file, if that hasn't
+// happened yet. A missing file is created on the first Set.
+func (s *FileStore) Unlock() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.unlock()
+}
+
+func (s *FileStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.key == nil && !s.exists() {
+		return "", ErrNotFound
+	}
+	if err := s.refresh(); err != nil {
+		return "", err
+	}
+	v, ok := s.tokens[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+func (s *FileStore) Set(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.update(func(tokens map[string]string) error {
+		tokens[key] = value
+		return nil
+	})
+}
+
+func (s *FileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.key == nil && !s.exists() {
+		return ErrNotFound
+	}
+	return s.update(func(tokens map[string]string) error {
+		if _, ok := tokens[key]; !ok {
+			return ErrNotFound
+		}
+		delete(tokens, key)
+		return nil
+	})
+}
+
+func (s *FileStore) String() string {
+	return "the encrypted file " + s.path
+}
+
+// exists reports whether the file has been created.
+func (s *FileStore) exists() bool {
+	_, err := os.Stat(s.path)
+	return err == nil
+}
+
+// unlock derives the key and loads the tokens. Must be called with s.mu held.
+func (s *FileStore) unlock() error {
+	if s.key != nil {
+		return nil
+	}
+	if s.passphrase == nil {
+		return errors.New("encrypted credentials need a passphrase")
+	}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		pass, err := s.passphrase(true)
+		if err != nil {
+			return err
+		}
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+		hdr := encryptedFile{Version: 1, KDF: "scrypt", N: scryptN, R: scryptR, P: scryptP, Salt: salt}
+		key, err := deriveKey(pass, hdr)
+		if err != nil {
+			return err
+		}
+		s.key, s.header, s.tokens = key, hdr, map[string]string{}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var hdr encryptedFile
+	if err := json.Unmarshal(data, &hdr); err != nil {
+		return fmt.Errorf("parse %s: %w", s.path, err)
+	}
+	if hdr.Version != 1 || hdr.KDF != "scrypt" {
+		return fmt.Errorf("%s: unsupported format (version %d, kdf %q)", s.path, hdr.Version, hdr.KDF)
+	}
+	pass, err := s.passphrase(false)
+	if err != nil {
+		return err
+	}
+	key, err := deriveKey(pass, hdr)
+	if err != nil {
+		return err
+	}
+	plain, err := open(key, hdr.Nonce, hdr.Data)
+	if err != nil {
+		return ErrWrongPassphrase
+	}
+	tokens := map[string]string{}
+	if err := json.Unmarshal(plain, &tokens); err != nil {
+		return fmt.Errorf("parse %s: %w", s.path, err)
+	}
+	s.key, s.header, s.tokens = key, hdr, tokens
+	return nil
+}
+
+// refresh unlocks the store on first use and otherwise re-reads the file,
+// picking up changes made by other processes. Must be called with s.mu held.
+func (s *FileStore) refresh() error {
+	if s.key == nil {
+		return s.unlock()
+	}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		s.tokens = map[string]string{}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var hdr encryptedFile
+	if err := json.Unmarshal(data, &hdr); err != nil {
+		return fmt.Errorf("parse %s: %w", s.path, err)
+	}
+	if hdr.KDF != s.header.KDF || !bytes.Equal(hdr.Salt, s.header.Salt) ||
+		hdr.N != s.header.N || hdr.R != s.header.R || hdr.P != s.header.P {
+		// The file was recreated by another process; derive the key again.
+		s.key = nil
+		return s.unlock()
+	}
+	plain, err := open(s.key, hdr.Nonce, hdr.Data)
+	if err != nil {
+		return ErrWrongPassphrase
+	}
+	tokens := map[string]string{}
+	if err := json.Unmarshal(plain, &tokens); err != nil {
+		return fmt.Errorf("parse %s: %w", s.path, err)
+	}
+	s.tokens = tokens
+	return nil
+}
+
+// update applies fn to the current contents of the file and saves the
+// result. The lock file is held from the read to the write, so concurrent
+// updates from other processes are merged instead of overwritten. Must be
+// called with s.mu held.
+func (s *FileStore) update(fn func(tokens map[string]string) error) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	unlockFile, err := lockFile(s.path + ".lock")
+	if err != nil {
+		return fmt.Errorf("lock %s: %w", s.path, err)
+	}
+	defer unlockFile()
+
+	if err := s.refresh(); err != nil {
+		return err
+	}
+	if err := fn(s.tokens); err != nil {
+		return err
+	}
+	return s.save()
+}
+
+// save encrypts the tokens with a fresh nonce and replaces the file
+// atomically. Must be called with s.mu and the lock file held.
+func (s *FileStore) save() error {
+	plain, err := json.Marshal(s.tokens)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return err
+	}
+	hdr := s.header
+	hdr.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(hdr.Nonce); err != nil {
+		return err
+	}
+	hdr.Data = gcm.Seal(nil, hdr.Nonce, plain, nil)
+	data, err := json.MarshalIndent(hdr, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, s.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	s.header = hdr
+	return nil
+}
+
+// deriveKey derives the AES-256 key from the passphrase with the scrypt
+// parameters of hdr.
+func deriveKey(pass []byte, hdr encryptedFile) ([]byte, error) {
+	if len(pass) == 0 {
+		return nil, errors.New("empty passphrase")
+	}
+	return scrypt.Key(pass, hdr.Salt, hdr.N, hdr.R, hdr.P, 32)
+}
+
+// open decrypts data sealed with key and nonce.
+func open(key, nonce, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+	return gcm.Open(nil, nonce, data, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,128 @@
+package keyring
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// passphrase returns a passphrase callback that counts its calls.
+func passphrase(pass string, calls *int) func(bool) ([]byte, error) {
+	return func(bool) ([]byte, error) {
+		*calls++
+		return []byte(pass), nil
+	}
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), credentialsFile)
+	calls := 0
+	s := NewFileStore(path, passphrase("hunter2", &calls))
+
+	if _, err := s.Get("user_T1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get before any Set = %v, want ErrNotFound", err)
+	}
+	if calls != 0 {
+		t.Error("a missing file should not ask for the passphrase")
+	}
+
+	if err := s.Set("user_T1", "xoxp-1"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := s.Set("app_T1", "xapp-1"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("passphrase asked %d times, want 1", calls)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "xoxp-1") {
+		t.Error("token stored in plain text")
+	}
+
+	// A new store reads the file back with the same passphrase.
+	reopened := NewFileStore(path, passphrase("hunter2", &calls))
+	if got, err := reopened.Get("user_T1"); err != nil || got != "xoxp-1" {
+		t.Errorf("Get after reopen = %q, %v", got, err)
+	}
+	if err := reopened.Delete("user_T1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := reopened.Get("user_T1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if got, _ := reopened.Get("app_T1"); got != "xapp-1" {
+		t.Errorf("app_T1 = %q after deleting another key", got)
+	}
+}
+
+func TestFileStoreWrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), credentialsFile)
+	calls := 0
+	if err := NewFileStore(path, passphrase("right", &calls)).Set("k", "v"); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewFileStore(path, passphrase("wrong", &calls))
+	if _, err := s.Get("k"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Get = %v, want ErrWrongPassphrase", err)
+	}
+	if err := s.Set("k", "other"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Set = %v, want ErrWrongPassphrase", err)
+	}
+
+	// The failed attempts must not have overwritten the file.
+	if got, err := NewFileStore(path, passphrase("right", &calls)).Get("k"); err != nil || got != "v" {
+		t.Errorf("Get with the right passphrase = %q, %v", got, err)
+	}
+}
+
+func TestFileStoreSharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), credentialsFile)
+	calls := 0
+	a := NewFileStore(path, passphrase("hunter2", &calls))
+	b := NewFileStore(path, passphrase("hunter2", &calls))
+
+	if err := a.Set("user_T1", "xoxp-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := b.Get("user_T1"); err != nil || got != "xoxp-1" {
+		t.Fatalf("b.Get = %q, %v", got, err)
+	}
+
+	// Both stores have the file loaded; neither write may drop the other.
+	if err := a.Set("user_T2", "xoxp-2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Set("user_T3", "xoxp-3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Delete("user_T1"); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewFileStore(path, passphrase("hunter2", &calls))
+	for key, want := range map[string]string{"user_T2": "xoxp-2", "user_T3": "xoxp-3"} {
+		if got, err := check.Get(key); err != nil || got != want {
+			t.Errorf("%s = %q, %v, want %q", key, got, err, want)
+		}
+	}
+	if _, err := b.Get("user_T1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("b.Get after a.Delete = %v, want ErrNotFound", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp"))
+	if len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
@@ -1,12 +1,6 @@
 package keyring
 
-import (
-	"os"
-
-	gokeyring "github.com/zalando/go-keyring"
-
-	"github.com/m96-chan/Slacko/internal/consts"
-)
+import "os"
 
 const (
 	userTokenUser = "user_token"
@@ -17,7 +11,7 @@ const (
 )
 
 // GetUserToken returns the user token from the SLACKO_USER_TOKEN env var,
-// falling back to SLACKO_BOT_TOKEN (legacy), then the system keyring.
+// falling back to SLACKO_BOT_TOKEN (legacy), then the credential store.
 func GetUserToken() (string, error) {
 	if v := os.Getenv("SLACKO_USER_TOKEN"); v != "" {
 		return v, nil
@@ -27,38 +21,38 @@ func GetUserToken() (string, error) {
 		return v, nil
 	}
 	// Try new keyring key first, then legacy.
-	tok, err := gokeyring.Get(consts.Name, userTokenUser)
+	tok, err := store.Get(userTokenUser)
 	if err == nil {
 		return tok, nil
 	}
-	return gokeyring.Get(consts.Name, legacyBotTokenUser)
+	return store.Get(legacyBotTokenUser)
 }
 
 // GetAppToken returns the app-level token from the SLACKO_APP_TOKEN env var,
-// falling back to the system keyring.
+// falling back to the credential store.
 func GetAppToken() (string, error) {
 	if v := os.Getenv("SLACKO_APP_TOKEN"); v != "" {
 		return v, nil
 	}
-	return gokeyring.Get(consts.Name, appTokenUser)
+	return store.Get(appTokenUser)
 }
 
-// SetUserToken stores the user token in the system keyring.
+// SetUserToken stores the user token in the credential store.
 func SetUserToken(token string) error {
-	return gokeyring.Set(consts.Name, userTokenUser, token)
+	return store.Set(userTokenUser, token)
 }
 
-// SetAppToken stores the app-level token in the system keyring.
+// SetAppToken stores the app-level token in the credential store.
 func SetAppToken(token string) error {
-	return gokeyring.Set(consts.Name, appTokenUser, token)
+	return store.Set(appTokenUser, token)
 }
 
-// DeleteUserToken removes the user token from the system keyring.
+// DeleteUserToken removes the user token from the credential store.
 func DeleteUserToken() error {
-	return gokeyring.Delete(consts.Name, userTokenUser)
+	return store.Delete(userTokenUser)
 }
 
-// DeleteAppToken removes the app-level token from the system keyring.
+// DeleteAppToken removes the app-level token from the credential store.
 func DeleteAppToken() error {
-	return gokeyring.Delete(consts.Name, appTokenUser)
+	return store.Delete(appTokenUser)
 }
@@ -0,0 +1,180 @@
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	gokeyring "github.com/zalando/go-keyring"
+
+	"github.com/m96-chan/Slacko/internal/consts"
+)
+
+// Credential backends for the credentials.backend setting.
+const (
+	// BackendAuto uses the system keyring, or the encrypted file when no
+	// keyring is available (e.g. headless Linux without a Secret Service).
+	BackendAuto    = "auto"
+	BackendKeyring = "keyring"
+	BackendFile    = "file"
+	BackendPass    = "pass"
+	BackendCommand = "command"
+)
+
+// Backends lists the valid credentials.backend values.
+var Backends = []string{BackendAuto, BackendKeyring, BackendFile, BackendPass, BackendCommand}
+
+// ErrNotFound is returned when a token is not stored in the backend.
+var ErrNotFound = gokeyring.ErrNotFound
+
+// Store is a place tokens are kept, addressed by key (e.g. "user_T123").
+// String describes where, for messages.
+type Store interface {
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+	String() string
+}
+
+// store is the backend used by the token and workspace functions. It is set
+// once at startup with Use.
+var store Store = keyringStore{}
+
+// Use makes s the backend for all stored tokens.
+func Use(s Store) {
+	store = s
+}
+
+// Current returns the backend in use.
+func Current() Store {
+	return store
+}
+
+// Unlock asks for the passphrase of the encrypted file now when that is the
+// backend in use, so the prompt doesn't appear while the TUI owns the
+// terminal. It does nothing for the other backends.
+func Unlock() error {
+	if fs, ok := store.(*FileStore); ok {
+		return fs.Unlock()
+	}
+	return nil
+}
+
+// Options configures NewStore.
+type Options struct {
+	// Command is the program used by BackendCommand.
+	Command string
+	// Passphrase returns the passphrase of the encrypted file. confirm is
+	// true when the file is about to be created and the passphrase should
+	// be entered twice.
+	Passphrase func(confirm bool) ([]byte, error)
+}
+
+// NewStore returns the store for the named backend. BackendAuto probes the
+// system keyring and falls back to the encrypted file when it is not
+// available.
+func NewStore(backend string, opts Options) (Store, error) {
+	switch backend {
+	case BackendAuto, "":
+		if err := probeKeyring(); err != nil {
+			slog.Warn("system keyring unavailable, using encrypted file", "error", err)
+			return NewFileStore(FilePath(), opts.Passphrase), nil
+		}
+		return keyringStore{}, nil
+	case BackendKeyring:
+		return keyringStore{}, nil
+	case BackendFile:
+		return NewFileStore(FilePath(), opts.Passphrase), nil
+	case BackendPass:
+		return passStore(), nil
+	case BackendCommand:
+		if opts.Command == "" {
+			return nil, errors.New("credentials.command is required for the command backend")
+		}
+		return newCommandStore(opts.Command), nil
+	default:
+		return nil, fmt.Errorf("unknown credential backend %q", backend)
+	}
+}
+
+// FilePath returns the path of the encrypted token file, which lives next
+// to the workspace registry.
+func FilePath() string {
+	return filepath.Join(consts.CacheDir, credentialsFile)
+}
+
+// probeKeyring reports whether the system keyring can be used. Looking up a
+// key that doesn't exist succeeds with ErrNotFound when it can.
+func probeKeyring() error {
+	_, err := gokeyring.Get(consts.Name, "probe")
+	if err == nil || errors.Is(err, gokeyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// keyringStore keeps tokens in the system keyring.
+type keyringStore struct{}
+
+func (keyringStore) Get(key string) (string, error) {
+	return gokeyring.Get(consts.Name, key)
+}
+
+func (keyringStore) Set(key, value string) error {
+	return gokeyring.Set(consts.Name, key, value)
+}
+
+func (keyringStore) Delete(key string) error {
+	return gokeyring.Delete(consts.Name, key)
+}
+
+func (keyringStore) String() string {
+	return "the system keyring"
+}
+
+// StoredKeys returns the keys of every token Slacko may have stored: the
+// default tokens and those of each registered workspace.
+func StoredKeys() []string {
+	keys := []string{userTokenUser, appTokenUser, legacyBotTokenUser}
+	ws, _ := ListWorkspaces()
+	for _, w := range ws {
+		for _, k := range []string{w.UserKey, w.AppKey, w.BotKey, w.RefreshKey} {
+			if k != "" {
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys
+}
+
+// Migrate copies every stored token from one backend to another and then
+// deletes it from the source. Nothing is deleted unless all tokens were
+// copied. It returns the number of tokens moved.
+func Migrate(from, to Store) (int, error) {
+	if from.String() == to.String() {
+		return 0, fmt.Errorf("tokens are already stored in %s", to)
+	}
+
+	var moved []string
+	for _, key := range StoredKeys() {
+		value, err := from.Get(key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("read %s: %w", key, err)
+		}
+		if err := to.Set(key, value); err != nil {
+			return 0, fmt.Errorf("store %s: %w", key, err)
+		}
+		moved = append(moved, key)
+	}
+
+	for _, key := range moved {
+		if err := from.Delete(key); err != nil {
+			slog.Warn("failed to delete migrated token", "key", key, "error", err)
+		}
+	}
+	return len(moved), nil
+}
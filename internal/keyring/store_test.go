@@ -0,0 +1,46 @@
+package keyring
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	useTempRegistry(t)
+	if err := AddWorkspace("T1", "One", "xoxp-1", "xapp-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetUserToken("xoxp-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	file := NewFileStore(filepath.Join(t.TempDir(), credentialsFile), passphrase("pw", &calls))
+	n, err := Migrate(Current(), file)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("moved %d tokens, want 3", n)
+	}
+	if _, err := Current().Get("user_T1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("source still has user_T1: %v", err)
+	}
+
+	orig := Current()
+	Use(file)
+	t.Cleanup(func() { Use(orig) })
+	w, _ := FindWorkspace("T1")
+	tokens, err := GetWorkspaceTokens(w)
+	if err != nil || tokens.UserToken != "xoxp-1" || tokens.AppToken != "xapp-1" {
+		t.Errorf("tokens after migration = %+v, %v", tokens, err)
+	}
+	if got, err := GetUserToken(); err != nil || got != "xoxp-1" {
+		t.Errorf("default user token = %q, %v", got, err)
+	}
+
+	if _, err := Migrate(file, file); err == nil {
+		t.Error("migrating to the same store should fail")
+	}
+}
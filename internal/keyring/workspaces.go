@@ -2,10 +2,11 @@ package keyring
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
-
-	gokeyring "github.com/zalando/go-keyring"
+	"strings"
+	"time"
 
 	"github.com/m96-chan/Slacko/internal/consts"
 )
@@ -17,12 +18,20 @@ type Workspace struct {
 	UserKey string `json:"user_key,omitempty"` // keyring key for user token
 	AppKey  string `json:"app_key"`            // keyring key for app token
 	BotKey  string `json:"bot_key,omitempty"`  // legacy keyring key (read-only fallback)
+	Domain  string `json:"domain,omitempty"`   // workspace subdomain, used to match permalinks
+
+	// Token rotation: the keyring key of the refresh token and when the
+	// user token expires. Both are empty for long-lived tokens.
+	RefreshKey string    `json:"refresh_key,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at,omitzero"`
 }
 
 // WorkspaceTokens holds the resolved tokens for a workspace.
 type WorkspaceTokens struct {
-	UserToken string
-	AppToken  string
+	UserToken    string
+	AppToken     string
+	RefreshToken string    // empty unless the workspace uses token rotation
+	ExpiresAt    time.Time // zero for tokens that don't expire
 }
 
 const workspacesFile = "workspaces.json"
@@ -68,11 +77,11 @@ func AddWorkspace(id, name, userToken, appToken string) error {
 	userKey := "user_" + id
 	appKey := "app_" + id
 
-	// Store tokens in keyring.
-	if err := gokeyring.Set(consts.Name, userKey, userToken); err != nil {
+	// Store tokens in the credential store.
+	if err := store.Set(userKey, userToken); err != nil {
 		return err
 	}
-	if err := gokeyring.Set(consts.Name, appKey, appToken); err != nil {
+	if err := store.Set(appKey, appToken); err != nil {
 		return err
 	}
 
@@ -99,6 +108,149 @@ func AddWorkspace(id, name, userToken, appToken string) error {
 	return saveWorkspaces(ws)
 }
 
+// SetWorkspaceRefresh stores the refresh token and expiry of a workspace
+// whose tokens rotate. An empty refresh token clears them.
+func SetWorkspaceRefresh(id, refreshToken string, expiresAt time.Time) error {
+	ws, err := ListWorkspaces()
+	if err != nil {
+		return err
+	}
+	for i, w := range ws {
+		if w.ID != id {
+			continue
+		}
+		if refreshToken == "" {
+			if w.RefreshKey != "" {
+				_ = store.Delete(w.RefreshKey)
+			}
+			ws[i].RefreshKey = ""
+			ws[i].ExpiresAt = time.Time{}
+			return saveWorkspaces(ws)
+		}
+		key := "refresh_" + id
+		if err := store.Set(key, refreshToken); err != nil {
+			return err
+		}
+		ws[i].RefreshKey = key
+		ws[i].ExpiresAt = expiresAt
+		return saveWorkspaces(ws)
+	}
+	return fmt.Errorf("workspace not found: %s", id)
+}
+
+// UpdateWorkspaceToken replaces the user token of a workspace after it was
+// refreshed. When the workspace is the default one, the default token is
+// replaced too.
+func UpdateWorkspaceToken(w Workspace, userToken string) error {
+	old, _ := GetWorkspaceTokens(w)
+	if def, err := GetUserToken(); err == nil && old.UserToken != "" && def == old.UserToken {
+		if err := SetUserToken(userToken); err != nil {
+			return err
+		}
+	}
+	if w.UserKey == "" {
+		return fmt.Errorf("workspace %s has no user token key", w.ID)
+	}
+	return store.Set(w.UserKey, userToken)
+}
+
+// SetWorkspaceDomain records the workspace subdomain (e.g. "team" for
+// team.slack.com) for a registered workspace. Unknown IDs are ignored.
+func SetWorkspaceDomain(id, domain string) error {
+	ws, err := ListWorkspaces()
+	if err != nil {
+		return err
+	}
+	for i, w := range ws {
+		if w.ID == id {
+			if w.Domain == domain {
+				return nil
+			}
+			ws[i].Domain = domain
+			return saveWorkspaces(ws)
+		}
+	}
+	return nil
+}
+
+// FindWorkspace returns the registered workspace whose ID or name matches ref
+// (names are compared case-insensitively).
+func FindWorkspace(ref string) (Workspace, bool) {
+	ws, err := ListWorkspaces()
+	if err != nil {
+		return Workspace{}, false
+	}
+	for _, w := range ws {
+		if w.ID == ref || strings.EqualFold(w.Name, ref) {
+			return w, true
+		}
+	}
+	return Workspace{}, false
+}
+
+// FindWorkspaceByDomain returns the registered workspace with the given
+// subdomain.
+func FindWorkspaceByDomain(domain string) (Workspace, bool) {
+	ws, err := ListWorkspaces()
+	if err != nil {
+		return Workspace{}, false
+	}
+	for _, w := range ws {
+		if w.Domain != "" && strings.EqualFold(w.Domain, domain) {
+			return w, true
+		}
+	}
+	return Workspace{}, false
+}
+
+// RenameWorkspace changes the display name of a registered workspace.
+func RenameWorkspace(id, name string) error {
+	ws, err := ListWorkspaces()
+	if err != nil {
+		return err
+	}
+	for i, w := range ws {
+		if w.ID == id {
+			ws[i].Name = name
+			return saveWorkspaces(ws)
+		}
+	}
+	return fmt.Errorf("workspace not found: %s", id)
+}
+
+// SetDefaultWorkspace makes w the workspace Slacko starts with by copying its
+// tokens to the default keyring entries.
+func SetDefaultWorkspace(w Workspace) error {
+	tokens, err := GetWorkspaceTokens(w)
+	if err != nil {
+		return err
+	}
+	if err := SetUserToken(tokens.UserToken); err != nil {
+		return err
+	}
+	return SetAppToken(tokens.AppToken)
+}
+
+// DefaultWorkspaceID returns the ID of the registered workspace whose user
+// token matches the default keyring entry, or "" if none does.
+func DefaultWorkspaceID() string {
+	user, err := GetUserToken()
+	if err != nil {
+		return ""
+	}
+	ws, err := ListWorkspaces()
+	if err != nil {
+		return ""
+	}
+	for _, w := range ws {
+		tokens, err := GetWorkspaceTokens(w)
+		if err == nil && tokens.UserToken == user {
+			return w.ID
+		}
+	}
+	return ""
+}
+
 // RemoveWorkspace removes a workspace from the registry and deletes its tokens.
 func RemoveWorkspace(id string) error {
 	ws, err := ListWorkspaces()
@@ -110,12 +262,15 @@ func RemoveWorkspace(id string) error {
 	for _, w := range ws {
 		if w.ID == id {
 			if w.UserKey != "" {
-				_ = gokeyring.Delete(consts.Name, w.UserKey)
+				_ = store.Delete(w.UserKey)
 			}
 			if w.BotKey != "" {
-				_ = gokeyring.Delete(consts.Name, w.BotKey)
+				_ = store.Delete(w.BotKey)
+			}
+			if w.RefreshKey != "" {
+				_ = store.Delete(w.RefreshKey)
 			}
-			_ = gokeyring.Delete(consts.Name, w.AppKey)
+			_ = store.Delete(w.AppKey)
 			continue
 		}
 		updated = append(updated, w)
@@ -124,30 +279,36 @@ func RemoveWorkspace(id string) error {
 	return saveWorkspaces(updated)
 }
 
-// GetWorkspaceTokens retrieves the tokens for a workspace from the keyring.
+// GetWorkspaceTokens retrieves the tokens for a workspace from the credential store.
 // It falls back to the legacy BotKey if UserKey is not set.
 func GetWorkspaceTokens(w Workspace) (WorkspaceTokens, error) {
 	var user string
 	var err error
 
 	if w.UserKey != "" {
-		user, err = gokeyring.Get(consts.Name, w.UserKey)
+		user, err = store.Get(w.UserKey)
 	}
 	// Fallback to legacy BotKey if UserKey is empty or failed.
 	if w.UserKey == "" || err != nil {
 		if w.BotKey != "" {
-			user, err = gokeyring.Get(consts.Name, w.BotKey)
+			user, err = store.Get(w.BotKey)
 		}
 	}
 	if err != nil {
 		return WorkspaceTokens{}, err
 	}
 
-	app, err := gokeyring.Get(consts.Name, w.AppKey)
+	app, err := store.Get(w.AppKey)
 	if err != nil {
 		return WorkspaceTokens{}, err
 	}
-	return WorkspaceTokens{UserToken: user, AppToken: app}, nil
+	tokens := WorkspaceTokens{UserToken: user, AppToken: app, ExpiresAt: w.ExpiresAt}
+	if w.RefreshKey != "" {
+		if tokens.RefreshToken, err = store.Get(w.RefreshKey); err != nil {
+			return WorkspaceTokens{}, err
+		}
+	}
+	return tokens, nil
 }
 
 // MigrateDefaultWorkspace migrates the legacy single-workspace tokens
@@ -0,0 +1,193 @@
+package keyring
+
+import (
+	"testing"
+	"time"
+
+	gokeyring "github.com/zalando/go-keyring"
+
+	"github.com/m96-chan/Slacko/internal/consts"
+)
+
+// useTempRegistry points the workspace registry at a temporary directory
+// and installs the mock keyring for the duration of the test.
+func useTempRegistry(t *testing.T) {
+	t.Helper()
+	gokeyring.MockInit()
+	orig := consts.CacheDir
+	consts.CacheDir = t.TempDir()
+	t.Cleanup(func() { consts.CacheDir = orig })
+}
+
+func TestAddAndListWorkspaces(t *testing.T) {
+	useTempRegistry(t)
+
+	if err := AddWorkspace("T1", "Team One", "xoxp-1", "xapp-1"); err != nil {
+		t.Fatalf("AddWorkspace: %v", err)
+	}
+	ws, err := ListWorkspaces()
+	if err != nil {
+		t.Fatalf("ListWorkspaces: %v", err)
+	}
+	if len(ws) != 1 || ws[0].ID != "T1" || ws[0].Name != "Team One" {
+		t.Fatalf("ListWorkspaces = %+v", ws)
+	}
+
+	tokens, err := GetWorkspaceTokens(ws[0])
+	if err != nil {
+		t.Fatalf("GetWorkspaceTokens: %v", err)
+	}
+	if tokens.UserToken != "xoxp-1" || tokens.AppToken != "xapp-1" {
+		t.Errorf("tokens = %+v", tokens)
+	}
+}
+
+func TestSetWorkspaceDomainAndFind(t *testing.T) {
+	useTempRegistry(t)
+
+	if err := AddWorkspace("T1", "Team One", "xoxp-1", "xapp-1"); err != nil {
+		t.Fatalf("AddWorkspace: %v", err)
+	}
+	if _, ok := FindWorkspaceByDomain("teamone"); ok {
+		t.Fatal("found workspace before domain was recorded")
+	}
+
+	if err := SetWorkspaceDomain("T1", "teamone"); err != nil {
+		t.Fatalf("SetWorkspaceDomain: %v", err)
+	}
+	w, ok := FindWorkspaceByDomain("TeamOne")
+	if !ok || w.ID != "T1" {
+		t.Errorf("FindWorkspaceByDomain = %+v, %v", w, ok)
+	}
+
+	// Re-adding keeps the recorded domain.
+	if err := AddWorkspace("T1", "Team 1", "xoxp-2", "xapp-2"); err != nil {
+		t.Fatalf("AddWorkspace: %v", err)
+	}
+	if w, ok := FindWorkspaceByDomain("teamone"); !ok || w.Name != "Team 1" {
+		t.Errorf("after re-add FindWorkspaceByDomain = %+v, %v", w, ok)
+	}
+}
+
+func TestRemoveWorkspace(t *testing.T) {
+	useTempRegistry(t)
+
+	_ = AddWorkspace("T1", "One", "xoxp-1", "xapp-1")
+	_ = AddWorkspace("T2", "Two", "xoxp-2", "xapp-2")
+
+	if err := RemoveWorkspace("T1"); err != nil {
+		t.Fatalf("RemoveWorkspace: %v", err)
+	}
+	ws, _ := ListWorkspaces()
+	if len(ws) != 1 || ws[0].ID != "T2" {
+		t.Errorf("ListWorkspaces = %+v", ws)
+	}
+	if _, err := gokeyring.Get(consts.Name, "user_T1"); err == nil {
+		t.Error("user token for removed workspace still in keyring")
+	}
+}
+
+func TestFindWorkspace(t *testing.T) {
+	useTempRegistry(t)
+
+	if err := AddWorkspace("T1", "Team One", "xoxp-1", "xapp-1"); err != nil {
+		t.Fatalf("AddWorkspace: %v", err)
+	}
+	for _, ref := range []string{"T1", "team one", "Team One"} {
+		if w, ok := FindWorkspace(ref); !ok || w.ID != "T1" {
+			t.Errorf("FindWorkspace(%q) = %+v, %v", ref, w, ok)
+		}
+	}
+	if _, ok := FindWorkspace("T2"); ok {
+		t.Error("FindWorkspace(T2) should not match")
+	}
+}
+
+func TestRenameWorkspace(t *testing.T) {
+	useTempRegistry(t)
+
+	if err := AddWorkspace("T1", "Team One", "xoxp-1", "xapp-1"); err != nil {
+		t.Fatalf("AddWorkspace: %v", err)
+	}
+	if err := RenameWorkspace("T1", "Work"); err != nil {
+		t.Fatalf("RenameWorkspace: %v", err)
+	}
+	if w, ok := FindWorkspace("work"); !ok || w.ID != "T1" {
+		t.Errorf("FindWorkspace(work) = %+v, %v", w, ok)
+	}
+	if err := RenameWorkspace("T9", "x"); err == nil {
+		t.Error("renaming an unknown workspace should fail")
+	}
+}
+
+func TestSetDefaultWorkspace(t *testing.T) {
+	useTempRegistry(t)
+	t.Setenv("SLACKO_USER_TOKEN", "")
+	t.Setenv("SLACKO_BOT_TOKEN", "")
+	t.Setenv("SLACKO_APP_TOKEN", "")
+
+	if err := AddWorkspace("T1", "One", "xoxp-1", "xapp-1"); err != nil {
+		t.Fatalf("AddWorkspace: %v", err)
+	}
+	if err := AddWorkspace("T2", "Two", "xoxp-2", "xapp-2"); err != nil {
+		t.Fatalf("AddWorkspace: %v", err)
+	}
+	if id := DefaultWorkspaceID(); id != "" {
+		t.Errorf("DefaultWorkspaceID before setting = %q, want empty", id)
+	}
+
+	w, _ := FindWorkspace("T2")
+	if err := SetDefaultWorkspace(w); err != nil {
+		t.Fatalf("SetDefaultWorkspace: %v", err)
+	}
+	if id := DefaultWorkspaceID(); id != "T2" {
+		t.Errorf("DefaultWorkspaceID = %q, want T2", id)
+	}
+	if app, _ := GetAppToken(); app != "xapp-2" {
+		t.Errorf("default app token = %q, want xapp-2", app)
+	}
+}
+
+func TestWorkspaceRefresh(t *testing.T) {
+	useTempRegistry(t)
+	t.Setenv("SLACKO_USER_TOKEN", "")
+	t.Setenv("SLACKO_BOT_TOKEN", "")
+	if err := AddWorkspace("T1", "One", "xoxp-old", "xapp-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetUserToken("xoxp-old"); err != nil {
+		t.Fatal(err)
+	}
+
+	expires := time.Now().Add(12 * time.Hour).Truncate(time.Second)
+	if err := SetWorkspaceRefresh("T1", "xoxe-refresh", expires); err != nil {
+		t.Fatalf("SetWorkspaceRefresh: %v", err)
+	}
+	w, _ := FindWorkspace("T1")
+	if err := UpdateWorkspaceToken(w, "xoxp-new"); err != nil {
+		t.Fatalf("UpdateWorkspaceToken: %v", err)
+	}
+
+	tokens, err := GetWorkspaceTokens(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens.UserToken != "xoxp-new" || tokens.RefreshToken != "xoxe-refresh" || !tokens.ExpiresAt.Equal(expires) {
+		t.Errorf("tokens = %+v", tokens)
+	}
+	if def, _ := GetUserToken(); def != "xoxp-new" {
+		t.Errorf("default token = %q, want xoxp-new", def)
+	}
+
+	// Clearing removes the refresh token again.
+	if err := SetWorkspaceRefresh("T1", "", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	w, _ = FindWorkspace("T1")
+	if w.RefreshKey != "" || !w.ExpiresAt.IsZero() {
+		t.Errorf("after clearing = %+v", w)
+	}
+	if err := SetWorkspaceRefresh("T9", "x", expires); err == nil {
+		t.Error("unknown workspace should fail")
+	}
+}
@@ -399,6 +399,22 @@ func resolveSlackTokens(text string, users map[string]slack.User, channels map[s
 	})
 }
 
+// ResolveMentions replaces user, channel and special mention tokens
+// (<@U…>, <#C…>, <!here>) with plain @name / #name text using the same
+// lookups as Render. Link tokens (<url|label>) are left untouched so callers
+// can format them for their own output (e.g. Markdown or HTML export).
+func ResolveMentions(text string, users map[string]slack.User, channels map[string]string) string {
+	return slackTokenRe.ReplaceAllStringFunc(text, func(match string) string {
+		inner := match[1 : len(match)-1]
+		switch {
+		case strings.HasPrefix(inner, "@"), strings.HasPrefix(inner, "#"), strings.HasPrefix(inner, "!"):
+			return resolveSlackTokens(match, users, channels, false)
+		default:
+			return match
+		}
+	})
+}
+
 // resolveUserMentionPlain returns @displayname for a user mention token.
 func resolveUserMentionPlain(token string, users map[string]slack.User) string {
 	parts := strings.SplitN(token, "|", 2)
@@ -356,3 +356,11 @@ func TestRender_UserMention_FallbackToName(t *testing.T) {
 		t.Errorf("mention fallback to Name: got %q, want %q", got, want)
 	}
 }
+
+func TestResolveMentions(t *testing.T) {
+	got := ResolveMentions("hi <@U1> in <#C2>, <!here>: <https://example.com|docs>", testUsers, testChannels)
+	want := "hi @Alice in #random, @here: <https://example.com|docs>"
+	if got != want {
+		t.Errorf("ResolveMentions() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package oauth
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// completionPrefix starts the code the proxy shows in manual mode. The rest
+// is the base64url-encoded form it would otherwise post to localhost.
+const completionPrefix = "slacko:"
+
+// Manual is an OAuth flow without a local callback server, for when the
+// browser runs on another machine (e.g. Slacko over SSH). The user opens
+// AuthURL anywhere and pastes back what the browser ends up with: in direct
+// mode the address of the failed redirect to localhost, in proxy mode the
+// completion code the Worker shows. Both carry the state, which Complete
+// verifies.
+type Manual struct {
+	AuthURL string
+
+	p           Params
+	state       string
+	verifier    string // PKCE, for public clients
+	redirectURI string
+}
+
+// StartManual begins a manual flow.
+func StartManual(p Params) (*Manual, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	state, err := generateState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate state: %w", err)
+	}
+
+	verifier, err := p.newVerifier()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+
+	m := &Manual{p: p, state: state, verifier: verifier}
+	if p.ProxyURL != "" {
+		// Port 0 tells the Worker to show a completion code instead of
+		// posting the result to localhost.
+		m.AuthURL = buildProxyAuthorizeURL(p.ProxyURL, 0, state)
+	} else {
+		m.redirectURI = "http://localhost/callback"
+		if p.Port != 0 {
+			m.redirectURI = fmt.Sprintf("http://localhost:%d/callback", p.Port)
+		}
+		m.AuthURL = p.directAuthURL(m.redirectURI, state, verifier)
+	}
+	return m, nil
+}
+
+// Prompt returns what the user should paste back, for display.
+func (m *Manual) Prompt() string {
+	if m.p.ProxyURL != "" {
+		return "the code shown after authorizing"
+	}
+	return "the full address of the page the browser is redirected to (it will fail to load)"
+}
+
+// Complete finishes the flow with the pasted redirect URL, its query string,
+// or the proxy's completion code.
+func (m *Manual) Complete(ctx context.Context, input string) (*Result, error) {
+	values, err := parsePasted(input)
+	if err != nil {
+		return nil, err
+	}
+	if errMsg := values.Get("error"); errMsg != "" {
+		return nil, fmt.Errorf("slack denied authorization: %s", errMsg)
+	}
+	if values.Get("state") != m.state {
+		return nil, errStateMismatch
+	}
+
+	if m.p.ProxyURL != "" {
+		return proxyResult(values)
+	}
+	code := values.Get("code")
+	if code == "" {
+		return nil, fmt.Errorf("no code in pasted address")
+	}
+	return m.p.exchangeCode(ctx, code, m.redirectURI, m.verifier)
+}
+
+// parsePasted extracts the callback parameters from a pasted redirect URL,
+// query string or completion code. A bare code is rejected because it can't
+// be checked against the state.
+func parsePasted(input string) (url.Values, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, errors.New("nothing pasted")
+	}
+
+	if rest, ok := strings.CutPrefix(input, completionPrefix); ok {
+		data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(rest, "="))
+		if err != nil {
+			return nil, fmt.Errorf("invalid completion code: %w", err)
+		}
+		return url.ParseQuery(string(data))
+	}
+
+	query := input
+	if u, err := url.Parse(input); err == nil && u.RawQuery != "" {
+		query = u.RawQuery
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil || !values.Has("state") {
+		return nil, errors.New("paste the whole address, including its state parameter")
+	}
+	return values, nil
+}
+
+// RunManual runs a manual flow on a terminal: it writes the authorize URL to
+// out and reads the pasted answer from in.
+func RunManual(ctx context.Context, p Params, in io.Reader, out io.Writer) (*Result, error) {
+	m, err := StartManual(p)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(out, "Open this URL in a browser on any device and authorize Slacko:\n\n%s\n\n", m.AuthURL)
+	fmt.Fprintf(out, "Then paste %s:\n> ", m.Prompt())
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && line == "" {
+		return nil, fmt.Errorf("reading pasted answer: %w", err)
+	}
+	return m.Complete(ctx, line)
+}
@@ -0,0 +1,164 @@
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManualDirect(t *testing.T) {
+	srv, got := newTokenServer(t, map[string]any{
+		"ok":          true,
+		"team":        map[string]any{"id": "T1", "name": "One"},
+		"authed_user": map[string]any{"id": "U1", "access_token": "xoxp-1"},
+	})
+	useTokenURL(t, srv.URL)
+
+	m, err := StartManual(Params{ClientID: "id", ClientSecret: "secret", Port: 8765})
+	if err != nil {
+		t.Fatalf("StartManual: %v", err)
+	}
+	u, _ := url.Parse(m.AuthURL)
+	if u.Query().Get("redirect_uri") != "http://localhost:8765/callback" {
+		t.Errorf("redirect_uri = %q", u.Query().Get("redirect_uri"))
+	}
+	state := u.Query().Get("state")
+
+	res, err := m.Complete(context.Background(), "  http://localhost:8765/callback?code=the-code&state="+state+"\n")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if res.UserToken != "xoxp-1" {
+		t.Errorf("UserToken = %q", res.UserToken)
+	}
+	if got.PostForm.Get("code") != "the-code" || got.PostForm.Get("redirect_uri") != "http://localhost:8765/callback" {
+		t.Errorf("exchange form = %v", got.PostForm)
+	}
+}
+
+func TestManualRejectsWrongState(t *testing.T) {
+	m, err := StartManual(Params{ClientID: "id", ClientSecret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, input := range []string{
+		"http://localhost/callback?code=c&state=forged",
+		"code=c&state=forged",
+	} {
+		if _, err := m.Complete(context.Background(), input); !errors.Is(err, errStateMismatch) {
+			t.Errorf("Complete(%q) = %v, want state mismatch", input, err)
+		}
+	}
+	if _, err := m.Complete(context.Background(), "just-a-code"); err == nil || !strings.Contains(err.Error(), "state") {
+		t.Errorf("bare code = %v, want an error asking for the state", err)
+	}
+	if _, err := m.Complete(context.Background(), "http://localhost/callback?error=access_denied&state=x"); err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("denied = %v", err)
+	}
+}
+
+func TestManualProxy(t *testing.T) {
+	m, err := StartManual(Params{ProxyURL: "https://proxy.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(m.AuthURL)
+	if u.Query().Get("port") != "0" {
+		t.Errorf("port = %q, want 0 for manual mode", u.Query().Get("port"))
+	}
+
+	encode := func(state string) string {
+		form := url.Values{
+			"token":     {"xoxp-1"},
+			"app_token": {"xapp-1"},
+			"team_id":   {"T1"},
+			"state":     {state},
+		}
+		return completionPrefix + base64.RawURLEncoding.EncodeToString([]byte(form.Encode()))
+	}
+
+	res, err := m.Complete(context.Background(), encode(u.Query().Get("state")))
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if res.UserToken != "xoxp-1" || res.AppToken != "xapp-1" || res.TeamID != "T1" {
+		t.Errorf("result = %+v", res)
+	}
+	if _, err := m.Complete(context.Background(), encode("forged")); !errors.Is(err, errStateMismatch) {
+		t.Errorf("forged state = %v, want state mismatch", err)
+	}
+}
+
+func TestRunManualPrintsURL(t *testing.T) {
+	var out bytes.Buffer
+	in := strings.NewReader("http://localhost/callback?code=c&state=forged\n")
+	_, err := RunManual(context.Background(), Params{ClientID: "id", ClientSecret: "secret"}, in, &out)
+	if !errors.Is(err, errStateMismatch) {
+		t.Errorf("err = %v, want state mismatch", err)
+	}
+	if !strings.Contains(out.String(), "https://slack.com/oauth/v2/authorize?") {
+		t.Errorf("authorize URL not printed:\n%s", out.String())
+	}
+}
+
+func TestRunFixedPort(t *testing.T) {
+	srv, _ := newTokenServer(t, map[string]any{
+		"ok":          true,
+		"authed_user": map[string]any{"id": "U1", "access_token": "xoxp-1"},
+	})
+	useTokenURL(t, srv.URL)
+
+	// Find a free port for the callback server.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	opened := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() {
+		res, err := Run(context.Background(), Params{
+			ClientID:     "id",
+			ClientSecret: "secret",
+			Port:         port,
+			OpenBrowser:  func(u string) error { opened <- u; return nil },
+		})
+		if err == nil && res.UserToken != "xoxp-1" {
+			err = errors.New("unexpected token " + res.UserToken)
+		}
+		done <- err
+	}()
+
+	var authURL string
+	select {
+	case authURL = <-opened:
+	case <-time.After(5 * time.Second):
+		t.Fatal("browser not opened")
+	}
+	q, _ := url.Parse(authURL)
+	redirect := q.Query().Get("redirect_uri")
+	if want := "http://localhost:" + strings.TrimPrefix(ln.Addr().String(), "127.0.0.1:") + "/callback"; redirect != want {
+		t.Fatalf("redirect_uri = %q, want %q", redirect, want)
+	}
+
+	callback := "http://" + ln.Addr().String() + "/callback?code=c&state=" + q.Query().Get("state")
+	resp, err := http.Get(callback)
+	if err != nil {
+		t.Fatalf("callback: %v", err)
+	}
+	resp.Body.Close()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -12,8 +13,6 @@ import (
 	"runtime"
 	"strings"
 	"time"
-
-	"github.com/slack-go/slack"
 )
 
 // UserScopes is the set of OAuth user scopes required by Slacko.
@@ -26,7 +25,7 @@ const UserScopes = "bookmarks:read," +
 	"pins:read,pins:write,reactions:read,reactions:write," +
 	"search:read,stars:read,stars:write," +
 	"team:read,users:read,users:read.email,users.profile:read,users.profile:write," +
-	"reminders:read,reminders:write"
+	"reminders:read,reminders:write,usergroups:read"
 
 // Result holds the tokens and identity returned by the OAuth flow.
 type Result struct {
@@ -35,13 +34,19 @@ type Result struct {
 	TeamID    string
 	TeamName  string
 	UserID    string
+
+	// Set when the workspace uses token rotation: the token that renews
+	// UserToken, and when UserToken expires.
+	RefreshToken string
+	ExpiresAt    time.Time
 }
 
 // Params configures the OAuth flow.
 type Params struct {
 	ClientID     string
-	ClientSecret string // direct exchange (self-hosted mode)
+	ClientSecret string // direct exchange (self-hosted mode); empty uses PKCE
 	ProxyURL     string // Cloudflare Worker URL (public distribution mode)
+	Port         int    // fixed callback port, e.g. for SSH port forwarding; 0 picks a free one
 	OpenBrowser  func(string) error
 }
 
@@ -56,11 +61,12 @@ type flowResult struct {
 // Proxy mode (ProxyURL set): Browser → Worker/authorize → Slack → Worker/callback
 // (exchanges token) → form POST to localhost/done.
 //
-// Direct mode (ClientSecret set): Browser → Slack → localhost/callback → direct
-// token exchange with Slack API.
+// Direct mode (ProxyURL empty): Browser → Slack → localhost/callback → direct
+// token exchange with Slack API, authenticated with ClientSecret or, for
+// public clients without one, with PKCE.
 func Run(ctx context.Context, p Params) (*Result, error) {
-	if p.ClientSecret == "" && p.ProxyURL == "" {
-		return nil, fmt.Errorf("either client_secret (self-hosted) or proxy_url (public) must be configured")
+	if err := p.validate(); err != nil {
+		return nil, err
 	}
 
 	openBrowser := p.OpenBrowser
@@ -68,8 +74,8 @@ func Run(ctx context.Context, p Params) (*Result, error) {
 		openBrowser = defaultOpenBrowser
 	}
 
-	// Listen on a random port.
-	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	// Listen on the configured port, or a random one.
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", p.Port))
 	if err != nil {
 		return nil, fmt.Errorf("failed to listen: %w", err)
 	}
@@ -82,14 +88,21 @@ func Run(ctx context.Context, p Params) (*Result, error) {
 		return nil, fmt.Errorf("failed to generate state: %w", err)
 	}
 
+	verifier, err := p.newVerifier()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+
 	ch := make(chan flowResult, 1)
 	mux := http.NewServeMux()
 
+	redirectURI := fmt.Sprintf("http://localhost:%d/callback", port)
 	if p.ProxyURL != "" {
 		mux.HandleFunc("/done", handleProxyDone(ch, state))
 	} else {
-		redirectURI := fmt.Sprintf("http://localhost:%d/callback", port)
-		mux.HandleFunc("/callback", handleDirectCallback(ch, state, p.ClientID, p.ClientSecret, redirectURI))
+		mux.HandleFunc("/callback", handleCallback(ch, state, func(ctx context.Context, code string) (*Result, error) {
+			return p.exchangeCode(ctx, code, redirectURI, verifier)
+		}))
 	}
 
 	server := &http.Server{Handler: mux}
@@ -107,8 +120,7 @@ func Run(ctx context.Context, p Params) (*Result, error) {
 	if p.ProxyURL != "" {
 		authURL = buildProxyAuthorizeURL(p.ProxyURL, port, state)
 	} else {
-		redirectURI := fmt.Sprintf("http://localhost:%d/callback", port)
-		authURL = buildAuthURL(p.ClientID, redirectURI, state)
+		authURL = p.directAuthURL(redirectURI, state, verifier)
 	}
 
 	if err := openBrowser(authURL); err != nil {
@@ -126,6 +138,41 @@ func Run(ctx context.Context, p Params) (*Result, error) {
 	}
 }
 
+// validate checks that the parameters select a mode.
+func (p Params) validate() error {
+	if p.ClientID == "" && p.ProxyURL == "" {
+		return fmt.Errorf("either client_id (self-hosted) or proxy_url (public) must be configured")
+	}
+	return nil
+}
+
+// newVerifier returns a PKCE code verifier when the direct mode runs as a
+// public client, i.e. without a client secret, and "" otherwise.
+func (p Params) newVerifier() (string, error) {
+	if p.ProxyURL != "" || p.ClientSecret != "" {
+		return "", nil
+	}
+	return generateVerifier()
+}
+
+// directAuthURL builds the authorize URL of the direct mode, with a PKCE
+// challenge when verifier is set.
+func (p Params) directAuthURL(redirectURI, state, verifier string) string {
+	if verifier != "" {
+		return buildPKCEAuthURL(p.ClientID, redirectURI, state, codeChallenge(verifier))
+	}
+	return buildAuthURL(p.ClientID, redirectURI, state)
+}
+
+// exchangeCode redeems a code of the direct mode, with the PKCE verifier
+// when set and with the client secret otherwise.
+func (p Params) exchangeCode(ctx context.Context, code, redirectURI, verifier string) (*Result, error) {
+	if verifier != "" {
+		return exchangeCodePKCE(ctx, p.ClientID, verifier, code, redirectURI)
+	}
+	return exchangeCodeDirect(ctx, p.ClientID, p.ClientSecret, code, redirectURI)
+}
+
 // handleProxyDone returns an HTTP handler for POST /done, which receives
 // the token and identity from the Worker's auto-submitted form.
 func handleProxyDone(ch chan<- flowResult, expectedState string) http.HandlerFunc {
@@ -142,33 +189,55 @@ func handleProxyDone(ch chan<- flowResult, expectedState string) http.HandlerFun
 		}
 
 		if r.FormValue("state") != expectedState {
-			ch <- flowResult{err: fmt.Errorf("state mismatch (possible CSRF)")}
+			ch <- flowResult{err: errStateMismatch}
 			http.Error(w, "state mismatch", http.StatusBadRequest)
 			return
 		}
 
-		token := r.FormValue("token")
-		if token == "" {
-			ch <- flowResult{err: fmt.Errorf("no token in callback")}
+		result, err := proxyResult(r.Form)
+		if err != nil {
+			ch <- flowResult{err: err}
 			http.Error(w, "missing token", http.StatusBadRequest)
 			return
 		}
-
-		ch <- flowResult{result: &Result{
-			UserToken: token,
-			AppToken:  r.FormValue("app_token"),
-			TeamID:    r.FormValue("team_id"),
-			TeamName:  r.FormValue("team_name"),
-			UserID:    r.FormValue("user_id"),
-		}}
+		ch <- flowResult{result: result}
 
 		fmt.Fprintf(w, "<html><body><h2>Authorization successful!</h2><p>You can close this window and return to Slacko.</p></body></html>")
 	}
 }
 
+// errStateMismatch is returned when the state of a callback is not the one
+// the flow was started with.
+var errStateMismatch = errors.New("state mismatch (possible CSRF)")
+
+// proxyResult builds the result from the fields the Worker sends.
+func proxyResult(form url.Values) (*Result, error) {
+	token := form.Get("token")
+	if token == "" {
+		return nil, fmt.Errorf("no token in callback")
+	}
+	return &Result{
+		UserToken:    token,
+		AppToken:     form.Get("app_token"),
+		TeamID:       form.Get("team_id"),
+		TeamName:     form.Get("team_name"),
+		UserID:       form.Get("user_id"),
+		RefreshToken: form.Get("refresh_token"),
+		ExpiresAt:    expiresAt(form.Get("expires_in")),
+	}, nil
+}
+
 // handleDirectCallback returns an HTTP handler for GET /callback (direct mode),
 // which receives the code from Slack and exchanges it locally.
 func handleDirectCallback(ch chan<- flowResult, expectedState, clientID, clientSecret, redirectURI string) http.HandlerFunc {
+	return handleCallback(ch, expectedState, func(ctx context.Context, code string) (*Result, error) {
+		return exchangeCodeDirect(ctx, clientID, clientSecret, code, redirectURI)
+	})
+}
+
+// handleCallback returns an HTTP handler for GET /callback that checks the
+// state and redeems the code with exchange.
+func handleCallback(ch chan<- flowResult, expectedState string, exchange func(ctx context.Context, code string) (*Result, error)) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if errMsg := r.URL.Query().Get("error"); errMsg != "" {
 			ch <- flowResult{err: fmt.Errorf("slack denied authorization: %s", errMsg)}
@@ -177,7 +246,7 @@ func handleDirectCallback(ch chan<- flowResult, expectedState, clientID, clientS
 		}
 
 		if r.URL.Query().Get("state") != expectedState {
-			ch <- flowResult{err: fmt.Errorf("state mismatch (possible CSRF)")}
+			ch <- flowResult{err: errStateMismatch}
 			http.Error(w, "state mismatch", http.StatusBadRequest)
 			return
 		}
@@ -189,7 +258,7 @@ func handleDirectCallback(ch chan<- flowResult, expectedState, clientID, clientS
 			return
 		}
 
-		result, err := exchangeCodeDirect(clientID, clientSecret, code, redirectURI)
+		result, err := exchange(r.Context(), code)
 		ch <- flowResult{result: result, err: err}
 
 		if err != nil {
@@ -232,23 +301,13 @@ func buildProxyAuthorizeURL(proxyURL string, port int, state string) string {
 
 // exchangeCodeDirect exchanges the authorization code for an access token
 // directly with the Slack API (self-hosted mode with client_secret).
-func exchangeCodeDirect(clientID, clientSecret, code, redirectURI string) (*Result, error) {
-	resp, err := slack.GetOAuthV2Response(http.DefaultClient, clientID, clientSecret, code, redirectURI)
-	if err != nil {
-		return nil, fmt.Errorf("token exchange failed: %w", err)
-	}
-
-	userToken := resp.AuthedUser.AccessToken
-	if userToken == "" {
-		return nil, fmt.Errorf("no user token in OAuth response")
-	}
-
-	return &Result{
-		UserToken: userToken,
-		TeamID:    resp.Team.ID,
-		TeamName:  resp.Team.Name,
-		UserID:    resp.AuthedUser.ID,
-	}, nil
+func exchangeCodeDirect(ctx context.Context, clientID, clientSecret, code, redirectURI string) (*Result, error) {
+	return requestToken(ctx, tokenURL, url.Values{
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+	})
 }
 
 // defaultOpenBrowser opens a URL in the system's default browser.
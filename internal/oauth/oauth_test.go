@@ -129,6 +129,32 @@ func TestHandleProxyDone(t *testing.T) {
 	}
 }
 
+func TestHandleProxyDoneRotation(t *testing.T) {
+	ch := make(chan flowResult, 1)
+	handler := handleProxyDone(ch, "state")
+
+	form := url.Values{
+		"token":         {"xoxe.xoxp-token"},
+		"refresh_token": {"xoxe-1-refresh"},
+		"expires_in":    {"43200"},
+		"state":         {"state"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/done", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	handler(httptest.NewRecorder(), req)
+
+	res := <-ch
+	if res.err != nil {
+		t.Fatalf("unexpected error: %v", res.err)
+	}
+	if res.result.RefreshToken != "xoxe-1-refresh" {
+		t.Errorf("RefreshToken = %q", res.result.RefreshToken)
+	}
+	if res.result.ExpiresAt.IsZero() {
+		t.Error("ExpiresAt not set")
+	}
+}
+
 func TestHandleProxyDoneStateMismatch(t *testing.T) {
 	ch := make(chan flowResult, 1)
 	handler := handleProxyDone(ch, "correct-state")
@@ -230,16 +256,15 @@ func TestRunTimeout(t *testing.T) {
 	}
 }
 
-func TestRunRequiresSecretOrProxy(t *testing.T) {
+func TestRunRequiresClientIDOrProxy(t *testing.T) {
 	ctx := context.Background()
 	_, err := Run(ctx, Params{
-		ClientID:    "test-id",
 		OpenBrowser: func(string) error { return nil },
 	})
 	if err == nil {
-		t.Fatal("expected error when neither secret nor proxy is set")
+		t.Fatal("expected error when neither client ID nor proxy is set")
 	}
-	if !strings.Contains(err.Error(), "client_secret") {
-		t.Errorf("expected error about client_secret/proxy_url, got: %v", err)
+	if !strings.Contains(err.Error(), "client_id") {
+		t.Errorf("expected error about client_id/proxy_url, got: %v", err)
 	}
 }
@@ -0,0 +1,54 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+)
+
+// PKCE (RFC 7636) lets a public client — a Slack app without a client
+// secret — redeem a code: the authorize request carries the hash of a
+// random verifier, and only the holder of the verifier can exchange the
+// code Slack returns.
+
+// generateVerifier creates a random PKCE code verifier of 43 characters.
+func generateVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// codeChallenge returns the S256 challenge for verifier.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// buildPKCEAuthURL constructs the Slack OAuth v2 authorization URL for a
+// public client (direct mode without client_secret).
+func buildPKCEAuthURL(clientID, redirectURI, state, challenge string) string {
+	params := url.Values{
+		"client_id":             {clientID},
+		"user_scope":            {UserScopes},
+		"redirect_uri":          {redirectURI},
+		"state":                 {state},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+	return "https://slack.com/oauth/v2/authorize?" + params.Encode()
+}
+
+// exchangeCodePKCE exchanges the authorization code for an access token
+// with the verifier instead of a client secret.
+func exchangeCodePKCE(ctx context.Context, clientID, verifier, code, redirectURI string) (*Result, error) {
+	return requestToken(ctx, tokenURL, url.Values{
+		"client_id":     {clientID},
+		"code_verifier": {verifier},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+	})
+}
@@ -0,0 +1,152 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m96-chan/Slacko/internal/keyring"
+)
+
+// tokenURL is Slack's oauth.v2.access endpoint. Tests point it at a local
+// server.
+var tokenURL = "https://slack.com/api/oauth.v2.access"
+
+// RefreshMargin is how long before it expires a rotating token is renewed.
+const RefreshMargin = 10 * time.Minute
+
+// tokenResponse is the oauth.v2.access response, or the proxy's copy of it.
+// User tokens come in authed_user for a code exchange and at the top level
+// for a refresh.
+type tokenResponse struct {
+	OK           bool   `json:"ok"`
+	Error        string `json:"error"`
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Team         struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"team"`
+	AuthedUser struct {
+		ID           string `json:"id"`
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	} `json:"authed_user"`
+}
+
+// requestToken posts values to a token endpoint and returns the user token
+// from the response.
+func requestToken(ctx context.Context, endpoint string, values url.Values) (*Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token exchange failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tr tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, fmt.Errorf("token exchange failed: %s: %w", resp.Status, err)
+	}
+	if !tr.OK {
+		if tr.Error == "" {
+			tr.Error = resp.Status
+		}
+		return nil, fmt.Errorf("token exchange failed: %s", tr.Error)
+	}
+
+	result := &Result{
+		UserToken:    tr.AuthedUser.AccessToken,
+		TeamID:       tr.Team.ID,
+		TeamName:     tr.Team.Name,
+		UserID:       tr.AuthedUser.ID,
+		RefreshToken: tr.AuthedUser.RefreshToken,
+		ExpiresAt:    expiresAt(strconv.Itoa(tr.AuthedUser.ExpiresIn)),
+	}
+	if result.UserToken == "" && tr.TokenType == "user" {
+		result.UserToken = tr.AccessToken
+		result.RefreshToken = tr.RefreshToken
+		result.ExpiresAt = expiresAt(strconv.Itoa(tr.ExpiresIn))
+	}
+	if result.UserToken == "" {
+		return nil, fmt.Errorf("no user token in OAuth response")
+	}
+	return result, nil
+}
+
+// expiresAt converts an expires_in value in seconds to a time. Missing or
+// zero values mean the token doesn't expire.
+func expiresAt(expiresIn string) time.Time {
+	secs, err := strconv.Atoi(expiresIn)
+	if err != nil || secs <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(secs) * time.Second)
+}
+
+// Refresh exchanges a refresh token for a new user token. In proxy mode the
+// Worker's /refresh endpoint adds the client secret; in direct mode
+// ClientSecret is sent to Slack, or only the client ID for a public client.
+func Refresh(ctx context.Context, p Params, refreshToken string) (*Result, error) {
+	values := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	}
+	switch {
+	case p.ProxyURL != "":
+		return requestToken(ctx, strings.TrimRight(p.ProxyURL, "/")+"/refresh", values)
+	case p.ClientSecret != "":
+		values.Set("client_id", p.ClientID)
+		values.Set("client_secret", p.ClientSecret)
+		return requestToken(ctx, tokenURL, values)
+	case p.ClientID != "":
+		values.Set("client_id", p.ClientID)
+		return requestToken(ctx, tokenURL, values)
+	default:
+		return nil, fmt.Errorf("either client_id (self-hosted) or proxy_url (public) must be configured to refresh tokens")
+	}
+}
+
+// FreshTokens returns the stored tokens of w. When the user token rotates
+// and expires within RefreshMargin it is refreshed first, and the new tokens
+// are stored.
+func FreshTokens(ctx context.Context, p Params, w keyring.Workspace) (keyring.WorkspaceTokens, error) {
+	tokens, err := keyring.GetWorkspaceTokens(w)
+	if err != nil {
+		return tokens, err
+	}
+	if tokens.RefreshToken == "" || tokens.ExpiresAt.IsZero() || time.Until(tokens.ExpiresAt) > RefreshMargin {
+		return tokens, nil
+	}
+
+	result, err := Refresh(ctx, p, tokens.RefreshToken)
+	if err != nil {
+		return tokens, err
+	}
+	if err := keyring.UpdateWorkspaceToken(w, result.UserToken); err != nil {
+		return tokens, err
+	}
+	refresh := result.RefreshToken
+	if refresh == "" {
+		refresh = tokens.RefreshToken
+	}
+	if err := keyring.SetWorkspaceRefresh(w.ID, refresh, result.ExpiresAt); err != nil {
+		return tokens, err
+	}
+	tokens.UserToken = result.UserToken
+	tokens.RefreshToken = refresh
+	tokens.ExpiresAt = result.ExpiresAt
+	return tokens, nil
+}
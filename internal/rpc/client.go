@@ -0,0 +1,84 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+
+	"github.com/m96-chan/Slacko/internal/consts"
+)
+
+// Conn is a client connection to a control socket.
+type Conn struct {
+	conn net.Conn
+}
+
+// Dial connects to the control socket at path. The socket's directory must
+// be private to the current user, so another user cannot pose as a running
+// instance.
+func Dial(path string) (*Conn, error) {
+	if err := consts.MkdirPrivate(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{conn: conn}, nil
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// message is any message a server sends: a response or a notification.
+type message struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Error  *Error          `json:"error"`
+}
+
+// Subscribe subscribes to events (all events when empty) and calls fn with
+// the params of each event notification until ctx is done or the server
+// closes the connection. It closes the connection when it returns.
+func (c *Conn) Subscribe(ctx context.Context, events []string, fn func(params json.RawMessage)) error {
+	defer c.conn.Close()
+	stop := context.AfterFunc(ctx, func() { c.conn.Close() })
+	defer stop()
+
+	req := Request{JSONRPC: Version, ID: json.RawMessage("1"), Method: "subscribe"}
+	if len(events) > 0 {
+		req.Params, _ = json.Marshal(subscribeParams{Events: events})
+	}
+	if err := json.NewEncoder(c.conn).Encode(req); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRequestSize)
+	for scanner.Scan() {
+		var msg message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			return fmt.Errorf("invalid message from control socket: %w", err)
+		}
+		switch {
+		case msg.Error != nil:
+			return msg.Error
+		case msg.Method == EventMethod:
+			fn(msg.Params)
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return errors.New("control socket closed")
+}
@@ -0,0 +1,83 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConnSubscribe(t *testing.T) {
+	s, path := startServer(t)
+	conn, err := Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	got := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- conn.Subscribe(ctx, []string{"message"}, func(params json.RawMessage) {
+			var p struct {
+				N string `json:"n"`
+			}
+			json.Unmarshal(params, &p)
+			select {
+			case got <- p.N:
+			default:
+			}
+		})
+	}()
+
+	// The subscription starts asynchronously; publish until it is seen.
+	tick := time.NewTicker(10 * time.Millisecond)
+	defer tick.Stop()
+	for n := ""; n == ""; {
+		select {
+		case n = <-got:
+			if n != "2" {
+				t.Errorf("event n = %q, want the message event", n)
+			}
+		case <-tick.C:
+			s.Publish("reaction_added", map[string]string{"n": "1"})
+			s.Publish("message", map[string]string{"n": "2"})
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event received")
+		}
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Subscribe after cancel = %v, want context.Canceled", err)
+	}
+}
+
+func TestConnSubscribeServerClosed(t *testing.T) {
+	s, path := startServer(t)
+	conn, err := Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- conn.Subscribe(context.Background(), nil, func(json.RawMessage) {}) }()
+
+	s.Close()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Subscribe returned nil after the server closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Subscribe did not return after the server closed")
+	}
+}
+
+func TestDialNoServer(t *testing.T) {
+	_, path := startServer(t)
+	if _, err := Dial(path + ".missing"); err == nil {
+		t.Error("Dial succeeded without a listening socket")
+	}
+}
@@ -0,0 +1,356 @@
+// Package rpc serves a JSON-RPC 2.0 API on a Unix domain socket. Requests,
+// responses and notifications are JSON objects separated by newlines.
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/m96-chan/Slacko/internal/consts"
+)
+
+// Version is the JSON-RPC protocol version.
+const Version = "2.0"
+
+// Standard JSON-RPC error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	// CodeServerError is used for errors returned by method handlers.
+	CodeServerError = -32000
+)
+
+// EventMethod is the method name of event notifications sent to subscribers.
+const EventMethod = "event"
+
+// outboxSize is how many messages may be queued for a client. Events for a
+// client that falls further behind are dropped.
+const outboxSize = 64
+
+// maxRequestSize limits the size of a single request line.
+const maxRequestSize = 1 << 20
+
+// Error is a JSON-RPC error object. Handlers return it to choose the code;
+// other errors are reported with CodeServerError.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// InvalidParams returns an Error with CodeInvalidParams.
+func InvalidParams(format string, args ...any) *Error {
+	return &Error{Code: CodeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+// Request is a JSON-RPC request. A request without an ID is a notification
+// and gets no response.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC response.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Notification is a message sent to a client without a request, such as an
+// event for a subscriber.
+type Notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// HandlerFunc handles a method call. params is the raw "params" member,
+// which may be empty. The result is encoded as JSON.
+type HandlerFunc func(params json.RawMessage) (any, error)
+
+// Server is a JSON-RPC server. Besides the methods registered with Handle,
+// it implements "subscribe" and "unsubscribe" for the event stream fed by
+// Publish.
+type Server struct {
+	mu       sync.Mutex
+	handlers map[string]HandlerFunc
+	clients  map[*client]struct{}
+	ln       net.Listener
+}
+
+// NewServer creates a server with no methods.
+func NewServer() *Server {
+	return &Server{
+		handlers: make(map[string]HandlerFunc),
+		clients:  make(map[*client]struct{}),
+	}
+}
+
+// SocketPath returns the control socket path for a workspace.
+func SocketPath(teamID string) string {
+	return filepath.Join(consts.RuntimeDir(), teamID+".sock")
+}
+
+// Handle registers h for method.
+func (s *Server) Handle(method string, h HandlerFunc) {
+	s.mu.Lock()
+	s.handlers[method] = h
+	s.mu.Unlock()
+}
+
+// Listen creates the socket at path, with a private parent directory. A
+// leftover socket from a previous run is replaced; one that another process
+// is still serving is an error.
+func (s *Server) Listen(path string) error {
+	if err := consts.MkdirPrivate(filepath.Dir(path)); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		if c, err := net.Dial("unix", path); err == nil {
+			c.Close()
+			return fmt.Errorf("%s is in use by another process", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close()
+		return err
+	}
+	s.mu.Lock()
+	s.ln = ln
+	s.mu.Unlock()
+	return nil
+}
+
+// Serve accepts connections until Close is called. Listen must be called
+// first.
+func (s *Server) Serve() error {
+	s.mu.Lock()
+	ln := s.ln
+	s.mu.Unlock()
+	if ln == nil {
+		return errors.New("rpc: Serve called before Listen")
+	}
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		c := &client{conn: conn, out: make(chan any, outboxSize)}
+		s.mu.Lock()
+		s.clients[c] = struct{}{}
+		s.mu.Unlock()
+		go c.writeLoop()
+		go s.serveClient(c)
+	}
+}
+
+// Close stops accepting connections, disconnects all clients and removes
+// the socket.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	ln := s.ln
+	clients := make([]*client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range clients {
+		c.conn.Close()
+	}
+	if ln == nil {
+		return nil
+	}
+	return ln.Close()
+}
+
+// Publish sends an event notification with params to every client
+// subscribed to event. It never blocks; clients whose queue is full miss the
+// event.
+func (s *Server) Publish(event string, params any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		if !c.subscribed(event) {
+			continue
+		}
+		select {
+		case c.out <- Notification{JSONRPC: Version, Method: EventMethod, Params: params}:
+		default:
+			slog.Warn("rpc client is not keeping up, dropping event", "event", event)
+		}
+	}
+}
+
+// serveClient reads requests from c until it disconnects.
+func (s *Server) serveClient(c *client) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+		c.conn.Close()
+		close(c.out)
+	}()
+
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxRequestSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if resp := s.handle(c, line); resp != nil {
+			c.out <- resp
+		}
+	}
+}
+
+// handle processes one request line and returns the response, or nil for a
+// notification.
+func (s *Server) handle(c *client, line []byte) *Response {
+	var req Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return errorResponse(nil, &Error{Code: CodeParseError, Message: err.Error()})
+	}
+	if req.JSONRPC != Version || req.Method == "" {
+		return errorResponse(req.ID, &Error{Code: CodeInvalidRequest, Message: "invalid request"})
+	}
+
+	var result any
+	var err error
+	switch req.Method {
+	case "subscribe":
+		result, err = c.subscribe(req.Params)
+	case "unsubscribe":
+		c.unsubscribe()
+	default:
+		s.mu.Lock()
+		h, ok := s.handlers[req.Method]
+		s.mu.Unlock()
+		if !ok {
+			err = &Error{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+		} else {
+			result, err = h(req.Params)
+		}
+	}
+
+	if req.ID == nil {
+		if err != nil {
+			slog.Debug("rpc notification failed", "method", req.Method, "error", err)
+		}
+		return nil
+	}
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeServerError, Message: err.Error()}
+		}
+		return errorResponse(req.ID, rpcErr)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(req.ID, &Error{Code: CodeInternalError, Message: err.Error()})
+	}
+	return &Response{JSONRPC: Version, ID: req.ID, Result: data}
+}
+
+// errorResponse builds an error response. A missing ID is sent as null.
+func errorResponse(id json.RawMessage, err *Error) *Response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &Response{JSONRPC: Version, ID: id, Error: err}
+}
+
+// client is a connection to the server.
+type client struct {
+	conn net.Conn
+	out  chan any // responses and notifications, written by writeLoop
+
+	mu     sync.Mutex
+	events []string // subscribed events; nil means not subscribed, empty means all
+}
+
+// subscribeParams are the parameters of "subscribe".
+type subscribeParams struct {
+	// Events limits the subscription to these event names; empty means all.
+	Events []string `json:"events"`
+}
+
+// subscribe starts (or replaces) the client's event subscription.
+func (c *client) subscribe(raw json.RawMessage) (any, error) {
+	var p subscribeParams
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, InvalidParams("%v", err)
+		}
+	}
+	events := p.Events
+	if events == nil {
+		events = []string{}
+	}
+	c.mu.Lock()
+	c.events = events
+	c.mu.Unlock()
+	return map[string][]string{"events": events}, nil
+}
+
+// unsubscribe stops the client's event subscription.
+func (c *client) unsubscribe() {
+	c.mu.Lock()
+	c.events = nil
+	c.mu.Unlock()
+}
+
+// subscribed reports whether the client wants event.
+func (c *client) subscribed(event string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.events != nil && (len(c.events) == 0 || slices.Contains(c.events, event))
+}
+
+// writeLoop writes queued messages until the queue is closed.
+func (c *client) writeLoop() {
+	enc := json.NewEncoder(c.conn)
+	for msg := range c.out {
+		if err := enc.Encode(msg); err != nil {
+			slog.Debug("rpc write failed", "error", err)
+			c.conn.Close()
+			// Drain so senders never block on a dead client.
+			for range c.out {
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	s := NewServer()
+	s.Handle("echo", func(params json.RawMessage) (any, error) {
+		var p struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, InvalidParams("%v", err)
+		}
+		return p, nil
+	})
+	s.Handle("fail", func(json.RawMessage) (any, error) {
+		return nil, errors.New("boom")
+	})
+
+	path := filepath.Join(t.TempDir(), "run", "test.sock")
+	if err := s.Listen(path); err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve()
+	t.Cleanup(func() { s.Close() })
+	return s, path
+}
+
+type testClient struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+func dial(t *testing.T, path string) *testClient {
+	t.Helper()
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &testClient{conn: conn, scanner: bufio.NewScanner(conn)}
+}
+
+// call sends line and returns the next message from the server.
+func (c *testClient) call(t *testing.T, line string) map[string]any {
+	t.Helper()
+	if _, err := c.conn.Write([]byte(line + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	return c.read(t)
+}
+
+func (c *testClient) read(t *testing.T) map[string]any {
+	t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if !c.scanner.Scan() {
+		t.Fatalf("no message from server: %v", c.scanner.Err())
+	}
+	var msg map[string]any
+	if err := json.Unmarshal(c.scanner.Bytes(), &msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func errorCode(msg map[string]any) int {
+	e, _ := msg["error"].(map[string]any)
+	code, _ := e["code"].(float64)
+	return int(code)
+}
+
+func TestServerCall(t *testing.T) {
+	_, path := startServer(t)
+	c := dial(t, path)
+
+	resp := c.call(t, `{"jsonrpc":"2.0","id":1,"method":"echo","params":{"text":"hi"}}`)
+	if resp["id"] != 1.0 || resp["result"].(map[string]any)["text"] != "hi" {
+		t.Errorf("response = %v", resp)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	_, path := startServer(t)
+	c := dial(t, path)
+
+	tests := []struct {
+		line string
+		code int
+	}{
+		{`not json`, CodeParseError},
+		{`{"id":1,"method":"echo"}`, CodeInvalidRequest},
+		{`{"jsonrpc":"2.0","id":2,"method":"nope"}`, CodeMethodNotFound},
+		{`{"jsonrpc":"2.0","id":3,"method":"echo","params":[1]}`, CodeInvalidParams},
+		{`{"jsonrpc":"2.0","id":4,"method":"fail"}`, CodeServerError},
+	}
+	for _, tt := range tests {
+		if got := errorCode(c.call(t, tt.line)); got != tt.code {
+			t.Errorf("%s: error code = %d, want %d", tt.line, got, tt.code)
+		}
+	}
+}
+
+func TestServerNotificationGetsNoResponse(t *testing.T) {
+	_, path := startServer(t)
+	c := dial(t, path)
+
+	// The notification must not produce a response, so the next message is
+	// the reply to the call.
+	c.conn.Write([]byte(`{"jsonrpc":"2.0","method":"echo","params":{"text":"x"}}` + "\n"))
+	resp := c.call(t, `{"jsonrpc":"2.0","id":"a","method":"echo","params":{"text":"y"}}`)
+	if resp["id"] != "a" {
+		t.Errorf("response = %v, want reply to id a", resp)
+	}
+}
+
+func TestServerSubscribe(t *testing.T) {
+	s, path := startServer(t)
+	c := dial(t, path)
+	other := dial(t, path)
+
+	resp := c.call(t, `{"jsonrpc":"2.0","id":1,"method":"subscribe","params":{"events":["message"]}}`)
+	if resp["error"] != nil {
+		t.Fatalf("subscribe failed: %v", resp)
+	}
+	other.call(t, `{"jsonrpc":"2.0","id":1,"method":"echo","params":{"text":"x"}}`)
+
+	s.Publish("reaction_added", map[string]string{"n": "1"})
+	s.Publish("message", map[string]string{"n": "2"})
+
+	msg := c.read(t)
+	if msg["method"] != EventMethod || msg["params"].(map[string]any)["n"] != "2" {
+		t.Errorf("event = %v, want the message event only", msg)
+	}
+
+	// The client that did not subscribe gets nothing: its next message is
+	// the reply to its next call.
+	if resp := other.call(t, `{"jsonrpc":"2.0","id":2,"method":"echo","params":{"text":"x"}}`); resp["id"] != 2.0 {
+		t.Errorf("unsubscribed client got %v", resp)
+	}
+}
+
+func TestListenReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "stale.sock")
+	first := NewServer()
+	if err := first.Listen(path); err != nil {
+		t.Fatal(err)
+	}
+	go first.Serve()
+
+	second := NewServer()
+	err := second.Listen(path)
+	if err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Errorf("Listen on a live socket = %v, want in use error", err)
+	}
+
+	first.Close()
+	// Leave a socket file behind, as after a crash.
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	ln.Close()
+
+	if err := second.Listen(path); err != nil {
+		t.Fatalf("Listen over a stale socket: %v", err)
+	}
+	second.Close()
+}
+
+func TestListenRefusesSharedDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("directory modes are not checked on Windows")
+	}
+	dir := filepath.Join(t.TempDir(), "run")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	// A directory others can write to, like one pre-created in /tmp.
+	if err := os.Chmod(dir, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewServer().Listen(filepath.Join(dir, "T1.sock")); err == nil {
+		t.Error("Listen accepted a directory other users can write to")
+	}
+}
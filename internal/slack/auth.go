@@ -0,0 +1,46 @@
+package slack
+
+import (
+	"errors"
+
+	"github.com/slack-go/slack"
+)
+
+// Token problems reported by AuthProblem.
+const (
+	TokenRevoked = "revoked"
+	TokenExpired = "expired"
+	TokenInvalid = "invalid"
+)
+
+// ValidateToken checks a user token with auth.test and returns the identity
+// it belongs to. Unlike New it does not require an app token.
+func ValidateToken(userToken string, options ...slack.Option) (*slack.AuthTestResponse, error) {
+	api := slack.New(userToken, options...)
+	var resp *slack.AuthTestResponse
+	err := retryOnRateLimit(func() error {
+		var e error
+		resp, e = api.AuthTest()
+		return e
+	})
+	return resp, err
+}
+
+// AuthProblem classifies an auth.test error as TokenRevoked, TokenExpired or
+// TokenInvalid. Other errors (network failures, rate limits) return "".
+func AuthProblem(err error) string {
+	var slackErr slack.SlackErrorResponse
+	if !errors.As(err, &slackErr) {
+		return ""
+	}
+	switch slackErr.Err {
+	case "token_revoked", "account_inactive":
+		return TokenRevoked
+	case "token_expired":
+		return TokenExpired
+	case "invalid_auth", "not_authed":
+		return TokenInvalid
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,51 @@
+package slack
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestAuthProblem(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{slack.SlackErrorResponse{Err: "token_revoked"}, TokenRevoked},
+		{slack.SlackErrorResponse{Err: "account_inactive"}, TokenRevoked},
+		{slack.SlackErrorResponse{Err: "token_expired"}, TokenExpired},
+		{slack.SlackErrorResponse{Err: "invalid_auth"}, TokenInvalid},
+		{slack.SlackErrorResponse{Err: "ratelimited"}, ""},
+		{errors.New("dial tcp: timeout"), ""},
+	}
+	for _, tt := range tests {
+		if got := AuthProblem(tt.err); got != tt.want {
+			t.Errorf("AuthProblem(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestValidateToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") == "Bearer xoxp-good" || r.FormValue("token") == "xoxp-good" {
+			w.Write([]byte(`{"ok":true,"team_id":"T1","team":"Team","user_id":"U1","user":"alice"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":false,"error":"token_revoked"}`))
+	}))
+	defer srv.Close()
+
+	resp, err := ValidateToken("xoxp-good", slack.OptionAPIURL(srv.URL+"/"))
+	if err != nil || resp.TeamID != "T1" {
+		t.Fatalf("ValidateToken(good) = %+v, %v", resp, err)
+	}
+
+	_, err = ValidateToken("xoxp-bad", slack.OptionAPIURL(srv.URL+"/"))
+	if AuthProblem(err) != TokenRevoked {
+		t.Errorf("ValidateToken(bad) error = %v, want revoked", err)
+	}
+}
@@ -3,7 +3,10 @@ package slack
 import (
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/slack-go/slack"
@@ -12,12 +15,20 @@ import (
 // Client is a thin wrapper around slack.Client with rate-limit retry
 // and cached identity information.
 type Client struct {
+	mu       sync.RWMutex
 	api      *slack.Client
 	token    string
-	UserID   string
-	TeamID   string
-	TeamName string
-	UserName string
+	appToken string
+
+	UserID     string
+	TeamID     string
+	TeamName   string
+	UserName   string
+	TeamDomain string // workspace subdomain ("team" for team.slack.com)
+
+	rtmMu       sync.Mutex
+	rtm         *rtmConn // open RTM connection, if any
+	presenceIDs []string // users whose presence RTM is subscribed to
 }
 
 // New creates a Client, validates the tokens via AuthTest, and populates
@@ -40,20 +51,47 @@ func New(userToken, appToken string) (*Client, error) {
 	}
 
 	return &Client{
-		api:      api,
-		token:    userToken,
-		UserID:   resp.UserID,
-		TeamID:   resp.TeamID,
-		TeamName: resp.Team,
-		UserName: resp.User,
+		api:        api,
+		token:      userToken,
+		appToken:   appToken,
+		UserID:     resp.UserID,
+		TeamID:     resp.TeamID,
+		TeamName:   resp.Team,
+		UserName:   resp.User,
+		TeamDomain: TeamDomainFromURL(resp.URL),
 	}, nil
 }
 
 // API returns the underlying slack.Client for direct access (e.g. socketmode).
-func (c *Client) API() *slack.Client { return c.api }
+func (c *Client) API() *slack.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.api
+}
 
 // Token returns the user token for authenticated HTTP requests.
-func (c *Client) Token() string { return c.token }
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+// AppToken returns the app-level token used for Socket Mode.
+func (c *Client) AppToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.appToken
+}
+
+// SetUserToken switches the client to a refreshed user token, for
+// workspaces with token rotation. Requests already in flight finish with the
+// old token.
+func (c *Client) SetUserToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.api = slack.New(token, slack.OptionAppLevelToken(c.appToken))
+	c.token = token
+}
 
 // retryOnRateLimit executes fn and, if a RateLimitedError is returned,
 // sleeps for the requested duration and retries once.
@@ -79,7 +117,7 @@ func (c *Client) GetConversations(params *slack.GetConversationsParameters) ([]s
 	)
 	err := retryOnRateLimit(func() error {
 		var e error
-		channels, cursor, e = c.api.GetConversations(params)
+		channels, cursor, e = c.API().GetConversations(params)
 		return e
 	})
 	return channels, cursor, err
@@ -90,7 +128,7 @@ func (c *Client) GetConversationHistory(params *slack.GetConversationHistoryPara
 	var resp *slack.GetConversationHistoryResponse
 	err := retryOnRateLimit(func() error {
 		var e error
-		resp, e = c.api.GetConversationHistory(params)
+		resp, e = c.API().GetConversationHistory(params)
 		return e
 	})
 	return resp, err
@@ -105,7 +143,7 @@ func (c *Client) GetConversationReplies(params *slack.GetConversationRepliesPara
 	)
 	err := retryOnRateLimit(func() error {
 		var e error
-		msgs, hasMore, cursor, e = c.api.GetConversationReplies(params)
+		msgs, hasMore, cursor, e = c.API().GetConversationReplies(params)
 		return e
 	})
 	return msgs, hasMore, cursor, err
@@ -120,16 +158,16 @@ func (c *Client) PostMessage(channelID string, options ...slack.MsgOption) (stri
 	)
 	err := retryOnRateLimit(func() error {
 		var e error
-		channel, ts, e = c.api.PostMessage(channelID, options...)
+		channel, ts, e = c.API().PostMessage(channelID, options...)
 		return e
 	})
 	if err != nil && isNotInChannel(err) {
-		if _, _, _, joinErr := c.api.JoinConversation(channelID); joinErr != nil {
+		if _, _, _, joinErr := c.API().JoinConversation(channelID); joinErr != nil {
 			return "", "", fmt.Errorf("auto-join failed: %w", joinErr)
 		}
 		err = retryOnRateLimit(func() error {
 			var e error
-			channel, ts, e = c.api.PostMessage(channelID, options...)
+			channel, ts, e = c.API().PostMessage(channelID, options...)
 			return e
 		})
 	}
@@ -154,7 +192,7 @@ func (c *Client) UpdateMessage(channelID, timestamp string, options ...slack.Msg
 	)
 	err := retryOnRateLimit(func() error {
 		var e error
-		channel, ts, text, e = c.api.UpdateMessage(channelID, timestamp, options...)
+		channel, ts, text, e = c.API().UpdateMessage(channelID, timestamp, options...)
 		return e
 	})
 	return channel, ts, text, err
@@ -168,7 +206,7 @@ func (c *Client) DeleteMessage(channelID, timestamp string) (string, string, err
 	)
 	err := retryOnRateLimit(func() error {
 		var e error
-		channel, ts, e = c.api.DeleteMessage(channelID, timestamp)
+		channel, ts, e = c.API().DeleteMessage(channelID, timestamp)
 		return e
 	})
 	return channel, ts, err
@@ -177,34 +215,53 @@ func (c *Client) DeleteMessage(channelID, timestamp string) (string, string, err
 // AddReaction adds a reaction emoji to an item.
 func (c *Client) AddReaction(name string, item slack.ItemRef) error {
 	return retryOnRateLimit(func() error {
-		return c.api.AddReaction(name, item)
+		return c.API().AddReaction(name, item)
 	})
 }
 
 // RemoveReaction removes a reaction emoji from an item.
 func (c *Client) RemoveReaction(name string, item slack.ItemRef) error {
 	return retryOnRateLimit(func() error {
-		return c.api.RemoveReaction(name, item)
+		return c.API().RemoveReaction(name, item)
 	})
 }
 
-// UploadFile uploads a file to Slack.
+// UploadFile uploads a file to Slack. FileSize is filled in from the file on
+// disk when not set, since files.upload v2 requires it.
 func (c *Client) UploadFile(params slack.UploadFileParameters) (*slack.FileSummary, error) {
+	if params.FileSize == 0 {
+		switch {
+		case params.File != "":
+			info, err := os.Stat(params.File)
+			if err != nil {
+				return nil, err
+			}
+			params.FileSize = int(info.Size())
+		case params.Content != "":
+			params.FileSize = len(params.Content)
+		}
+	}
+
 	var file *slack.FileSummary
 	err := retryOnRateLimit(func() error {
 		var e error
-		file, e = c.api.UploadFile(params)
+		file, e = c.API().UploadFile(params)
 		return e
 	})
 	return file, err
 }
 
+// GetFile downloads a private file URL into w using the user token.
+func (c *Client) GetFile(url string, w io.Writer) error {
+	return c.API().GetFile(url, w)
+}
+
 // GetUserInfo returns detailed information about a user.
 func (c *Client) GetUserInfo(userID string) (*slack.User, error) {
 	var user *slack.User
 	err := retryOnRateLimit(func() error {
 		var e error
-		user, e = c.api.GetUserInfo(userID)
+		user, e = c.API().GetUserInfo(userID)
 		return e
 	})
 	return user, err
@@ -215,7 +272,7 @@ func (c *Client) GetUsers() ([]slack.User, error) {
 	var users []slack.User
 	err := retryOnRateLimit(func() error {
 		var e error
-		users, e = c.api.GetUsers()
+		users, e = c.API().GetUsers()
 		return e
 	})
 	return users, err
@@ -224,7 +281,7 @@ func (c *Client) GetUsers() ([]slack.User, error) {
 // MarkConversation sets the read cursor for a conversation to a specific message.
 func (c *Client) MarkConversation(channel, ts string) error {
 	return retryOnRateLimit(func() error {
-		return c.api.MarkConversation(channel, ts)
+		return c.API().MarkConversation(channel, ts)
 	})
 }
 
@@ -233,7 +290,18 @@ func (c *Client) SearchMessages(query string, params slack.SearchParameters) (*s
 	var results *slack.SearchMessages
 	err := retryOnRateLimit(func() error {
 		var e error
-		results, e = c.api.SearchMessages(query, params)
+		results, e = c.API().SearchMessages(query, params)
+		return e
+	})
+	return results, err
+}
+
+// SearchFiles searches for files matching a query.
+func (c *Client) SearchFiles(query string, params slack.SearchParameters) (*slack.SearchFiles, error) {
+	var results *slack.SearchFiles
+	err := retryOnRateLimit(func() error {
+		var e error
+		results, e = c.API().SearchFiles(query, params)
 		return e
 	})
 	return results, err
@@ -244,7 +312,7 @@ func (c *Client) GetPermalink(channelID, timestamp string) (string, error) {
 	var permalink string
 	err := retryOnRateLimit(func() error {
 		var e error
-		permalink, e = c.api.GetPermalink(&slack.PermalinkParameters{
+		permalink, e = c.API().GetPermalink(&slack.PermalinkParameters{
 			Channel: channelID,
 			Ts:      timestamp,
 		})
@@ -258,7 +326,7 @@ func (c *Client) ListPins(channel string) ([]slack.Item, error) {
 	var items []slack.Item
 	err := retryOnRateLimit(func() error {
 		var e error
-		items, _, e = c.api.ListPins(channel)
+		items, _, e = c.API().ListPins(channel)
 		return e
 	})
 	return items, err
@@ -267,14 +335,14 @@ func (c *Client) ListPins(channel string) ([]slack.Item, error) {
 // AddPin pins an item to a channel.
 func (c *Client) AddPin(channel string, item slack.ItemRef) error {
 	return retryOnRateLimit(func() error {
-		return c.api.AddPin(channel, item)
+		return c.API().AddPin(channel, item)
 	})
 }
 
 // RemovePin unpins an item from a channel.
 func (c *Client) RemovePin(channel string, item slack.ItemRef) error {
 	return retryOnRateLimit(func() error {
-		return c.api.RemovePin(channel, item)
+		return c.API().RemovePin(channel, item)
 	})
 }
 
@@ -283,7 +351,7 @@ func (c *Client) GetConversationInfo(channelID string) (*slack.Channel, error) {
 	var ch *slack.Channel
 	err := retryOnRateLimit(func() error {
 		var e error
-		ch, e = c.api.GetConversationInfo(&slack.GetConversationInfoInput{
+		ch, e = c.API().GetConversationInfo(&slack.GetConversationInfoInput{
 			ChannelID:         channelID,
 			IncludeNumMembers: true,
 		})
@@ -297,7 +365,7 @@ func (c *Client) SetTopic(channelID, topic string) (*slack.Channel, error) {
 	var ch *slack.Channel
 	err := retryOnRateLimit(func() error {
 		var e error
-		ch, e = c.api.SetTopicOfConversation(channelID, topic)
+		ch, e = c.API().SetTopicOfConversation(channelID, topic)
 		return e
 	})
 	return ch, err
@@ -308,7 +376,7 @@ func (c *Client) SetPurpose(channelID, purpose string) (*slack.Channel, error) {
 	var ch *slack.Channel
 	err := retryOnRateLimit(func() error {
 		var e error
-		ch, e = c.api.SetPurposeOfConversation(channelID, purpose)
+		ch, e = c.API().SetPurposeOfConversation(channelID, purpose)
 		return e
 	})
 	return ch, err
@@ -319,7 +387,7 @@ func (c *Client) LeaveConversation(channelID string) (bool, error) {
 	var notInChannel bool
 	err := retryOnRateLimit(func() error {
 		var e error
-		notInChannel, e = c.api.LeaveConversation(channelID)
+		notInChannel, e = c.API().LeaveConversation(channelID)
 		return e
 	})
 	return notInChannel, err
@@ -328,14 +396,14 @@ func (c *Client) LeaveConversation(channelID string) (bool, error) {
 // AddStar stars an item.
 func (c *Client) AddStar(channel string, item slack.ItemRef) error {
 	return retryOnRateLimit(func() error {
-		return c.api.AddStar(channel, item)
+		return c.API().AddStar(channel, item)
 	})
 }
 
 // RemoveStar removes a star from an item.
 func (c *Client) RemoveStar(channel string, item slack.ItemRef) error {
 	return retryOnRateLimit(func() error {
-		return c.api.RemoveStar(channel, item)
+		return c.API().RemoveStar(channel, item)
 	})
 }
 
@@ -344,7 +412,7 @@ func (c *Client) ListAllStars() ([]slack.Item, error) {
 	var items []slack.Item
 	err := retryOnRateLimit(func() error {
 		var e error
-		items, e = c.api.ListAllStars()
+		items, e = c.API().ListAllStars()
 		return e
 	})
 	return items, err
@@ -353,7 +421,7 @@ func (c *Client) ListAllStars() ([]slack.Item, error) {
 // SetUserCustomStatus sets the authenticated user's status emoji and text.
 func (c *Client) SetUserCustomStatus(statusText, statusEmoji string) error {
 	return retryOnRateLimit(func() error {
-		return c.api.SetUserCustomStatus(statusText, statusEmoji, 0)
+		return c.API().SetUserCustomStatus(statusText, statusEmoji, 0)
 	})
 }
 
@@ -363,7 +431,7 @@ func (c *Client) GetUsersInConversation(channelID, cursor string, limit int) ([]
 	var nextCursor string
 	err := retryOnRateLimit(func() error {
 		var e error
-		userIDs, nextCursor, e = c.api.GetUsersInConversation(&slack.GetUsersInConversationParameters{
+		userIDs, nextCursor, e = c.API().GetUsersInConversation(&slack.GetUsersInConversationParameters{
 			ChannelID: channelID,
 			Cursor:    cursor,
 			Limit:     limit,
@@ -379,7 +447,7 @@ func (c *Client) ScheduleMessage(channelID, postAt, text string) (string, string
 	var respChannel, scheduledID string
 	err := retryOnRateLimit(func() error {
 		var e error
-		respChannel, scheduledID, e = c.api.ScheduleMessage(channelID, postAt,
+		respChannel, scheduledID, e = c.API().ScheduleMessage(channelID, postAt,
 			slack.MsgOptionText(text, false))
 		return e
 	})
@@ -391,7 +459,7 @@ func (c *Client) GetScheduledMessages(channelID string) ([]slack.ScheduledMessag
 	var msgs []slack.ScheduledMessage
 	err := retryOnRateLimit(func() error {
 		var e error
-		msgs, _, e = c.api.GetScheduledMessages(&slack.GetScheduledMessagesParameters{
+		msgs, _, e = c.API().GetScheduledMessages(&slack.GetScheduledMessagesParameters{
 			Channel: channelID,
 		})
 		return e
@@ -402,7 +470,7 @@ func (c *Client) GetScheduledMessages(channelID string) ([]slack.ScheduledMessag
 // DeleteScheduledMessage cancels a scheduled message.
 func (c *Client) DeleteScheduledMessage(channelID, scheduledMessageID string) error {
 	return retryOnRateLimit(func() error {
-		_, err := c.api.DeleteScheduledMessage(&slack.DeleteScheduledMessageParameters{
+		_, err := c.API().DeleteScheduledMessage(&slack.DeleteScheduledMessageParameters{
 			Channel:            channelID,
 			ScheduledMessageID: scheduledMessageID,
 		})
@@ -415,7 +483,7 @@ func (c *Client) AddReminder(text, timeStr string) (*slack.Reminder, error) {
 	var rem *slack.Reminder
 	err := retryOnRateLimit(func() error {
 		var e error
-		rem, e = c.api.AddUserReminder(c.UserID, text, timeStr)
+		rem, e = c.API().AddUserReminder(c.UserID, text, timeStr)
 		return e
 	})
 	return rem, err
@@ -426,7 +494,7 @@ func (c *Client) ListReminders() ([]*slack.Reminder, error) {
 	var rems []*slack.Reminder
 	err := retryOnRateLimit(func() error {
 		var e error
-		rems, e = c.api.ListReminders()
+		rems, e = c.API().ListReminders()
 		return e
 	})
 	return rems, err
@@ -435,7 +503,7 @@ func (c *Client) ListReminders() ([]*slack.Reminder, error) {
 // DeleteReminder deletes a reminder by ID.
 func (c *Client) DeleteReminder(id string) error {
 	return retryOnRateLimit(func() error {
-		return c.api.DeleteReminder(id)
+		return c.API().DeleteReminder(id)
 	})
 }
 
@@ -444,7 +512,7 @@ func (c *Client) CreateConversation(name string, isPrivate bool) (*slack.Channel
 	var ch *slack.Channel
 	err := retryOnRateLimit(func() error {
 		var e error
-		ch, e = c.api.CreateConversation(slack.CreateConversationParams{
+		ch, e = c.API().CreateConversation(slack.CreateConversationParams{
 			ChannelName: name,
 			IsPrivate:   isPrivate,
 		})
@@ -458,7 +526,7 @@ func (c *Client) InviteUsersToConversation(channelID string, userIDs ...string)
 	var ch *slack.Channel
 	err := retryOnRateLimit(func() error {
 		var e error
-		ch, e = c.api.InviteUsersToConversation(channelID, userIDs...)
+		ch, e = c.API().InviteUsersToConversation(channelID, userIDs...)
 		return e
 	})
 	return ch, err
@@ -470,7 +538,7 @@ func (c *Client) OpenConversation(userIDs []string) (*slack.Channel, error) {
 	var ch *slack.Channel
 	err := retryOnRateLimit(func() error {
 		var e error
-		ch, _, _, e = c.api.OpenConversation(&slack.OpenConversationParameters{
+		ch, _, _, e = c.API().OpenConversation(&slack.OpenConversationParameters{
 			Users: userIDs,
 		})
 		return e
@@ -483,12 +551,23 @@ func (c *Client) ListBookmarks(channelID string) ([]slack.Bookmark, error) {
 	var bookmarks []slack.Bookmark
 	err := retryOnRateLimit(func() error {
 		var e error
-		bookmarks, e = c.api.ListBookmarks(channelID)
+		bookmarks, e = c.API().ListBookmarks(channelID)
 		return e
 	})
 	return bookmarks, err
 }
 
+// GetUserGroups returns the workspace's user groups including their members.
+func (c *Client) GetUserGroups() ([]slack.UserGroup, error) {
+	var groups []slack.UserGroup
+	err := retryOnRateLimit(func() error {
+		var e error
+		groups, e = c.API().GetUserGroups(slack.GetUserGroupsOptionIncludeUsers(true))
+		return e
+	})
+	return groups, err
+}
+
 // safePrefix returns the first 10 characters of a token for error messages.
 func safePrefix(token string) string {
 	if len(token) <= 10 {
@@ -4,17 +4,17 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 
 	"github.com/slack-go/slack/slackevents"
 	"github.com/slack-go/slack/socketmode"
 )
 
 // TypingEvent represents a user_typing event.
-// Note: user_typing is an RTM-only event. This struct is provided
-// so the infrastructure is ready when RTM support is added.
+// Note: user_typing is an RTM-only event; see RunRTM.
 type TypingEvent struct {
-	ChannelID string
-	UserID    string
+	ChannelID string `json:"channel"`
+	UserID    string `json:"user"`
 }
 
 // EventHandler holds typed callback fields — one per event kind.
@@ -22,7 +22,7 @@ type TypingEvent struct {
 // Nil callbacks are silently skipped.
 //
 // Note: user_typing and presence_change are RTM-only events and are
-// not available via Socket Mode / Events API.
+// not available via Socket Mode / Events API; RunRTM delivers them.
 type EventHandler struct {
 	OnMessage             func(*slackevents.MessageEvent)
 	OnMessageChanged      func(*slackevents.MessageEvent) // SubType "message_changed"
@@ -40,7 +40,8 @@ type EventHandler struct {
 	OnPinRemoved          func(*slackevents.PinRemovedEvent)
 	OnFileShared          func(*slackevents.FileSharedEvent)
 	OnUserStatusChanged   func(*slackevents.UserStatusChangedEvent)
-	OnTyping              func(*TypingEvent) // RTM-only; included for future support
+	OnTyping              func(*TypingEvent)   // RTM-only
+	OnPresenceChange      func(*PresenceEvent) // RTM-only
 	OnConnected           func()
 	OnDisconnected        func()
 	OnError               func(error)
@@ -48,13 +49,25 @@ type EventHandler struct {
 
 // RunSocketMode creates a socketmode.Client, registers event handlers from
 // the provided EventHandler, and runs the event loop. It blocks until ctx
-// is cancelled or a fatal error occurs.
+// is cancelled or a fatal error occurs. Events of other teams on the same
+// app token are dropped; use RunSocketModeShared to receive them.
 func (c *Client) RunSocketMode(ctx context.Context, handler *EventHandler) error {
-	smClient := socketmode.New(c.api)
+	d := NewDispatcher()
+	d.Add(c.TeamID, handler)
+	return c.RunSocketModeShared(ctx, d)
+}
+
+// RunSocketModeShared is like RunSocketMode, but delivers each event to the
+// handler d has for the event's team. Slack spreads the events of an app
+// over all of its open connections instead of sending each event to every
+// one, so workspaces signed in with the same app token have to share one
+// connection.
+func (c *Client) RunSocketModeShared(ctx context.Context, d *Dispatcher) error {
+	smClient := socketmode.New(c.API())
 	smHandler := socketmode.NewSocketmodeHandler(smClient)
 
-	registerEventHandlers(smHandler, handler)
-	registerLifecycleHandlers(smHandler, handler)
+	registerEventHandlers(smHandler, d)
+	registerLifecycleHandlers(smHandler, d)
 
 	// Catch-all: log any unhandled socket mode events for debugging.
 	smHandler.HandleDefault(func(evt *socketmode.Event, _ *socketmode.Client) {
@@ -64,9 +77,88 @@ func (c *Client) RunSocketMode(ctx context.Context, handler *EventHandler) error
 	return smHandler.RunEventLoopContext(ctx)
 }
 
+// Dispatcher routes the events of a Socket Mode connection to the
+// EventHandler of the team each event belongs to. Connection events go to
+// every handler.
+type Dispatcher struct {
+	mu        sync.Mutex
+	handlers  map[string]*EventHandler // by team ID
+	connected bool
+}
+
+// NewDispatcher creates a Dispatcher without handlers.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[string]*EventHandler)}
+}
+
+// Add routes the events of teamID to handler until the returned function is
+// called. A handler added while the connection is up is told so with
+// OnConnected.
+func (d *Dispatcher) Add(teamID string, handler *EventHandler) (remove func()) {
+	d.mu.Lock()
+	d.handlers[teamID] = handler
+	connected := d.connected
+	d.mu.Unlock()
+	if connected && handler.OnConnected != nil {
+		go handler.OnConnected()
+	}
+	return func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		// A newer handler for the team stays.
+		if d.handlers[teamID] == handler {
+			delete(d.handlers, teamID)
+		}
+	}
+}
+
+// Len returns the number of teams with a handler.
+func (d *Dispatcher) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.handlers)
+}
+
+// handler returns the handler for the events of teamID, or nil. Events
+// without a team go to the only handler when there is just one.
+func (d *Dispatcher) handler(teamID string) *EventHandler {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if h, ok := d.handlers[teamID]; ok {
+		return h
+	}
+	if teamID == "" && len(d.handlers) == 1 {
+		for _, h := range d.handlers {
+			return h
+		}
+	}
+	slog.Debug("dropped event of unknown team", "team", teamID)
+	return nil
+}
+
+// setConnected records whether the connection is up.
+func (d *Dispatcher) setConnected(connected bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.connected = connected
+}
+
+// each calls fn with every handler.
+func (d *Dispatcher) each(fn func(*EventHandler)) {
+	d.mu.Lock()
+	handlers := make([]*EventHandler, 0, len(d.handlers))
+	for _, h := range d.handlers {
+		handlers = append(handlers, h)
+	}
+	d.mu.Unlock()
+	for _, h := range handlers {
+		fn(h)
+	}
+}
+
 // registerEventHandlers wires Events API event types to the appropriate
-// EventHandler callbacks.
-func registerEventHandlers(smHandler *socketmode.SocketmodeHandler, handler *EventHandler) {
+// EventHandler callbacks of the event's team.
+func registerEventHandlers(smHandler *socketmode.SocketmodeHandler, d *Dispatcher) {
 	// Message events — routed by SubType.
 	smHandler.HandleEvents(slackevents.Message, func(evt *socketmode.Event, client *socketmode.Client) {
 		client.Ack(*evt.Request)
@@ -81,55 +173,63 @@ func registerEventHandlers(smHandler *socketmode.SocketmodeHandler, handler *Eve
 			slog.Debug("message event: unexpected inner type", "type", fmt.Sprintf("%T", apiEvt.InnerEvent.Data))
 			return
 		}
-		slog.Debug("message event received", "channel", msg.Channel, "user", msg.User, "subtype", msg.SubType)
+		slog.Debug("message event received", "team", apiEvt.TeamID, "channel", msg.Channel, "user", msg.User, "subtype", msg.SubType)
 
-		switch msg.SubType {
-		case "message_changed":
-			if handler.OnMessageChanged != nil {
-				handler.OnMessageChanged(msg)
-			}
-		case "message_deleted":
-			if handler.OnMessageDeleted != nil {
-				handler.OnMessageDeleted(msg)
-			}
-		default:
-			if handler.OnMessage != nil {
-				handler.OnMessage(msg)
-			}
+		if handler := d.handler(apiEvt.TeamID); handler != nil {
+			routeMessage(handler, msg)
 		}
 	})
 
 	// Reaction events.
-	registerTypedHandler(smHandler, slackevents.ReactionAdded, handler.OnReactionAdded)
-	registerTypedHandler(smHandler, slackevents.ReactionRemoved, handler.OnReactionRemoved)
+	registerTypedHandler(smHandler, d, slackevents.ReactionAdded, func(h *EventHandler) func(*slackevents.ReactionAddedEvent) { return h.OnReactionAdded })
+	registerTypedHandler(smHandler, d, slackevents.ReactionRemoved, func(h *EventHandler) func(*slackevents.ReactionRemovedEvent) { return h.OnReactionRemoved })
 
 	// Channel events.
-	registerTypedHandler(smHandler, slackevents.ChannelCreated, handler.OnChannelCreated)
-	registerTypedHandler(smHandler, slackevents.ChannelArchive, handler.OnChannelArchive)
-	registerTypedHandler(smHandler, slackevents.ChannelUnarchive, handler.OnChannelUnarchive)
-	registerTypedHandler(smHandler, slackevents.ChannelRename, handler.OnChannelRename)
+	registerTypedHandler(smHandler, d, slackevents.ChannelCreated, func(h *EventHandler) func(*slackevents.ChannelCreatedEvent) { return h.OnChannelCreated })
+	registerTypedHandler(smHandler, d, slackevents.ChannelArchive, func(h *EventHandler) func(*slackevents.ChannelArchiveEvent) { return h.OnChannelArchive })
+	registerTypedHandler(smHandler, d, slackevents.ChannelUnarchive, func(h *EventHandler) func(*slackevents.ChannelUnarchiveEvent) { return h.OnChannelUnarchive })
+	registerTypedHandler(smHandler, d, slackevents.ChannelRename, func(h *EventHandler) func(*slackevents.ChannelRenameEvent) { return h.OnChannelRename })
 
 	// Membership events.
-	registerTypedHandler(smHandler, slackevents.MemberJoinedChannel, handler.OnMemberJoinedChannel)
-	registerTypedHandler(smHandler, slackevents.MemberLeftChannel, handler.OnMemberLeftChannel)
+	registerTypedHandler(smHandler, d, slackevents.MemberJoinedChannel, func(h *EventHandler) func(*slackevents.MemberJoinedChannelEvent) { return h.OnMemberJoinedChannel })
+	registerTypedHandler(smHandler, d, slackevents.MemberLeftChannel, func(h *EventHandler) func(*slackevents.MemberLeftChannelEvent) { return h.OnMemberLeftChannel })
 
 	// Team events.
-	registerTypedHandler(smHandler, slackevents.TeamJoin, handler.OnTeamJoin)
+	registerTypedHandler(smHandler, d, slackevents.TeamJoin, func(h *EventHandler) func(*slackevents.TeamJoinEvent) { return h.OnTeamJoin })
 
 	// Pin events.
-	registerTypedHandler(smHandler, slackevents.PinAdded, handler.OnPinAdded)
-	registerTypedHandler(smHandler, slackevents.PinRemoved, handler.OnPinRemoved)
+	registerTypedHandler(smHandler, d, slackevents.PinAdded, func(h *EventHandler) func(*slackevents.PinAddedEvent) { return h.OnPinAdded })
+	registerTypedHandler(smHandler, d, slackevents.PinRemoved, func(h *EventHandler) func(*slackevents.PinRemovedEvent) { return h.OnPinRemoved })
 
 	// File events.
-	registerTypedHandler(smHandler, slackevents.FileShared, handler.OnFileShared)
+	registerTypedHandler(smHandler, d, slackevents.FileShared, func(h *EventHandler) func(*slackevents.FileSharedEvent) { return h.OnFileShared })
 
 	// User status events.
-	registerTypedHandler(smHandler, slackevents.UserStatusChanged, handler.OnUserStatusChanged)
+	registerTypedHandler(smHandler, d, slackevents.UserStatusChanged, func(h *EventHandler) func(*slackevents.UserStatusChangedEvent) { return h.OnUserStatusChanged })
+}
+
+// routeMessage calls the EventHandler callback for msg's SubType.
+func routeMessage(handler *EventHandler, msg *slackevents.MessageEvent) {
+	switch msg.SubType {
+	case "message_changed":
+		if handler.OnMessageChanged != nil {
+			handler.OnMessageChanged(msg)
+		}
+	case "message_deleted":
+		if handler.OnMessageDeleted != nil {
+			handler.OnMessageDeleted(msg)
+		}
+	default:
+		if handler.OnMessage != nil {
+			handler.OnMessage(msg)
+		}
+	}
 }
 
 // registerTypedHandler is a generic helper that registers a HandleEvents callback
-// which extracts the inner event, type-asserts it, and calls the provided callback.
-func registerTypedHandler[T any](smHandler *socketmode.SocketmodeHandler, eventType slackevents.EventsAPIType, callback func(*T)) {
+// which extracts the inner event, type-asserts it, and calls the callback that
+// callback returns for the handler of the event's team.
+func registerTypedHandler[T any](smHandler *socketmode.SocketmodeHandler, d *Dispatcher, eventType slackevents.EventsAPIType, callback func(*EventHandler) func(*T)) {
 	smHandler.HandleEvents(eventType, func(evt *socketmode.Event, client *socketmode.Client) {
 		client.Ack(*evt.Request)
 
@@ -144,15 +244,27 @@ func registerTypedHandler[T any](smHandler *socketmode.SocketmodeHandler, eventT
 				"data_type", fmt.Sprintf("%T", apiEvt.InnerEvent.Data))
 			return
 		}
-		if callback != nil {
-			callback(inner)
+		handler := d.handler(apiEvt.TeamID)
+		if handler == nil {
+			return
+		}
+		if fn := callback(handler); fn != nil {
+			fn(inner)
 		}
 	})
 }
 
 // registerLifecycleHandlers wires socketmode-level connection events to the
-// appropriate EventHandler callbacks.
-func registerLifecycleHandlers(smHandler *socketmode.SocketmodeHandler, handler *EventHandler) {
+// appropriate callbacks of every EventHandler of d.
+func registerLifecycleHandlers(smHandler *socketmode.SocketmodeHandler, d *Dispatcher) {
+	onError := func(err error) {
+		d.each(func(h *EventHandler) {
+			if h.OnError != nil {
+				h.OnError(err)
+			}
+		})
+	}
+
 	smHandler.Handle(socketmode.EventTypeConnecting, func(evt *socketmode.Event, _ *socketmode.Client) {
 		slog.Debug("socket mode connecting")
 	})
@@ -163,58 +275,53 @@ func registerLifecycleHandlers(smHandler *socketmode.SocketmodeHandler, handler
 
 	smHandler.Handle(socketmode.EventTypeErrorWriteFailed, func(evt *socketmode.Event, _ *socketmode.Client) {
 		slog.Warn("socket mode write failed", "data", evt.Data)
-		if handler.OnError != nil {
-			handler.OnError(fmt.Errorf("socket mode write failed: %v", evt.Data))
-		}
+		onError(fmt.Errorf("socket mode write failed: %v", evt.Data))
 	})
 
 	smHandler.Handle(socketmode.EventTypeErrorBadMessage, func(evt *socketmode.Event, _ *socketmode.Client) {
 		slog.Warn("socket mode bad message", "data", evt.Data)
-		if handler.OnError != nil {
-			handler.OnError(fmt.Errorf("socket mode bad message: %v", evt.Data))
-		}
+		onError(fmt.Errorf("socket mode bad message: %v", evt.Data))
 	})
 
 	smHandler.Handle(socketmode.EventTypeConnected, func(evt *socketmode.Event, _ *socketmode.Client) {
 		slog.Info("socket mode connected")
-		if handler.OnConnected != nil {
-			handler.OnConnected()
-		}
+		d.setConnected(true)
+		d.each(func(h *EventHandler) {
+			if h.OnConnected != nil {
+				h.OnConnected()
+			}
+		})
 	})
 
 	smHandler.Handle(socketmode.EventTypeDisconnect, func(evt *socketmode.Event, _ *socketmode.Client) {
 		slog.Warn("socket mode disconnected")
-		if handler.OnDisconnected != nil {
-			handler.OnDisconnected()
-		}
+		d.setConnected(false)
+		d.each(func(h *EventHandler) {
+			if h.OnDisconnected != nil {
+				h.OnDisconnected()
+			}
+		})
 	})
 
 	smHandler.Handle(socketmode.EventTypeIncomingError, func(evt *socketmode.Event, _ *socketmode.Client) {
-		if handler.OnError == nil {
-			return
-		}
 		if err, ok := evt.Data.(error); ok {
-			handler.OnError(err)
+			onError(err)
 		} else {
-			handler.OnError(fmt.Errorf("socket mode incoming error: %v", evt.Data))
+			onError(fmt.Errorf("socket mode incoming error: %v", evt.Data))
 		}
 	})
 
 	smHandler.Handle(socketmode.EventTypeConnectionError, func(evt *socketmode.Event, _ *socketmode.Client) {
 		slog.Warn("socket mode connection error", "data", evt.Data)
-		if handler.OnError != nil {
-			if err, ok := evt.Data.(error); ok {
-				handler.OnError(err)
-			} else {
-				handler.OnError(fmt.Errorf("socket mode connection error: %v", evt.Data))
-			}
+		if err, ok := evt.Data.(error); ok {
+			onError(err)
+		} else {
+			onError(fmt.Errorf("socket mode connection error: %v", evt.Data))
 		}
 	})
 
 	smHandler.Handle(socketmode.EventTypeInvalidAuth, func(evt *socketmode.Event, _ *socketmode.Client) {
 		slog.Error("socket mode invalid auth")
-		if handler.OnError != nil {
-			handler.OnError(fmt.Errorf("socket mode: invalid auth"))
-		}
+		onError(fmt.Errorf("socket mode: invalid auth"))
 	})
 }
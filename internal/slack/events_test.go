@@ -2,10 +2,14 @@ package slack
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
 )
 
 func TestMessageSubTypeRouting(t *testing.T) {
@@ -142,6 +146,87 @@ func TestLifecycleCallbacks(t *testing.T) {
 	}
 }
 
+func TestSharedConnectionRoutesByTeam(t *testing.T) {
+	var mu sync.Mutex
+	got := map[string][]string{}
+	record := func(team string) *EventHandler {
+		return &EventHandler{
+			OnMessage: func(msg *slackevents.MessageEvent) {
+				mu.Lock()
+				got[team] = append(got[team], msg.Channel)
+				mu.Unlock()
+			},
+			OnReactionAdded: func(evt *slackevents.ReactionAddedEvent) {
+				mu.Lock()
+				got[team] = append(got[team], evt.Reaction)
+				mu.Unlock()
+			},
+		}
+	}
+	d := NewDispatcher()
+	d.Add("T1", record("T1"))
+	d.Add("T2", record("T2"))
+
+	smClient := socketmode.New(slack.New("xoxp-test", slack.OptionAppLevelToken("xapp-test")))
+	smHandler := socketmode.NewSocketmodeHandler(smClient)
+	registerEventHandlers(smHandler, d)
+
+	deliver := func(team, eventType string, inner any) {
+		evt := &socketmode.Event{
+			Type:    socketmode.EventTypeEventsAPI,
+			Request: &socketmode.Request{EnvelopeID: team + eventType},
+			Data: slackevents.EventsAPIEvent{
+				TeamID:     team,
+				InnerEvent: slackevents.EventsAPIInnerEvent{Type: eventType, Data: inner},
+			},
+		}
+		for _, f := range smHandler.EventApiMap[slackevents.EventsAPIType(eventType)] {
+			f(evt, smClient)
+		}
+	}
+	deliver("T2", "message", &slackevents.MessageEvent{Channel: "D2"})
+	deliver("T1", "message", &slackevents.MessageEvent{Channel: "C1"})
+	deliver("T1", "reaction_added", &slackevents.ReactionAddedEvent{Reaction: "wave"})
+	deliver("T3", "message", &slackevents.MessageEvent{Channel: "C3"})
+
+	if !slices.Equal(got["T1"], []string{"C1", "wave"}) {
+		t.Errorf("T1 got %v, want [C1 wave]", got["T1"])
+	}
+	if !slices.Equal(got["T2"], []string{"D2"}) {
+		t.Errorf("T2 got %v, want [D2]", got["T2"])
+	}
+	if len(got) != 2 {
+		t.Errorf("events of an unknown team were delivered: %v", got)
+	}
+}
+
+func TestDispatcherAddRemove(t *testing.T) {
+	d := NewDispatcher()
+	old := &EventHandler{}
+	removeOld := d.Add("T1", old)
+
+	// A handler replacing another for the same team survives the old one's
+	// removal.
+	connected := make(chan struct{})
+	replacement := &EventHandler{OnConnected: func() { close(connected) }}
+	d.setConnected(true)
+	d.Add("T1", replacement)
+	removeOld()
+	if d.handler("T1") != replacement {
+		t.Error("removing the old handler dropped its replacement")
+	}
+	if d.handler("") != replacement {
+		t.Error("an event without a team did not reach the only handler")
+	}
+
+	// A handler added to a live connection is told it is connected.
+	select {
+	case <-connected:
+	case <-time.After(time.Second):
+		t.Error("OnConnected not called for a handler added while connected")
+	}
+}
+
 // --- test helpers that mirror the dispatch logic without needing a real socketmode.Client ---
 
 func dispatchMessage(handler *EventHandler, msg *slackevents.MessageEvent) {
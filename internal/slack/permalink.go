@@ -0,0 +1,82 @@
+package slack
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Permalink identifies a message referenced by a Slack permalink such as
+// https://team.slack.com/archives/C0123ABC/p1700000000123456?thread_ts=1699999999.000100
+type Permalink struct {
+	TeamDomain string // subdomain before .slack.com (e.g. "team", "acme.enterprise")
+	ChannelID  string
+	Timestamp  string // message ts in API form ("1700000000.123456")
+	ThreadTS   string // parent ts when the message is a thread reply
+}
+
+// permalinkPathRe matches the /archives/<channel>/p<digits> path of a permalink.
+var permalinkPathRe = regexp.MustCompile(`^/archives/([A-Z0-9]+)/p(\d{7,})/?$`)
+
+// ParsePermalink parses a Slack message permalink. Links wrapped in Slack's
+// mrkdwn angle brackets (<url|label>) are accepted.
+func ParsePermalink(raw string) (Permalink, error) {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "<")
+	raw = strings.TrimSuffix(raw, ">")
+	if i := strings.Index(raw, "|"); i >= 0 {
+		raw = raw[:i]
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Permalink{}, fmt.Errorf("invalid permalink: %w", err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return Permalink{}, fmt.Errorf("invalid permalink: not an http(s) URL")
+	}
+
+	host := strings.ToLower(u.Hostname())
+	domain, ok := strings.CutSuffix(host, ".slack.com")
+	if !ok || domain == "" {
+		return Permalink{}, fmt.Errorf("invalid permalink: %s is not a Slack workspace host", host)
+	}
+
+	m := permalinkPathRe.FindStringSubmatch(u.Path)
+	if m == nil {
+		return Permalink{}, fmt.Errorf("invalid permalink: unexpected path %q", u.Path)
+	}
+
+	digits := m[2]
+	ts := digits[:len(digits)-6] + "." + digits[len(digits)-6:]
+
+	threadTS := u.Query().Get("thread_ts")
+	if threadTS == ts {
+		threadTS = ""
+	}
+
+	return Permalink{
+		TeamDomain: domain,
+		ChannelID:  m[1],
+		Timestamp:  ts,
+		ThreadTS:   threadTS,
+	}, nil
+}
+
+// IsPermalink reports whether raw looks like a Slack message permalink.
+func IsPermalink(raw string) bool {
+	_, err := ParsePermalink(raw)
+	return err == nil
+}
+
+// TeamDomainFromURL extracts the workspace subdomain from an auth.test URL
+// such as "https://team.slack.com/".
+func TeamDomainFromURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	domain, _ := strings.CutSuffix(strings.ToLower(u.Hostname()), ".slack.com")
+	return domain
+}
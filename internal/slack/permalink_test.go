@@ -0,0 +1,75 @@
+package slack
+
+import "testing"
+
+func TestParsePermalink(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want Permalink
+	}{
+		{
+			name: "channel message",
+			raw:  "https://myteam.slack.com/archives/C0123ABC/p1700000000123456",
+			want: Permalink{TeamDomain: "myteam", ChannelID: "C0123ABC", Timestamp: "1700000000.123456"},
+		},
+		{
+			name: "thread reply",
+			raw:  "https://myteam.slack.com/archives/C0123ABC/p1700000100000200?thread_ts=1700000000.123456&cid=C0123ABC",
+			want: Permalink{TeamDomain: "myteam", ChannelID: "C0123ABC", Timestamp: "1700000100.000200", ThreadTS: "1700000000.123456"},
+		},
+		{
+			name: "thread parent drops redundant thread_ts",
+			raw:  "https://myteam.slack.com/archives/C0123ABC/p1700000000123456?thread_ts=1700000000.123456",
+			want: Permalink{TeamDomain: "myteam", ChannelID: "C0123ABC", Timestamp: "1700000000.123456"},
+		},
+		{
+			name: "enterprise grid host",
+			raw:  "https://acme.enterprise.slack.com/archives/D0456/p1700000000000001",
+			want: Permalink{TeamDomain: "acme.enterprise", ChannelID: "D0456", Timestamp: "1700000000.000001"},
+		},
+		{
+			name: "mrkdwn wrapped with label",
+			raw:  "<https://myteam.slack.com/archives/C0123ABC/p1700000000123456|this message>",
+			want: Permalink{TeamDomain: "myteam", ChannelID: "C0123ABC", Timestamp: "1700000000.123456"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePermalink(tt.raw)
+			if err != nil {
+				t.Fatalf("ParsePermalink: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePermalinkInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"not a url",
+		"https://example.com/archives/C0123/p1700000000123456",
+		"https://slack.com/archives/C0123/p1700000000123456",
+		"https://myteam.slack.com/messages/C0123",
+		"https://myteam.slack.com/archives/C0123",
+		"ftp://myteam.slack.com/archives/C0123/p1700000000123456",
+	}
+	for _, raw := range invalid {
+		if IsPermalink(raw) {
+			t.Errorf("IsPermalink(%q) = true, want false", raw)
+		}
+	}
+}
+
+func TestTeamDomainFromURL(t *testing.T) {
+	if got := TeamDomainFromURL("https://myteam.slack.com/"); got != "myteam" {
+		t.Errorf("TeamDomainFromURL = %q, want myteam", got)
+	}
+	if got := TeamDomainFromURL(""); got != "" {
+		t.Errorf("TeamDomainFromURL(\"\") = %q, want empty", got)
+	}
+}
@@ -0,0 +1,125 @@
+package slack
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// conversationIDRe matches channel, group and DM IDs (C…, G…, D…).
+var conversationIDRe = regexp.MustCompile(`^[CGD][A-Z0-9]{6,}$`)
+
+// userIDRe matches user IDs (U… or W… for Enterprise Grid).
+var userIDRe = regexp.MustCompile(`^[UW][A-Z0-9]{6,}$`)
+
+// conversationRef is a parsed reference to a conversation as typed on the
+// command line: "#general", "general", "@alice", "C0123ABC" or "U0123ABC".
+type conversationRef struct {
+	id   string // conversation ID, when given directly
+	user string // user name or ID, for DMs
+	name string // channel name
+}
+
+// parseConversationRef classifies a user-supplied conversation reference.
+func parseConversationRef(ref string) (conversationRef, error) {
+	ref = strings.TrimSpace(ref)
+	switch {
+	case ref == "" || ref == "#" || ref == "@":
+		return conversationRef{}, fmt.Errorf("empty conversation reference")
+	case strings.HasPrefix(ref, "#"):
+		return conversationRef{name: strings.ToLower(ref[1:])}, nil
+	case strings.HasPrefix(ref, "@"):
+		return conversationRef{user: ref[1:]}, nil
+	case conversationIDRe.MatchString(ref):
+		return conversationRef{id: ref}, nil
+	case userIDRe.MatchString(ref):
+		return conversationRef{user: ref}, nil
+	default:
+		return conversationRef{name: strings.ToLower(ref)}, nil
+	}
+}
+
+// GetAllConversations returns every conversation of the given types the
+// user can see, following pagination. Archived conversations are excluded.
+func (c *Client) GetAllConversations(types ...string) ([]slack.Channel, error) {
+	var all []slack.Channel
+	params := &slack.GetConversationsParameters{
+		Types:           types,
+		Limit:           200,
+		ExcludeArchived: true,
+	}
+	for {
+		channels, cursor, err := c.GetConversations(params)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, channels...)
+		if cursor == "" {
+			return all, nil
+		}
+		params.Cursor = cursor
+	}
+}
+
+// ResolveConversation turns a conversation reference ("#general",
+// "general", "@alice", or a channel/user ID) into a conversation ID, opening
+// a DM when the reference names a user.
+func (c *Client) ResolveConversation(ref string) (string, error) {
+	r, err := parseConversationRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case r.id != "":
+		return r.id, nil
+
+	case r.user != "":
+		userID := r.user
+		if !userIDRe.MatchString(userID) {
+			userID, err = c.findUserID(r.user)
+			if err != nil {
+				return "", err
+			}
+		}
+		ch, err := c.OpenConversation([]string{userID})
+		if err != nil {
+			return "", fmt.Errorf("open DM with %s: %w", r.user, err)
+		}
+		return ch.ID, nil
+
+	default:
+		channels, err := c.GetAllConversations("public_channel", "private_channel", "mpim")
+		if err != nil {
+			return "", err
+		}
+		for _, ch := range channels {
+			if strings.ToLower(ch.Name) == r.name {
+				return ch.ID, nil
+			}
+		}
+		return "", fmt.Errorf("channel not found: #%s", r.name)
+	}
+}
+
+// findUserID returns the ID of the user whose handle, display name or real
+// name matches name (case-insensitive).
+func (c *Client) findUserID(name string) (string, error) {
+	users, err := c.GetUsers()
+	if err != nil {
+		return "", err
+	}
+	for _, u := range users {
+		if u.Deleted {
+			continue
+		}
+		if strings.EqualFold(u.Name, name) ||
+			strings.EqualFold(u.Profile.DisplayName, name) ||
+			strings.EqualFold(u.RealName, name) {
+			return u.ID, nil
+		}
+	}
+	return "", fmt.Errorf("user not found: @%s", name)
+}
@@ -0,0 +1,34 @@
+package slack
+
+import "testing"
+
+func TestParseConversationRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want conversationRef
+	}{
+		{"#General", conversationRef{name: "general"}},
+		{"random", conversationRef{name: "random"}},
+		{"@alice", conversationRef{user: "alice"}},
+		{"C0123ABCD", conversationRef{id: "C0123ABCD"}},
+		{"D0123ABCD", conversationRef{id: "D0123ABCD"}},
+		{"U0123ABCD", conversationRef{user: "U0123ABCD"}},
+		{" #dev ", conversationRef{name: "dev"}},
+	}
+	for _, tt := range tests {
+		got, err := parseConversationRef(tt.ref)
+		if err != nil {
+			t.Errorf("parseConversationRef(%q) error: %v", tt.ref, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseConversationRef(%q) = %+v, want %+v", tt.ref, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "#", "@"} {
+		if _, err := parseConversationRef(bad); err == nil {
+			t.Errorf("parseConversationRef(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+const (
+	// rtmPingInterval is how often a ping is sent over an idle RTM socket.
+	// The socket is considered dead after two intervals without a message.
+	rtmPingInterval = 30 * time.Second
+	// rtmWriteTimeout bounds a single websocket write.
+	rtmWriteTimeout = 10 * time.Second
+	// rtmMaxBackoff caps the delay between reconnect attempts.
+	rtmMaxBackoff = time.Minute
+)
+
+// ErrRTMNotConnected is returned when sending over RTM while no RTM
+// connection is open, e.g. when Socket Mode is the active transport.
+var ErrRTMNotConnected = errors.New("rtm: not connected")
+
+// PresenceEvent represents a presence_change event for one user. Presence
+// is "active" or "away".
+type PresenceEvent struct {
+	UserID   string `json:"user"`
+	Presence string `json:"presence"`
+}
+
+// rtmOutgoing is a message sent to Slack over the RTM socket.
+type rtmOutgoing struct {
+	ID      int      `json:"id"`
+	Type    string   `json:"type"`
+	Channel string   `json:"channel,omitempty"`
+	IDs     []string `json:"ids,omitempty"`
+}
+
+// rtmConn is an open RTM websocket. Writes are serialized by mu.
+type rtmConn struct {
+	mu     sync.Mutex
+	ws     *websocket.Conn
+	nextID int
+}
+
+// send writes msg to the socket, assigning it the next message ID.
+func (rc *rtmConn) send(msg rtmOutgoing) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.nextID++
+	msg.ID = rc.nextID
+	rc.ws.SetWriteDeadline(time.Now().Add(rtmWriteTimeout))
+	return rc.ws.WriteJSON(msg)
+}
+
+// keepAlive pings the socket every rtmPingInterval until done is closed,
+// closing the socket when a ping cannot be written.
+func (rc *rtmConn) keepAlive(done <-chan struct{}) {
+	ticker := time.NewTicker(rtmPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := rc.send(rtmOutgoing{Type: "ping"}); err != nil {
+				slog.Debug("rtm ping failed", "error", err)
+				rc.ws.Close()
+				return
+			}
+		}
+	}
+}
+
+// RunRTM connects to the Real Time Messaging API and feeds its events to
+// handler until ctx is cancelled. Unlike Socket Mode, RTM delivers
+// user_typing and presence_change, and lets SendTyping and
+// SubscribePresence talk back to Slack. Dropped connections are retried
+// with backoff; errors reported by the Slack API itself (e.g. a token type
+// that may not use RTM) are returned.
+func (c *Client) RunRTM(ctx context.Context, handler *EventHandler) error {
+	backoff := time.Second
+	for {
+		connected, err := c.runRTMOnce(ctx, handler)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connected {
+			backoff = time.Second
+		}
+		if err != nil {
+			if handler.OnError != nil {
+				handler.OnError(err)
+			}
+			var apiErr slack.SlackErrorResponse
+			if errors.As(err, &apiErr) {
+				return err
+			}
+			slog.Warn("rtm connection failed", "error", err, "retry_in", backoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, rtmMaxBackoff)
+	}
+}
+
+// runRTMOnce opens one RTM connection and reads events until it drops.
+// connected reports whether Slack said hello on it.
+func (c *Client) runRTMOnce(ctx context.Context, handler *EventHandler) (connected bool, err error) {
+	_, wsURL, err := c.API().ConnectRTMContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	ws, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return false, err
+	}
+	defer ws.Close()
+	// Closing the socket is the only way to interrupt ReadMessage.
+	stop := context.AfterFunc(ctx, func() { ws.Close() })
+	defer stop()
+
+	rc := &rtmConn{ws: ws}
+	done := make(chan struct{})
+	defer close(done)
+	go rc.keepAlive(done)
+
+	c.rtmMu.Lock()
+	c.rtm = rc
+	c.rtmMu.Unlock()
+	defer func() {
+		c.rtmMu.Lock()
+		c.rtm = nil
+		c.rtmMu.Unlock()
+	}()
+
+	handlers := rtmHandlers(handler)
+	for {
+		ws.SetReadDeadline(time.Now().Add(2 * rtmPingInterval))
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			if connected {
+				slog.Warn("rtm disconnected", "error", err)
+				if handler.OnDisconnected != nil {
+					handler.OnDisconnected()
+				}
+			}
+			if ctx.Err() != nil {
+				return connected, nil
+			}
+			return connected, err
+		}
+
+		switch typ := dispatchRTM(handlers, data); typ {
+		case "hello":
+			slog.Info("rtm connected")
+			connected = true
+			c.resubscribePresence()
+			if handler.OnConnected != nil {
+				handler.OnConnected()
+			}
+		case "goodbye":
+			// Slack is about to close the socket; reconnect.
+			slog.Info("rtm goodbye received")
+			if handler.OnDisconnected != nil {
+				handler.OnDisconnected()
+			}
+			return connected, nil
+		}
+	}
+}
+
+// dispatchRTM decodes one RTM frame and calls the matching handler. It
+// returns the event type so the caller can handle connection events.
+func dispatchRTM(handlers map[string]func([]byte), data []byte) string {
+	var head struct {
+		Type    string `json:"type"`
+		ReplyTo int    `json:"reply_to"`
+		OK      *bool  `json:"ok"`
+		Error   *struct {
+			Msg string `json:"msg"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		slog.Debug("rtm: undecodable frame", "error", err)
+		return ""
+	}
+	if head.Type == "" {
+		// Replies to our own messages, e.g. {"ok":false,"reply_to":1,...}.
+		if head.OK != nil && !*head.OK && head.Error != nil {
+			slog.Debug("rtm: message rejected", "reply_to", head.ReplyTo, "error", head.Error.Msg)
+		}
+		return ""
+	}
+	if fn, ok := handlers[head.Type]; ok {
+		fn(data)
+	} else {
+		slog.Debug("unhandled rtm event", "type", head.Type)
+	}
+	return head.Type
+}
+
+// rtmHandlers maps RTM event types to the EventHandler callbacks. RTM
+// events share their JSON shape with the Events API, so the same
+// slackevents types are decoded.
+func rtmHandlers(handler *EventHandler) map[string]func([]byte) {
+	return map[string]func([]byte){
+		"hello":   func([]byte) {},
+		"goodbye": func([]byte) {},
+		"pong":    func([]byte) {},
+
+		string(slackevents.Message): rtmTyped(func(msg *slackevents.MessageEvent) {
+			slog.Debug("message event received", "channel", msg.Channel, "user", msg.User, "subtype", msg.SubType)
+			routeMessage(handler, msg)
+		}),
+
+		string(slackevents.ReactionAdded):       rtmTyped(handler.OnReactionAdded),
+		string(slackevents.ReactionRemoved):     rtmTyped(handler.OnReactionRemoved),
+		string(slackevents.ChannelCreated):      rtmTyped(handler.OnChannelCreated),
+		string(slackevents.ChannelArchive):      rtmTyped(handler.OnChannelArchive),
+		string(slackevents.ChannelUnarchive):    rtmTyped(handler.OnChannelUnarchive),
+		string(slackevents.ChannelRename):       rtmTyped(handler.OnChannelRename),
+		string(slackevents.MemberJoinedChannel): rtmTyped(handler.OnMemberJoinedChannel),
+		string(slackevents.MemberLeftChannel):   rtmTyped(handler.OnMemberLeftChannel),
+		string(slackevents.TeamJoin):            rtmTyped(handler.OnTeamJoin),
+		string(slackevents.PinAdded):            rtmTyped(handler.OnPinAdded),
+		string(slackevents.PinRemoved):          rtmTyped(handler.OnPinRemoved),
+		string(slackevents.FileShared):          rtmTyped(handler.OnFileShared),
+		string(slackevents.UserStatusChanged):   rtmTyped(handler.OnUserStatusChanged),
+
+		"user_typing": rtmTyped(handler.OnTyping),
+		"presence_change": func(data []byte) {
+			if handler.OnPresenceChange == nil {
+				return
+			}
+			// Batched presence_sub updates carry "users" instead of "user".
+			var evt struct {
+				User     string   `json:"user"`
+				Users    []string `json:"users"`
+				Presence string   `json:"presence"`
+			}
+			if err := json.Unmarshal(data, &evt); err != nil {
+				slog.Warn("rtm: bad presence_change event", "error", err)
+				return
+			}
+			if evt.User != "" {
+				evt.Users = append(evt.Users, evt.User)
+			}
+			for _, id := range evt.Users {
+				handler.OnPresenceChange(&PresenceEvent{UserID: id, Presence: evt.Presence})
+			}
+		},
+	}
+}
+
+// rtmTyped returns a frame handler that decodes the frame into a T and
+// passes it to callback, if set.
+func rtmTyped[T any](callback func(*T)) func([]byte) {
+	return func(data []byte) {
+		if callback == nil {
+			return
+		}
+		evt := new(T)
+		if err := json.Unmarshal(data, evt); err != nil {
+			slog.Warn("rtm: bad event", "type", fmt.Sprintf("%T", evt), "error", err)
+			return
+		}
+		callback(evt)
+	}
+}
+
+// SendTyping tells the other members of channelID that we are typing. It
+// returns ErrRTMNotConnected unless RunRTM has an open connection.
+func (c *Client) SendTyping(channelID string) error {
+	c.rtmMu.Lock()
+	rc := c.rtm
+	c.rtmMu.Unlock()
+	if rc == nil {
+		return ErrRTMNotConnected
+	}
+	return rc.send(rtmOutgoing{Type: "typing", Channel: channelID})
+}
+
+// SubscribePresence asks Slack for presence_change events about userIDs,
+// replacing any earlier subscription. The list is kept and sent again
+// whenever RunRTM reconnects, so it may be called before the first
+// connection.
+func (c *Client) SubscribePresence(userIDs []string) error {
+	c.rtmMu.Lock()
+	c.presenceIDs = userIDs
+	rc := c.rtm
+	c.rtmMu.Unlock()
+	if rc == nil {
+		return nil
+	}
+	return rc.send(rtmOutgoing{Type: "presence_sub", IDs: userIDs})
+}
+
+// resubscribePresence sends the stored presence subscription on a new
+// connection.
+func (c *Client) resubscribePresence() {
+	c.rtmMu.Lock()
+	ids, rc := c.presenceIDs, c.rtm
+	c.rtmMu.Unlock()
+	if rc == nil || len(ids) == 0 {
+		return
+	}
+	if err := rc.send(rtmOutgoing{Type: "presence_sub", IDs: ids}); err != nil {
+		slog.Warn("rtm presence subscription failed", "error", err)
+	}
+}
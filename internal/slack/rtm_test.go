@@ -0,0 +1,219 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// fakeRTM is a local stand-in for Slack's rtm.connect endpoint and RTM
+// websocket. Each connection is handed to serve.
+type fakeRTM struct {
+	*httptest.Server
+	serve func(conn *websocket.Conn)
+}
+
+func newFakeRTM(t *testing.T, serve func(conn *websocket.Conn)) *fakeRTM {
+	t.Helper()
+	f := &fakeRTM{serve: serve}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rtm.connect", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		wsURL := "ws" + strings.TrimPrefix(f.URL, "http") + "/ws"
+		json.NewEncoder(w).Encode(map[string]any{"ok": true, "url": wsURL})
+	})
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+		f.serve(conn)
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeRTM) client() *Client {
+	return &Client{api: slack.New("xoxp-test", slack.OptionAPIURL(f.URL+"/"))}
+}
+
+// readFrame reads the next client frame that is not a ping.
+func readFrame(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+	for {
+		var msg map[string]any
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Errorf("reading client frame: %v", err)
+			return nil
+		}
+		if msg["type"] != "ping" {
+			return msg
+		}
+	}
+}
+
+func TestRunRTMDeliversEvents(t *testing.T) {
+	frames := make(chan map[string]any, 4)
+	srv := newFakeRTM(t, func(conn *websocket.Conn) {
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"hello"}`))
+		frames <- readFrame(t, conn) // presence_sub sent on hello
+		for _, evt := range []string{
+			`{"type":"user_typing","channel":"C1","user":"U2"}`,
+			`{"type":"presence_change","users":["U2","U3"],"presence":"away"}`,
+			`{"type":"presence_change","user":"U4","presence":"active"}`,
+			`{"type":"message","channel":"C1","user":"U2","text":"hi","ts":"1.000001"}`,
+			`{"type":"message","subtype":"message_deleted","channel":"C1","deleted_ts":"1.000001"}`,
+			`{"type":"reaction_added","user":"U2","reaction":"tada","item":{"type":"message","channel":"C1","ts":"1.000001"}}`,
+			`{"ok":true,"reply_to":1,"ts":"1.000002"}`,
+			`{"type":"something_new"}`,
+		} {
+			conn.WriteMessage(websocket.TextMessage, []byte(evt))
+		}
+		frames <- readFrame(t, conn) // typing
+		conn.ReadMessage()           // block until the client goes away
+	})
+
+	var mu sync.Mutex
+	var got []string
+	record := func(s string) {
+		mu.Lock()
+		got = append(got, s)
+		mu.Unlock()
+	}
+	reacted := make(chan struct{})
+	handler := &EventHandler{
+		OnConnected: func() { record("connected") },
+		OnTyping:    func(e *TypingEvent) { record("typing " + e.ChannelID + " " + e.UserID) },
+		OnPresenceChange: func(e *PresenceEvent) {
+			record("presence " + e.UserID + " " + e.Presence)
+		},
+		OnMessage:        func(e *slackevents.MessageEvent) { record("message " + e.Text) },
+		OnMessageDeleted: func(e *slackevents.MessageEvent) { record("deleted " + e.DeletedTimeStamp) },
+		OnReactionAdded: func(e *slackevents.ReactionAddedEvent) {
+			record("reaction " + e.Reaction + " " + e.Item.Timestamp)
+			close(reacted)
+		},
+		OnError: func(err error) { t.Errorf("OnError(%v)", err) },
+	}
+
+	c := srv.client()
+	if err := c.SubscribePresence([]string{"U2", "U3", "U4"}); err != nil {
+		t.Fatalf("SubscribePresence before connecting: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- c.RunRTM(ctx, handler) }()
+
+	sub := <-frames
+	if sub["type"] != "presence_sub" || len(sub["ids"].([]any)) != 3 {
+		t.Errorf("first frame = %v, want presence_sub for 3 users", sub)
+	}
+
+	select {
+	case <-reacted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("events not delivered")
+	}
+	if err := c.SendTyping("C1"); err != nil {
+		t.Fatalf("SendTyping: %v", err)
+	}
+	if typing := <-frames; typing["type"] != "typing" || typing["channel"] != "C1" {
+		t.Errorf("typing frame = %v", typing)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("RunRTM after cancel = %v, want nil", err)
+	}
+
+	want := []string{
+		"connected",
+		"typing C1 U2",
+		"presence U2 away",
+		"presence U3 away",
+		"presence U4 active",
+		"message hi",
+		"deleted 1.000001",
+		"reaction tada 1.000001",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("events =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if err := c.SendTyping("C1"); !errors.Is(err, ErrRTMNotConnected) {
+		t.Errorf("SendTyping after disconnect = %v, want ErrRTMNotConnected", err)
+	}
+}
+
+func TestRunRTMReconnectsAfterGoodbye(t *testing.T) {
+	var mu sync.Mutex
+	conns := 0
+	srv := newFakeRTM(t, func(conn *websocket.Conn) {
+		mu.Lock()
+		conns++
+		n := conns
+		mu.Unlock()
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"hello"}`))
+		if n == 1 {
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"goodbye"}`))
+		}
+		conn.ReadMessage()
+	})
+
+	events := make(chan string, 8)
+	handler := &EventHandler{
+		OnConnected:    func() { events <- "connected" },
+		OnDisconnected: func() { events <- "disconnected" },
+		OnError:        func(err error) { t.Errorf("OnError(%v)", err) },
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.client().RunRTM(ctx, handler)
+
+	for _, want := range []string{"connected", "disconnected", "connected"} {
+		select {
+		case got := <-events:
+			if got != want {
+				t.Fatalf("event = %q, want %q", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+}
+
+func TestRunRTMReturnsAPIErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":false,"error":"not_allowed_token_type"}`))
+	}))
+	defer srv.Close()
+
+	var gotErr error
+	handler := &EventHandler{OnError: func(err error) { gotErr = err }}
+	c := &Client{api: slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))}
+
+	err := c.RunRTM(context.Background(), handler)
+	if err == nil || !strings.Contains(err.Error(), "not_allowed_token_type") {
+		t.Fatalf("RunRTM = %v, want not_allowed_token_type", err)
+	}
+	if gotErr == nil {
+		t.Error("OnError not called")
+	}
+}
@@ -0,0 +1,278 @@
+// Package status publishes a per-workspace summary of unread messages,
+// mentions and connection state as JSON files, for status bars such as
+// tmux, waybar and polybar.
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m96-chan/Slacko/internal/consts"
+)
+
+// Connection states.
+const (
+	StateConnecting   = "connecting"
+	StateConnected    = "connected"
+	StateDisconnected = "disconnected"
+	StateError        = "error"
+	// StateOffline is reported when no Slacko instance is running.
+	StateOffline = "offline"
+)
+
+// Summary is the status of one workspace.
+type Summary struct {
+	TeamID    string    `json:"team_id"`
+	Team      string    `json:"team"`
+	State     string    `json:"state"`
+	Unread    int       `json:"unread"`
+	Mentions  int       `json:"mentions"`
+	PID       int       `json:"pid"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Dir returns the directory holding the status files.
+func Dir() string {
+	return filepath.Join(consts.RuntimeDir(), "status")
+}
+
+// Path returns the status file of a workspace.
+func Path(teamID string) string {
+	return filepath.Join(Dir(), teamID+".json")
+}
+
+// Write atomically replaces the status file of s.TeamID, so readers never
+// see a partially written file.
+func Write(s Summary) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := Path(s.TeamID)
+	if err := consts.MkdirPrivate(consts.RuntimeDir()); err != nil {
+		return err
+	}
+	if err := consts.MkdirPrivate(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".status-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ReadAll returns the summaries of all workspaces with a status file,
+// sorted by team name. A missing directory yields no summaries. Files left
+// behind by an instance that is no longer running are skipped and removed.
+func ReadAll() ([]Summary, error) {
+	if _, err := os.Lstat(Dir()); err == nil {
+		// Don't trust files in a directory someone else controls.
+		if err := consts.MkdirPrivate(consts.RuntimeDir()); err != nil {
+			return nil, err
+		}
+		if err := consts.MkdirPrivate(Dir()); err != nil {
+			return nil, err
+		}
+	}
+	paths, err := filepath.Glob(filepath.Join(Dir(), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var all []Summary
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue // removed since the glob
+			}
+			return nil, err
+		}
+		var s Summary
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if s.PID > 0 && !processAlive(s.PID) {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				slog.Debug("failed to remove stale status file", "path", path, "error", err)
+			}
+			continue
+		}
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Team < all[j].Team })
+	return all, nil
+}
+
+// Total combines summaries into one. The state is the best state of any
+// workspace, or StateOffline when there are none.
+func Total(summaries []Summary) Summary {
+	total := Summary{State: StateOffline}
+	var teams []string
+	for _, s := range summaries {
+		total.Unread += s.Unread
+		total.Mentions += s.Mentions
+		teams = append(teams, s.Team)
+		if stateRank(s.State) > stateRank(total.State) {
+			total.State = s.State
+		}
+		if s.UpdatedAt.After(total.UpdatedAt) {
+			total.UpdatedAt = s.UpdatedAt
+		}
+	}
+	total.Team = strings.Join(teams, ",")
+	if len(summaries) == 1 {
+		total.TeamID = summaries[0].TeamID
+		total.PID = summaries[0].PID
+	}
+	return total
+}
+
+// stateRank orders states from worst to best for Total.
+func stateRank(state string) int {
+	switch state {
+	case StateConnected:
+		return 4
+	case StateConnecting:
+		return 3
+	case StateError:
+		return 2
+	case StateDisconnected:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Format expands the placeholders {unread}, {mentions}, {state}, {team}
+// and {team_id} in format with the values of s.
+func Format(format string, s Summary) string {
+	return strings.NewReplacer(
+		"{unread}", strconv.Itoa(s.Unread),
+		"{mentions}", strconv.Itoa(s.Mentions),
+		"{state}", s.State,
+		"{team}", s.Team,
+		"{team_id}", s.TeamID,
+	).Replace(format)
+}
+
+// Publisher keeps the status file of one workspace up to date. Updates are
+// written by Run in the background, so callers on the UI thread never wait
+// for the file system. A nil Publisher ignores updates.
+type Publisher struct {
+	mu      sync.Mutex
+	summary Summary
+	changed chan struct{}
+}
+
+// NewPublisher creates a Publisher for a workspace in StateConnecting.
+func NewPublisher(teamID, team string) *Publisher {
+	return &Publisher{
+		summary: Summary{TeamID: teamID, Team: team, State: StateConnecting, PID: os.Getpid()},
+		changed: make(chan struct{}, 1),
+	}
+}
+
+// SetState records the connection state.
+func (p *Publisher) SetState(state string) {
+	p.update(func(s *Summary) { s.State = state })
+}
+
+// SetCounts records the unread message and mention counts.
+func (p *Publisher) SetCounts(unread, mentions int) {
+	p.update(func(s *Summary) {
+		s.Unread = unread
+		s.Mentions = mentions
+	})
+}
+
+// Summary returns the current summary.
+func (p *Publisher) Summary() Summary {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.summary
+}
+
+// update applies fn and wakes Run when the summary changed.
+func (p *Publisher) update(fn func(*Summary)) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	before := p.summary
+	fn(&p.summary)
+	changed := p.summary != before
+	p.mu.Unlock()
+
+	if changed {
+		select {
+		case p.changed <- struct{}{}:
+		default: // a write is already pending
+		}
+	}
+}
+
+// Run writes the status file now and after every change until ctx is done,
+// then removes it.
+func (p *Publisher) Run(ctx context.Context) {
+	p.write()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := removeOwn(Path(p.Summary().TeamID)); err != nil {
+				slog.Warn("failed to remove status file", "error", err)
+			}
+			return
+		case <-p.changed:
+			p.write()
+		}
+	}
+}
+
+// removeOwn removes the status file at path if this process wrote it. A
+// second instance for the same workspace may have replaced it since.
+func removeOwn(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var s Summary
+	if err := json.Unmarshal(data, &s); err != nil || s.PID != os.Getpid() {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// write writes the current summary to the status file.
+func (p *Publisher) write() {
+	s := p.Summary()
+	s.UpdatedAt = time.Now()
+	if err := Write(s); err != nil {
+		slog.Warn("failed to write status file", "path", Path(s.TeamID), "error", err)
+	}
+}
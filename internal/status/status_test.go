@@ -0,0 +1,153 @@
+package status
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func useTempRuntimeDir(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+}
+
+func TestWriteReadAll(t *testing.T) {
+	useTempRuntimeDir(t)
+
+	if all, err := ReadAll(); err != nil || len(all) != 0 {
+		t.Fatalf("ReadAll before any write = %v, %v", all, err)
+	}
+
+	for _, s := range []Summary{
+		{TeamID: "T2", Team: "Work", State: StateConnected, Unread: 3, Mentions: 1},
+		{TeamID: "T1", Team: "Home", State: StateDisconnected, Unread: 2},
+	} {
+		if err := Write(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].Team != "Home" || all[1].Unread != 3 {
+		t.Errorf("ReadAll = %+v", all)
+	}
+
+	entries, _ := os.ReadDir(Dir())
+	if len(entries) != 2 {
+		t.Errorf("status dir has %d entries, want no temp files left", len(entries))
+	}
+}
+
+func TestReadAllSkipsStaleFiles(t *testing.T) {
+	useTempRuntimeDir(t)
+
+	// A process that has exited leaves a PID nobody runs under.
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []Summary{
+		{TeamID: "T1", Team: "Home", State: StateConnected, PID: os.Getpid()},
+		{TeamID: "T2", Team: "Work", State: StateConnected, Unread: 4, PID: cmd.Process.Pid},
+	} {
+		if err := Write(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].TeamID != "T1" {
+		t.Errorf("ReadAll = %+v, want only the running instance", all)
+	}
+	if _, err := os.Stat(Path("T2")); !os.IsNotExist(err) {
+		t.Errorf("stale status file not removed: %v", err)
+	}
+}
+
+func TestTotalAndFormat(t *testing.T) {
+	total := Total([]Summary{
+		{TeamID: "T1", Team: "Home", State: StateDisconnected, Unread: 2},
+		{TeamID: "T2", Team: "Work", State: StateConnected, Unread: 3, Mentions: 1},
+	})
+	if got := Format("{mentions}/{unread} {state} {team}", total); got != "1/5 connected Home,Work" {
+		t.Errorf("Format = %q", got)
+	}
+
+	if got := Format("{state}", Total(nil)); got != StateOffline {
+		t.Errorf("state with no workspaces = %q, want %q", got, StateOffline)
+	}
+}
+
+func TestPublisherRun(t *testing.T) {
+	useTempRuntimeDir(t)
+
+	p := NewPublisher("T1", "Home")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+
+	p.SetState(StateConnected)
+	p.SetCounts(4, 2)
+	waitFor(t, func(s Summary) bool { return s.State == StateConnected && s.Unread == 4 && s.Mentions == 2 })
+
+	cancel()
+	<-done
+	if _, err := os.Stat(Path("T1")); !os.IsNotExist(err) {
+		t.Errorf("status file still exists after Run returned: %v", err)
+	}
+}
+
+func TestNilPublisherIgnoresUpdates(t *testing.T) {
+	var p *Publisher
+	p.SetState(StateConnected)
+	p.SetCounts(1, 1)
+}
+
+// waitFor polls the T1 status file until ok accepts it.
+func waitFor(t *testing.T, ok func(Summary) bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if all, err := ReadAll(); err == nil && len(all) == 1 && ok(all[0]) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	all, _ := ReadAll()
+	t.Fatalf("status file never matched; last read %+v", all)
+}
+
+func TestPublisherRunKeepsOtherInstanceFile(t *testing.T) {
+	useTempRuntimeDir(t)
+
+	p := NewPublisher("T1", "Home")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+	waitFor(t, func(s Summary) bool { return s.PID == os.Getpid() })
+
+	// Another instance for the same workspace replaces the file.
+	if err := Write(Summary{TeamID: "T1", Team: "Home", State: StateConnected, PID: os.Getpid() + 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	<-done
+	if _, err := os.Stat(Path("T1")); err != nil {
+		t.Errorf("status file of the other instance was removed: %v", err)
+	}
+}
@@ -0,0 +1,277 @@
+package chat
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/m96-chan/Slacko/internal/config"
+	"github.com/m96-chan/Slacko/internal/ui/keys"
+)
+
+// ActivityKind identifies why an item appears in the activity inbox.
+type ActivityKind int
+
+const (
+	ActivityMention      ActivityKind = iota // direct <@me> mention
+	ActivityGroupMention                     // user group (subteam) mention
+	ActivityBroadcast                        // @here / @channel / @everyone
+	ActivityReaction                         // reaction added to one of our messages
+)
+
+// ActivityEntry holds a single item in the activity inbox.
+type ActivityEntry struct {
+	Kind        ActivityKind
+	ChannelID   string
+	ChannelName string // display label, e.g. "#general" or a DM partner's name
+	UserName    string
+	Timestamp   string // timestamp of the referenced message
+	ThreadTS    string // parent thread timestamp, if the message is a reply
+	Text        string
+	Reaction    string // emoji name for ActivityReaction
+	EventTS     string // when the activity happened (defaults to Timestamp)
+}
+
+// Key returns a stable identifier used for de-duplication and read tracking.
+func (e ActivityEntry) Key() string {
+	if e.Kind == ActivityReaction {
+		return fmt.Sprintf("%s/%s/%s/%s", e.ChannelID, e.Timestamp, e.Reaction, e.UserName)
+	}
+	return e.ChannelID + "/" + e.Timestamp
+}
+
+// sortTS returns the timestamp used to order entries in the inbox.
+func (e ActivityEntry) sortTS() time.Time {
+	if e.EventTS != "" {
+		return parseSlackTimestamp(e.EventTS)
+	}
+	return parseSlackTimestamp(e.Timestamp)
+}
+
+// ActivityPanel is a modal popup listing mentions and reactions, newest first.
+// Entries accumulate across openings; read state is tracked locally.
+type ActivityPanel struct {
+	*tview.Flex
+	cfg      *config.Config
+	list     *tview.List
+	status   *tview.TextView
+	entries  []ActivityEntry
+	read     map[string]bool
+	onSelect func(entry ActivityEntry)
+	onClose  func()
+}
+
+// NewActivityPanel creates a new activity inbox component.
+func NewActivityPanel(cfg *config.Config) *ActivityPanel {
+	ap := &ActivityPanel{
+		cfg:  cfg,
+		read: make(map[string]bool),
+	}
+
+	ap.list = tview.NewList()
+	ap.list.SetHighlightFullLine(true)
+	ap.list.ShowSecondaryText(true)
+	ap.list.SetWrapAround(false)
+	ap.list.SetSecondaryTextColor(cfg.Theme.Modal.SecondaryText.Foreground())
+	ap.list.SetInputCapture(ap.handleInput)
+
+	ap.status = tview.NewTextView()
+	ap.status.SetTextAlign(tview.AlignLeft)
+	ap.status.SetDynamicColors(true)
+
+	ap.Flex = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(ap.list, 0, 1, true).
+		AddItem(ap.status, 1, 0, false)
+	ap.SetBorder(true).SetTitle(" Activity ")
+	ap.SetInputCapture(ap.handleInput)
+
+	return ap
+}
+
+// SetOnSelect sets the callback for opening an activity item.
+func (ap *ActivityPanel) SetOnSelect(fn func(entry ActivityEntry)) {
+	ap.onSelect = fn
+}
+
+// SetOnClose sets the callback for closing the panel.
+func (ap *ActivityPanel) SetOnClose(fn func()) {
+	ap.onClose = fn
+}
+
+// Reset clears the status text. Entries and read state are kept so that
+// live events collected while the panel was closed remain visible.
+func (ap *ActivityPanel) Reset() {
+	ap.status.SetText("")
+	ap.render()
+}
+
+// AddEntries merges entries into the inbox, skipping duplicates, and
+// re-sorts by time (newest first).
+func (ap *ActivityPanel) AddEntries(entries []ActivityEntry) {
+	seen := make(map[string]bool, len(ap.entries))
+	for _, e := range ap.entries {
+		seen[e.Key()] = true
+	}
+	for _, e := range entries {
+		if seen[e.Key()] {
+			continue
+		}
+		seen[e.Key()] = true
+		ap.entries = append(ap.entries, e)
+	}
+	sort.SliceStable(ap.entries, func(i, j int) bool {
+		return ap.entries[i].sortTS().After(ap.entries[j].sortTS())
+	})
+	ap.render()
+}
+
+// UnreadCount returns the number of entries not yet marked as read.
+func (ap *ActivityPanel) UnreadCount() int {
+	n := 0
+	for _, e := range ap.entries {
+		if !ap.read[e.Key()] {
+			n++
+		}
+	}
+	return n
+}
+
+// SetStatus updates the status text at the bottom of the panel.
+func (ap *ActivityPanel) SetStatus(text string) {
+	ap.status.SetText(" " + text)
+}
+
+// render rebuilds the list from entries, preserving the current selection.
+func (ap *ActivityPanel) render() {
+	cur := ap.list.GetCurrentItem()
+	ap.list.Clear()
+	for _, e := range ap.entries {
+		ap.list.AddItem(ap.formatMain(e), truncateText(e.Text, 70), 0, nil)
+	}
+	if n := ap.list.GetItemCount(); n > 0 {
+		if cur < 0 || cur >= n {
+			cur = 0
+		}
+		ap.list.SetCurrentItem(cur)
+	}
+}
+
+// formatMain builds the primary line for an entry.
+func (ap *ActivityPanel) formatMain(e ActivityEntry) string {
+	marker := "  "
+	if !ap.read[e.Key()] {
+		marker = "● "
+	}
+
+	where := e.ChannelName
+	if where == "" {
+		where = "a conversation"
+	}
+
+	var what string
+	switch e.Kind {
+	case ActivityReaction:
+		what = fmt.Sprintf("@%s reacted :%s: to your message in %s", e.UserName, e.Reaction, where)
+	case ActivityGroupMention:
+		what = fmt.Sprintf("@%s mentioned your group in %s", e.UserName, where)
+	case ActivityBroadcast:
+		what = fmt.Sprintf("@%s notified the channel in %s", e.UserName, where)
+	default:
+		what = fmt.Sprintf("@%s mentioned you in %s", e.UserName, where)
+	}
+
+	timeStr := ""
+	if t := e.sortTS(); !t.IsZero() {
+		timeStr = "  " + t.Format(time.DateTime)
+	}
+	return marker + what + timeStr
+}
+
+// toggleRead flips the read state of the highlighted entry.
+func (ap *ActivityPanel) toggleRead() {
+	cur := ap.list.GetCurrentItem()
+	if cur < 0 || cur >= len(ap.entries) {
+		return
+	}
+	key := ap.entries[cur].Key()
+	ap.read[key] = !ap.read[key]
+	ap.render()
+}
+
+// markAllRead marks every entry as read.
+func (ap *ActivityPanel) markAllRead() {
+	for _, e := range ap.entries {
+		ap.read[e.Key()] = true
+	}
+	ap.render()
+}
+
+// handleInput processes keybindings for the activity panel.
+func (ap *ActivityPanel) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	name := keys.Normalize(event.Name())
+
+	switch {
+	case ap.cfg.Keybinds.ActivityPanel.Close.Matches(name):
+		ap.close()
+		return nil
+
+	case ap.cfg.Keybinds.ActivityPanel.Select.Matches(name):
+		ap.selectCurrent()
+		return nil
+
+	case ap.cfg.Keybinds.ActivityPanel.ToggleRead.Matches(name):
+		ap.toggleRead()
+		return nil
+
+	case ap.cfg.Keybinds.ActivityPanel.MarkAllRead.Matches(name):
+		ap.markAllRead()
+		return nil
+
+	case ap.cfg.Keybinds.ActivityPanel.Up.Matches(name) || event.Key() == tcell.KeyUp:
+		cur := ap.list.GetCurrentItem()
+		if cur > 0 {
+			ap.list.SetCurrentItem(cur - 1)
+		}
+		return nil
+
+	case ap.cfg.Keybinds.ActivityPanel.Down.Matches(name) || event.Key() == tcell.KeyDown:
+		cur := ap.list.GetCurrentItem()
+		if cur < ap.list.GetItemCount()-1 {
+			ap.list.SetCurrentItem(cur + 1)
+		}
+		return nil
+
+	case ap.cfg.Keybinds.Activity.Matches(name):
+		// Toggle: pressing the keybind again closes the panel.
+		ap.close()
+		return nil
+	}
+
+	return event
+}
+
+// selectCurrent marks the highlighted entry as read and opens it.
+func (ap *ActivityPanel) selectCurrent() {
+	cur := ap.list.GetCurrentItem()
+	if cur < 0 || cur >= len(ap.entries) {
+		return
+	}
+
+	entry := ap.entries[cur]
+	ap.read[entry.Key()] = true
+	ap.render()
+	if ap.onSelect != nil {
+		ap.onSelect(entry)
+	}
+	ap.close()
+}
+
+// close signals the panel should be hidden.
+func (ap *ActivityPanel) close() {
+	if ap.onClose != nil {
+		ap.onClose()
+	}
+}
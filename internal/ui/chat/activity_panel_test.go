@@ -0,0 +1,133 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/m96-chan/Slacko/internal/config"
+)
+
+func TestNewActivityPanel(t *testing.T) {
+	ap := NewActivityPanel(&config.Config{})
+	if ap == nil {
+		t.Fatal("NewActivityPanel returned nil")
+	}
+}
+
+func TestActivityPanelAddEntriesSortsNewestFirst(t *testing.T) {
+	ap := NewActivityPanel(&config.Config{})
+	ap.AddEntries([]ActivityEntry{
+		{Kind: ActivityMention, ChannelID: "C1", Timestamp: "1700000000.000000", UserName: "alice"},
+		{Kind: ActivityMention, ChannelID: "C1", Timestamp: "1700000200.000000", UserName: "bob"},
+	})
+	ap.AddEntries([]ActivityEntry{
+		{Kind: ActivityReaction, ChannelID: "C2", Timestamp: "1690000000.000000", EventTS: "1700000100.000000", Reaction: "+1", UserName: "carol"},
+	})
+
+	if ap.list.GetItemCount() != 3 {
+		t.Fatalf("list count = %d, want 3", ap.list.GetItemCount())
+	}
+	want := []string{"bob", "carol", "alice"}
+	for i, name := range want {
+		if ap.entries[i].UserName != name {
+			t.Errorf("entries[%d] = %q, want %q", i, ap.entries[i].UserName, name)
+		}
+	}
+}
+
+func TestActivityPanelAddEntriesDeduplicates(t *testing.T) {
+	ap := NewActivityPanel(&config.Config{})
+	e := ActivityEntry{Kind: ActivityMention, ChannelID: "C1", Timestamp: "1700000000.000000", UserName: "alice"}
+	ap.AddEntries([]ActivityEntry{e})
+	ap.AddEntries([]ActivityEntry{e})
+
+	if len(ap.entries) != 1 {
+		t.Errorf("entries len = %d, want 1", len(ap.entries))
+	}
+}
+
+func TestActivityPanelReadTracking(t *testing.T) {
+	ap := NewActivityPanel(&config.Config{})
+	ap.AddEntries([]ActivityEntry{
+		{Kind: ActivityMention, ChannelID: "C1", Timestamp: "1700000000.000000", UserName: "alice"},
+		{Kind: ActivityBroadcast, ChannelID: "C2", Timestamp: "1700000100.000000", UserName: "bob"},
+	})
+	if ap.UnreadCount() != 2 {
+		t.Fatalf("UnreadCount = %d, want 2", ap.UnreadCount())
+	}
+
+	ap.list.SetCurrentItem(0)
+	ap.toggleRead()
+	if ap.UnreadCount() != 1 {
+		t.Errorf("after toggle UnreadCount = %d, want 1", ap.UnreadCount())
+	}
+	main, _ := ap.list.GetItemText(0)
+	if strings.HasPrefix(main, "●") {
+		t.Errorf("read entry should not have unread marker: %q", main)
+	}
+
+	ap.markAllRead()
+	if ap.UnreadCount() != 0 {
+		t.Errorf("after mark all UnreadCount = %d, want 0", ap.UnreadCount())
+	}
+}
+
+func TestActivityPanelResetKeepsEntries(t *testing.T) {
+	ap := NewActivityPanel(&config.Config{})
+	ap.AddEntries([]ActivityEntry{
+		{Kind: ActivityMention, ChannelID: "C1", Timestamp: "1700000000.000000", UserName: "alice"},
+	})
+	ap.SetStatus("1 unread")
+	ap.Reset()
+
+	if ap.list.GetItemCount() != 1 {
+		t.Errorf("list count = %d, want 1", ap.list.GetItemCount())
+	}
+	if got := ap.status.GetText(false); got != "" {
+		t.Errorf("status = %q, want empty", got)
+	}
+}
+
+func TestActivityPanelSelectMarksRead(t *testing.T) {
+	ap := NewActivityPanel(&config.Config{})
+	ap.AddEntries([]ActivityEntry{
+		{Kind: ActivityMention, ChannelID: "C1", Timestamp: "1700000000.000000", ThreadTS: "1699999999.000000", UserName: "alice"},
+	})
+
+	var got ActivityEntry
+	closed := false
+	ap.SetOnSelect(func(e ActivityEntry) { got = e })
+	ap.SetOnClose(func() { closed = true })
+
+	ap.selectCurrent()
+	if got.ChannelID != "C1" || got.ThreadTS != "1699999999.000000" {
+		t.Errorf("onSelect got %+v", got)
+	}
+	if !closed {
+		t.Error("onClose not called")
+	}
+	if ap.UnreadCount() != 0 {
+		t.Errorf("UnreadCount = %d, want 0", ap.UnreadCount())
+	}
+}
+
+func TestActivityPanelFormatMain(t *testing.T) {
+	ap := NewActivityPanel(&config.Config{})
+	tests := []struct {
+		entry ActivityEntry
+		want  string
+	}{
+		{ActivityEntry{Kind: ActivityMention, UserName: "alice", ChannelName: "#general"}, "@alice mentioned you in #general"},
+		{ActivityEntry{Kind: ActivityGroupMention, UserName: "alice", ChannelName: "#general"}, "@alice mentioned your group in #general"},
+		{ActivityEntry{Kind: ActivityBroadcast, UserName: "alice", ChannelName: "#general"}, "@alice notified the channel in #general"},
+		{ActivityEntry{Kind: ActivityReaction, UserName: "bob", ChannelName: "#dev", Reaction: "tada"}, "@bob reacted :tada: to your message in #dev"},
+		{ActivityEntry{Kind: ActivityReaction, UserName: "bob", ChannelName: "alice", Reaction: "tada"}, "to your message in alice"},
+		{ActivityEntry{Kind: ActivityMention, UserName: "alice"}, "@alice mentioned you in a conversation"},
+	}
+	for _, tt := range tests {
+		got := ap.formatMain(tt.entry)
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("formatMain(%v) = %q, want to contain %q", tt.entry.Kind, got, tt.want)
+		}
+	}
+}
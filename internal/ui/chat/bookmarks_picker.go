@@ -100,22 +100,22 @@ func (bp *BookmarksPicker) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
 	switch {
-	case name == bp.cfg.Keybinds.BookmarksPicker.Close:
+	case bp.cfg.Keybinds.BookmarksPicker.Close.Matches(name):
 		bp.close()
 		return nil
 
-	case name == bp.cfg.Keybinds.BookmarksPicker.Select:
+	case bp.cfg.Keybinds.BookmarksPicker.Select.Matches(name):
 		bp.selectCurrent()
 		return nil
 
-	case name == bp.cfg.Keybinds.BookmarksPicker.Up || event.Key() == tcell.KeyUp:
+	case bp.cfg.Keybinds.BookmarksPicker.Up.Matches(name) || event.Key() == tcell.KeyUp:
 		cur := bp.list.GetCurrentItem()
 		if cur > 0 {
 			bp.list.SetCurrentItem(cur - 1)
 		}
 		return nil
 
-	case name == bp.cfg.Keybinds.BookmarksPicker.Down || event.Key() == tcell.KeyDown:
+	case bp.cfg.Keybinds.BookmarksPicker.Down.Matches(name) || event.Key() == tcell.KeyDown:
 		cur := bp.list.GetCurrentItem()
 		if cur < bp.list.GetItemCount()-1 {
 			bp.list.SetCurrentItem(cur + 1)
@@ -165,29 +165,29 @@ func (ci *ChannelInfoPanel) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
 	switch {
-	case name == ci.cfg.Keybinds.ChannelInfoPanel.Close:
+	case ci.cfg.Keybinds.ChannelInfoPanel.Close.Matches(name):
 		ci.close()
 		return nil
 
-	case name == ci.cfg.Keybinds.ChannelInfoPanel.SetTopic:
+	case ci.cfg.Keybinds.ChannelInfoPanel.SetTopic.Matches(name):
 		if ci.onSetTopic != nil && ci.data.ChannelID != "" {
 			ci.onSetTopic(ci.data.ChannelID)
 		}
 		return nil
 
-	case name == ci.cfg.Keybinds.ChannelInfoPanel.SetPurpose:
+	case ci.cfg.Keybinds.ChannelInfoPanel.SetPurpose.Matches(name):
 		if ci.onSetPurpose != nil && ci.data.ChannelID != "" {
 			ci.onSetPurpose(ci.data.ChannelID)
 		}
 		return nil
 
-	case name == ci.cfg.Keybinds.ChannelInfoPanel.Leave:
+	case ci.cfg.Keybinds.ChannelInfoPanel.Leave.Matches(name):
 		if ci.onLeave != nil && ci.data.ChannelID != "" {
 			ci.onLeave(ci.data.ChannelID)
 		}
 		return nil
 
-	case name == ci.cfg.Keybinds.ChannelInfo:
+	case ci.cfg.Keybinds.ChannelInfo.Matches(name):
 		// Toggle: pressing Ctrl+O again closes the panel.
 		ci.close()
 		return nil
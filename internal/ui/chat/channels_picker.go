@@ -94,29 +94,29 @@ func (cp *ChannelsPicker) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
 	switch {
-	case name == cp.cfg.Keybinds.ChannelsPicker.Close:
+	case cp.cfg.Keybinds.ChannelsPicker.Close.Matches(name):
 		cp.close()
 		return nil
 
-	case name == cp.cfg.Keybinds.ChannelsPicker.Select:
+	case cp.cfg.Keybinds.ChannelsPicker.Select.Matches(name):
 		cp.selectCurrent()
 		return nil
 
-	case name == cp.cfg.Keybinds.ChannelsPicker.Up || event.Key() == tcell.KeyUp:
+	case cp.cfg.Keybinds.ChannelsPicker.Up.Matches(name) || event.Key() == tcell.KeyUp:
 		cur := cp.list.GetCurrentItem()
 		if cur > 0 {
 			cp.list.SetCurrentItem(cur - 1)
 		}
 		return nil
 
-	case name == cp.cfg.Keybinds.ChannelsPicker.Down || event.Key() == tcell.KeyDown:
+	case cp.cfg.Keybinds.ChannelsPicker.Down.Matches(name) || event.Key() == tcell.KeyDown:
 		cur := cp.list.GetCurrentItem()
 		if cur < cp.list.GetItemCount()-1 {
 			cp.list.SetCurrentItem(cur + 1)
 		}
 		return nil
 
-	case name == cp.cfg.Keybinds.ChannelPicker:
+	case cp.cfg.Keybinds.ChannelPicker.Matches(name):
 		// Ctrl+K while picker is open → close it.
 		cp.close()
 		return nil
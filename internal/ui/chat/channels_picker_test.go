@@ -10,11 +10,11 @@ import (
 
 func newTestPicker() *ChannelsPicker {
 	cfg := &config.Config{}
-	cfg.Keybinds.ChannelsPicker.Close = "Escape"
-	cfg.Keybinds.ChannelsPicker.Up = "Ctrl+P"
-	cfg.Keybinds.ChannelsPicker.Down = "Ctrl+N"
-	cfg.Keybinds.ChannelsPicker.Select = "Enter"
-	cfg.Keybinds.ChannelPicker = "Ctrl+K"
+	cfg.Keybinds.ChannelsPicker.Close = config.Keybind{"Escape"}
+	cfg.Keybinds.ChannelsPicker.Up = config.Keybind{"Ctrl+P"}
+	cfg.Keybinds.ChannelsPicker.Down = config.Keybind{"Ctrl+N"}
+	cfg.Keybinds.ChannelsPicker.Select = config.Keybind{"Enter"}
+	cfg.Keybinds.ChannelPicker = config.Keybind{"Ctrl+K"}
 	return NewChannelsPicker(cfg)
 }
 
@@ -46,21 +46,24 @@ type ChannelsTree struct {
 	nodeIndex       map[string]*tview.TreeNode // channelID → node
 	channelIDs      map[*tview.TreeNode]string // node → channelID (reverse)
 	unreadCounts    map[string]int             // channelID → unread count
+	mentionCounts   map[string]int             // channelID → unread mentions
 	mutedSet        map[string]bool            // channelID → muted state
 	onSelected      OnChannelSelectedFunc
 	onCopyChannelID OnCopyChannelIDFunc
+	onUnreadChanged func()
 }
 
 // NewChannelsTree creates a tree with four section headers.
 func NewChannelsTree(cfg *config.Config, onSelected OnChannelSelectedFunc) *ChannelsTree {
 	ct := &ChannelsTree{
-		TreeView:     tview.NewTreeView(),
-		cfg:          cfg,
-		nodeIndex:    make(map[string]*tview.TreeNode),
-		channelIDs:   make(map[*tview.TreeNode]string),
-		unreadCounts: make(map[string]int),
-		mutedSet:     make(map[string]bool),
-		onSelected:   onSelected,
+		TreeView:      tview.NewTreeView(),
+		cfg:           cfg,
+		nodeIndex:     make(map[string]*tview.TreeNode),
+		channelIDs:    make(map[*tview.TreeNode]string),
+		unreadCounts:  make(map[string]int),
+		mentionCounts: make(map[string]int),
+		mutedSet:      make(map[string]bool),
+		onSelected:    onSelected,
 	}
 
 	ct.root = tview.NewTreeNode("")
@@ -107,8 +110,23 @@ func (ct *ChannelsTree) SetOnCopyChannelID(fn OnCopyChannelIDFunc) {
 	ct.onCopyChannelID = fn
 }
 
+// SetOnUnreadChanged sets the callback invoked after unread or mention
+// counts change, e.g. to refresh totals shown elsewhere.
+func (ct *ChannelsTree) SetOnUnreadChanged(fn func()) {
+	ct.onUnreadChanged = fn
+}
+
+// unreadChanged invokes the onUnreadChanged callback, if set.
+func (ct *ChannelsTree) unreadChanged() {
+	if ct.onUnreadChanged != nil {
+		ct.onUnreadChanged()
+	}
+}
+
 // Populate clears and rebuilds the tree from the given channel/user data.
 func (ct *ChannelsTree) Populate(channels []slack.Channel, users map[string]slack.User, selfUserID string) {
+	defer ct.unreadChanged()
+
 	// Clear existing children from each section.
 	for _, section := range ct.sections {
 		section.ClearChildren()
@@ -116,6 +134,7 @@ func (ct *ChannelsTree) Populate(channels []slack.Channel, users map[string]slac
 	ct.nodeIndex = make(map[string]*tview.TreeNode)
 	ct.channelIDs = make(map[*tview.TreeNode]string)
 	ct.unreadCounts = make(map[string]int)
+	ct.mentionCounts = make(map[string]int)
 
 	// Sort channels: public/private by name, DMs/group DMs by display name.
 	sorted := make([]slack.Channel, len(channels))
@@ -153,6 +172,7 @@ func (ct *ChannelsTree) RemoveChannel(channelID string) {
 	if !ok {
 		return
 	}
+	defer ct.unreadChanged()
 
 	// Find and remove from parent section. tview has no RemoveChild,
 	// so we clear and re-add all children except the removed one.
@@ -173,6 +193,8 @@ func (ct *ChannelsTree) RemoveChannel(channelID string) {
 
 	delete(ct.channelIDs, node)
 	delete(ct.nodeIndex, channelID)
+	delete(ct.unreadCounts, channelID)
+	delete(ct.mentionCounts, channelID)
 }
 
 // RenameChannel updates the display text for a channel.
@@ -211,6 +233,7 @@ func (ct *ChannelsTree) SetUnreadCount(channelID string, count int) {
 	if !ok {
 		return
 	}
+	defer ct.unreadChanged()
 
 	if count == -1 {
 		ct.unreadCounts[channelID]++
@@ -219,6 +242,9 @@ func (ct *ChannelsTree) SetUnreadCount(channelID string, count int) {
 	}
 
 	actual := ct.unreadCounts[channelID]
+	if actual == 0 {
+		delete(ct.mentionCounts, channelID)
+	}
 
 	// Muted channels: track the count but keep the muted visual style.
 	if ct.mutedSet[channelID] {
@@ -254,6 +280,43 @@ func (ct *ChannelsTree) UnreadCount(channelID string) int {
 	return ct.unreadCounts[channelID]
 }
 
+// SetMentionCount sets the number of unread mentions in a channel, or
+// increments it when count is -1. Mentions are cleared together with the
+// unread count when the channel is read.
+func (ct *ChannelsTree) SetMentionCount(channelID string, count int) {
+	if _, ok := ct.nodeIndex[channelID]; !ok {
+		return
+	}
+	defer ct.unreadChanged()
+
+	if count == -1 {
+		ct.mentionCounts[channelID]++
+	} else if count > 0 {
+		ct.mentionCounts[channelID] = count
+	} else {
+		delete(ct.mentionCounts, channelID)
+	}
+}
+
+// MentionCount returns the number of unread mentions in a channel.
+func (ct *ChannelsTree) MentionCount(channelID string) int {
+	return ct.mentionCounts[channelID]
+}
+
+// UnreadTotals returns the unread messages across all channels that are not
+// muted, and the unread mentions across all channels.
+func (ct *ChannelsTree) UnreadTotals() (unread, mentions int) {
+	for channelID, n := range ct.unreadCounts {
+		if !ct.mutedSet[channelID] {
+			unread += n
+		}
+	}
+	for _, n := range ct.mentionCounts {
+		mentions += n
+	}
+	return unread, mentions
+}
+
 // SetMuted marks a channel as muted or unmuted.
 // Muted channels are displayed with a dimmed style and their unread badge is
 // hidden. The internal unread count is still tracked so that unmuting restores
@@ -263,6 +326,7 @@ func (ct *ChannelsTree) SetMuted(channelID string, muted bool) {
 	if !ok {
 		return
 	}
+	defer ct.unreadChanged()
 
 	if muted {
 		ct.mutedSet[channelID] = true
@@ -282,26 +346,75 @@ func (ct *ChannelsTree) SetMuted(channelID string, muted bool) {
 	}
 }
 
+// applyTheme restyles every channel node from the current theme, keeping
+// muted and unread state.
+func (ct *ChannelsTree) applyTheme() {
+	theme := ct.cfg.Theme.ChannelsTree
+	for channelID, node := range ct.nodeIndex {
+		switch {
+		case ct.mutedSet[channelID]:
+			node.SetTextStyle(theme.Muted.Style)
+		case ct.unreadCounts[channelID] > 0:
+			node.SetTextStyle(theme.Unread.Style)
+		default:
+			node.SetTextStyle(theme.Channel.Style)
+		}
+	}
+}
+
 // IsMuted reports whether a channel is currently muted.
 func (ct *ChannelsTree) IsMuted(channelID string) bool {
 	return ct.mutedSet[channelID]
 }
 
-// handleInput processes c (collapse) and p (parent) keys.
+// handleInput processes the channels tree keybindings. Unhandled keys fall
+// through to the tree's built-in navigation.
 func (ct *ChannelsTree) handleInput(event *tcell.EventKey) *tcell.EventKey {
-	name := keys.Normalize(event.Name())
+	if ct.handleKey(keys.Normalize(event.Name()), 1) {
+		return nil
+	}
+	return event
+}
+
+// handleKey runs the action bound to name (a key or completed sequence),
+// moving count nodes for movement actions. It reports whether name was
+// handled.
+func (ct *ChannelsTree) handleKey(name string, count int) bool {
+	switch {
+	case ct.cfg.Keybinds.ChannelsTree.Up.Matches(name):
+		ct.Move(-count)
+		return true
+
+	case ct.cfg.Keybinds.ChannelsTree.Down.Matches(name):
+		ct.Move(count)
+		return true
+
+	case ct.cfg.Keybinds.ChannelsTree.Top.Matches(name):
+		// With a count, go to that node like vim's 5gg.
+		ct.Move(-ct.GetRowCount())
+		ct.Move(count - 1)
+		return true
+
+	case ct.cfg.Keybinds.ChannelsTree.Bottom.Matches(name):
+		ct.Move(ct.GetRowCount())
+		return true
+
+	case ct.cfg.Keybinds.ChannelsTree.SelectCurrent.Matches(name):
+		if node := ct.GetCurrentNode(); node != nil {
+			ct.GetSelectedFunc()(node)
+		}
+		return true
 
-	switch name {
-	case ct.cfg.Keybinds.ChannelsTree.Collapse:
+	case ct.cfg.Keybinds.ChannelsTree.Collapse.Matches(name):
 		current := ct.GetCurrentNode()
 		if current == nil {
-			return event
+			return false
 		}
 		// If current is a section header, toggle its expansion.
 		for _, section := range ct.sections {
 			if current == section {
 				section.SetExpanded(!section.IsExpanded())
-				return nil
+				return true
 			}
 		}
 		// If current is a channel node, toggle its parent section.
@@ -311,41 +424,41 @@ func (ct *ChannelsTree) handleInput(event *tcell.EventKey) *tcell.EventKey {
 				for _, child := range section.GetChildren() {
 					if child == current {
 						section.SetExpanded(!section.IsExpanded())
-						return nil
+						return true
 					}
 				}
 			}
 		}
-		return nil
+		return true
 
-	case ct.cfg.Keybinds.ChannelsTree.MoveToParent:
+	case ct.cfg.Keybinds.ChannelsTree.MoveToParent.Matches(name):
 		current := ct.GetCurrentNode()
 		if current == nil {
-			return event
+			return false
 		}
 		// If current is a channel node, move to its parent section.
 		for _, section := range ct.sections {
 			for _, child := range section.GetChildren() {
 				if child == current {
 					ct.SetCurrentNode(section)
-					return nil
+					return true
 				}
 			}
 		}
-		return nil
+		return true
 
-	case ct.cfg.Keybinds.ChannelsTree.CopyChannelID:
+	case ct.cfg.Keybinds.ChannelsTree.CopyChannelID.Matches(name):
 		current := ct.GetCurrentNode()
 		if current == nil {
-			return event
+			return false
 		}
 		if chID, ok := ct.channelIDs[current]; ok && ct.onCopyChannelID != nil {
 			ct.onCopyChannelID(chID)
-			return nil
+			return true
 		}
 	}
 
-	return event
+	return false
 }
 
 // addChannelNode creates a node for a channel and adds it to the correct section.
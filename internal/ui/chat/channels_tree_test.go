@@ -391,6 +391,45 @@ func TestMutedChannelClearedOnPopulate(t *testing.T) {
 }
 
 // makeChannel is a test helper that creates a slack.Channel with the given properties.
+func TestMentionCountsAndTotals(t *testing.T) {
+	cfg := &config.Config{}
+	ct := NewChannelsTree(cfg, nil)
+	ct.Populate([]slack.Channel{
+		makeChannel("C1", "general", false, false, false),
+		makeChannel("C2", "random", false, false, false),
+		makeDMChannel("D1", "U2"),
+	}, map[string]slack.User{}, "SELF")
+
+	changes := 0
+	ct.SetOnUnreadChanged(func() { changes++ })
+
+	ct.SetUnreadCount("C1", 2)
+	ct.SetMentionCount("C1", -1)
+	ct.SetUnreadCount("C2", 5)
+	ct.SetUnreadCount("D1", 1)
+	ct.SetMentionCount("D1", 1)
+	ct.SetMuted("C2", true)
+
+	if changes != 6 {
+		t.Errorf("onUnreadChanged called %d times, want 6", changes)
+	}
+	if unread, mentions := ct.UnreadTotals(); unread != 3 || mentions != 2 {
+		t.Errorf("UnreadTotals = %d, %d; want 3 unread (muted excluded), 2 mentions", unread, mentions)
+	}
+
+	// Reading a channel clears its mentions.
+	ct.SetUnreadCount("C1", 0)
+	if got := ct.MentionCount("C1"); got != 0 {
+		t.Errorf("MentionCount after read = %d, want 0", got)
+	}
+
+	// Unknown channels are ignored.
+	ct.SetMentionCount("C9", -1)
+	if _, mentions := ct.UnreadTotals(); mentions != 1 {
+		t.Errorf("mentions = %d, want 1", mentions)
+	}
+}
+
 func makeChannel(id, name string, private, im, mpim bool) slack.Channel {
 	ch := slack.Channel{}
 	ch.ID = id
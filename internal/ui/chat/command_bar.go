@@ -19,7 +19,7 @@ type VimCommand struct {
 // builtinVimCommands is the list of supported vim-style commands.
 var builtinVimCommands = []VimCommand{
 	{Name: "q", Aliases: []string{"quit"}, Description: "Quit application"},
-	{Name: "theme", Description: "Switch theme"},
+	{Name: "theme", Description: "Switch theme (picker without a name)"},
 	{Name: "join", Description: "Join channel"},
 	{Name: "leave", Description: "Leave current channel"},
 	{Name: "search", Description: "Search messages"},
@@ -29,23 +29,31 @@ var builtinVimCommands = []VimCommand{
 	{Name: "reconnect", Description: "Reconnect Socket Mode"},
 	{Name: "debug", Description: "Toggle debug logging"},
 	{Name: "set", Description: "Change config at runtime"},
+	{Name: "source", Aliases: []string{"reload"}, Description: "Reload config file"},
+	{Name: "mkconfig", Aliases: []string{"w"}, Description: "Write changed options to config file"},
 	{Name: "bookmarks", Description: "Show channel bookmarks"},
-	{Name: "workspace", Aliases: []string{"ws"}, Description: "Switch workspace"},
+	{Name: "activity", Description: "Show mentions and reactions"},
+	{Name: "export", Description: "Export channel or thread history"},
+	{Name: "workspace", Aliases: []string{"ws"}, Description: "Switch, add or remove workspaces"},
 	{Name: "members", Aliases: []string{"who"}, Description: "List channel members"},
 	{Name: "create-channel", Description: "Create a new channel"},
 	{Name: "invite", Description: "Invite user to channel"},
 	{Name: "group-dm", Aliases: []string{"gdm"}, Description: "Create group DM"},
 }
 
+// workspaceSubcommands are the arguments of :workspace.
+var workspaceSubcommands = []string{"add", "remove"}
+
 // CommandBar is a vim-style command input shown at the bottom of the screen.
 type CommandBar struct {
 	*tview.InputField
-	cfg            *config.Config
-	history        []string
-	histIdx        int // -1 means not browsing history
-	onExecute      func(command, args string)
-	onClose        func()
-	setOptionNames []string // option names for :set subcommand completion
+	cfg             *config.Config
+	history         []string
+	histIdx         int // -1 means not browsing history
+	onExecute       func(command, args string)
+	onClose         func()
+	setOptionNames  []string            // option names for :set subcommand completion
+	setOptionValues map[string][]string // option name → values for "set option=" completion
 }
 
 // NewCommandBar creates a new command bar.
@@ -87,6 +95,11 @@ func (cb *CommandBar) SetSetOptionNames(names []string) {
 	cb.setOptionNames = names
 }
 
+// SetSetOptionValues sets the values offered after "set option=".
+func (cb *CommandBar) SetSetOptionValues(values map[string][]string) {
+	cb.setOptionValues = values
+}
+
 // Reset clears the input and history index.
 func (cb *CommandBar) Reset() {
 	cb.SetText("")
@@ -131,17 +144,7 @@ func (cb *CommandBar) execute() {
 	}
 	cb.histIdx = -1
 
-	// Parse command and args.
-	parts := strings.SplitN(text, " ", 2)
-	command := strings.ToLower(parts[0])
-	args := ""
-	if len(parts) > 1 {
-		args = strings.TrimSpace(parts[1])
-	}
-
-	// Resolve aliases.
-	command = resolveAlias(command)
-
+	command, args := ParseVimCommand(text)
 	if cb.onExecute != nil {
 		cb.onExecute(command, args)
 	}
@@ -183,6 +186,15 @@ func (cb *CommandBar) autocomplete(currentText string) []string {
 		cmd := strings.TrimSpace(lower[:spaceIdx])
 		sub := strings.TrimSpace(lower[spaceIdx+1:])
 
+		if name, prefix, ok := strings.Cut(sub, "="); cmd == "set" && ok {
+			var matches []string
+			for _, v := range cb.setOptionValues[name] {
+				if strings.HasPrefix(strings.ToLower(v), prefix) {
+					matches = append(matches, "set "+name+"="+v)
+				}
+			}
+			return matches
+		}
 		if cmd == "set" && len(cb.setOptionNames) > 0 {
 			var matches []string
 			for _, name := range cb.setOptionNames {
@@ -192,6 +204,24 @@ func (cb *CommandBar) autocomplete(currentText string) []string {
 			}
 			return matches
 		}
+		if cmd == "workspace" || cmd == "ws" {
+			var matches []string
+			for _, name := range workspaceSubcommands {
+				if strings.HasPrefix(name, sub) {
+					matches = append(matches, cmd+" "+name)
+				}
+			}
+			return matches
+		}
+		if cmd == "theme" {
+			var matches []string
+			for _, name := range config.ThemePresets {
+				if strings.HasPrefix(name, sub) {
+					matches = append(matches, "theme "+name)
+				}
+			}
+			return matches
+		}
 		return nil
 	}
 
@@ -207,9 +237,25 @@ func (cb *CommandBar) autocomplete(currentText string) []string {
 			}
 		}
 	}
+	for _, name := range aliasNames(cb.cfg.Aliases) {
+		if strings.HasPrefix(name, lower) {
+			matches = append(matches, name)
+		}
+	}
 	return matches
 }
 
+// ParseVimCommand splits command bar text (without the leading ":") into a
+// lower-case command name and its arguments, resolving builtin aliases.
+func ParseVimCommand(text string) (command, args string) {
+	parts := strings.SplitN(strings.TrimSpace(text), " ", 2)
+	command = strings.ToLower(parts[0])
+	if len(parts) > 1 {
+		args = strings.TrimSpace(parts[1])
+	}
+	return resolveAlias(command), args
+}
+
 // resolveAlias maps command aliases to their canonical names.
 func resolveAlias(command string) string {
 	for _, cmd := range builtinVimCommands {
@@ -43,6 +43,12 @@ func TestCommandBarAutocomplete(t *testing.T) {
 		{"qu", false, "quit"},
 		{"the", false, "theme"},
 		{"le", false, "leave"},
+		{"theme ", false, "theme monokai"},
+		{"theme sol", false, "theme solarized_light"},
+		{"theme nope", true, ""},
+		{"workspace ", false, "workspace add"},
+		{"ws rem", false, "ws remove"},
+		{"workspace nope", true, ""},
 		{"unknown with space", true, ""},
 		{"xyz", true, ""},
 	}
@@ -212,6 +218,7 @@ func TestCommandBarReset(t *testing.T) {
 func TestCommandBarAutocompleteSetSubcommands(t *testing.T) {
 	cb := NewCommandBar(&config.Config{})
 	cb.SetSetOptionNames([]string{"mouse", "timestamps", "markdown", "typing", "presence", "date_separator"})
+	cb.SetSetOptionValues(map[string][]string{"mouse": {"on", "off"}, "theme": {"dark", "default"}})
 
 	tests := []struct {
 		input     string
@@ -226,6 +233,10 @@ func TestCommandBarAutocompleteSetSubcommands(t *testing.T) {
 		{"set d", false, "set date_separator"},
 		{"set p", false, "set presence"},
 		{"set xyz", true, ""},
+		{"set mouse=", false, "set mouse=off"},
+		{"set theme=da", false, "set theme=dark"},
+		{"set theme=x", true, ""},
+		{"set messages_limit=", true, ""},
 	}
 
 	for _, tt := range tests {
@@ -274,3 +285,37 @@ func TestSetSetOptionNames(t *testing.T) {
 		t.Errorf("setOptionNames[0] = %q, want %q", cb.setOptionNames[0], "mouse")
 	}
 }
+
+func TestParseVimCommand(t *testing.T) {
+	tests := []struct {
+		input       string
+		wantCommand string
+		wantArgs    string
+	}{
+		{"join #general", "join", "#general"},
+		{"  Quit  ", "q", ""},
+		{"set  mouse", "set", "mouse"},
+		{"standup now", "standup", "now"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			command, args := ParseVimCommand(tt.input)
+			if command != tt.wantCommand || args != tt.wantArgs {
+				t.Errorf("ParseVimCommand(%q) = (%q, %q), want (%q, %q)",
+					tt.input, command, args, tt.wantCommand, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCommandBarAutocompleteAliases(t *testing.T) {
+	cb := NewCommandBar(&config.Config{
+		Aliases: map[string]config.Alias{"standup": {":join #standup"}},
+	})
+
+	matches := cb.autocomplete("sta")
+	if len(matches) != 1 || matches[0] != "standup" {
+		t.Errorf("autocomplete(%q) = %v, want [standup]", "sta", matches)
+	}
+}
@@ -1,6 +1,11 @@
 package chat
 
-import "strings"
+import (
+	"slices"
+	"strings"
+
+	"github.com/m96-chan/Slacko/internal/config"
+)
 
 // SlashCommand defines a slash command with its metadata.
 type SlashCommand struct {
@@ -49,6 +54,64 @@ func BuiltinCommandEntries() []commandEntry {
 	return entries
 }
 
+// CommandEntries returns the autocomplete entries for every slash command:
+// the builtin commands followed by the custom commands and aliases in cfg.
+func CommandEntries(cfg *config.Config) []commandEntry {
+	entries := BuiltinCommandEntries()
+	entries = append(entries, CustomCommandEntries(cfg.Commands)...)
+	return append(entries, AliasCommandEntries(cfg.Aliases)...)
+}
+
+// CustomCommandEntries returns autocomplete entries for the custom commands
+// defined in config, in config order.
+func CustomCommandEntries(cmds []config.CustomCommand) []commandEntry {
+	entries := make([]commandEntry, len(cmds))
+	for i, cmd := range cmds {
+		entries[i] = commandEntry{
+			name:        "/" + cmd.Name,
+			description: CustomCommandDescription(cmd),
+			searchText:  cmd.Name,
+			insertText:  "/" + cmd.Name + " ",
+		}
+	}
+	return entries
+}
+
+// CustomCommandDescription returns the description of a custom command,
+// falling back to the executable it runs.
+func CustomCommandDescription(cmd config.CustomCommand) string {
+	if cmd.Description != "" {
+		return cmd.Description
+	}
+	return "Run " + cmd.Exec
+}
+
+// AliasCommandEntries returns autocomplete entries for user-defined aliases,
+// sorted by name. The description lists the commands the alias runs.
+func AliasCommandEntries(aliases map[string]config.Alias) []commandEntry {
+	names := aliasNames(aliases)
+	entries := make([]commandEntry, len(names))
+	for i, name := range names {
+		entries[i] = commandEntry{
+			name:        "/" + name,
+			description: strings.Join(aliases[name], "; "),
+			searchText:  name,
+			insertText:  "/" + name + " ",
+		}
+	}
+	return entries
+}
+
+// aliasNames returns the alias names in sorted order.
+func aliasNames(aliases map[string]config.Alias) []string {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
 // ParseSlashCommand parses a slash command string into command name and args.
 // Returns ("", "") if the text is not a slash command.
 func ParseSlashCommand(text string) (command, args string) {
@@ -1,7 +1,10 @@
 package chat
 
 import (
+	"slices"
 	"testing"
+
+	"github.com/m96-chan/Slacko/internal/config"
 )
 
 func TestParseSlashCommand(t *testing.T) {
@@ -78,3 +81,67 @@ func TestBuiltinCommandEntries(t *testing.T) {
 		}
 	}
 }
+
+func TestAliasCommandEntries(t *testing.T) {
+	entries := AliasCommandEntries(map[string]config.Alias{
+		"standup": {"/status :calendar: Standup", ":join #standup"},
+		"lunch":   {"/status :pizza: Lunch"},
+	})
+	if len(entries) != 2 {
+		t.Fatalf("AliasCommandEntries() has %d entries, want 2", len(entries))
+	}
+
+	if entries[0].name != "/lunch" || entries[1].name != "/standup" {
+		t.Errorf("entries not sorted by name: %q, %q", entries[0].name, entries[1].name)
+	}
+	if want := "/status :calendar: Standup; :join #standup"; entries[1].description != want {
+		t.Errorf("description = %q, want %q", entries[1].description, want)
+	}
+	if entries[1].insertText != "/standup " {
+		t.Errorf("insertText = %q, want %q", entries[1].insertText, "/standup ")
+	}
+}
+
+func TestCommandEntries(t *testing.T) {
+	cfg := &config.Config{
+		Commands: []config.CustomCommand{
+			{Name: "jira", Description: "Link a Jira issue", Exec: "jira-link"},
+			{Name: "oncall", Exec: "oncall"},
+		},
+		Aliases: map[string]config.Alias{"standup": {":join #standup"}},
+	}
+
+	entries := CommandEntries(cfg)
+	builtin := len(BuiltinCommands())
+	if len(entries) != builtin+3 {
+		t.Fatalf("CommandEntries() has %d entries, want %d", len(entries), builtin+3)
+	}
+
+	custom := entries[builtin:]
+	if custom[0].name != "/jira" || custom[0].description != "Link a Jira issue" {
+		t.Errorf("entry = %q %q", custom[0].name, custom[0].description)
+	}
+	if custom[1].description != "Run oncall" {
+		t.Errorf("default description = %q, want %q", custom[1].description, "Run oncall")
+	}
+	if custom[2].name != "/standup" {
+		t.Errorf("aliases should follow custom commands, got %q", custom[2].name)
+	}
+}
+
+func TestBuiltinCommandNamesReserved(t *testing.T) {
+	// Custom commands and aliases may not use these names; the config
+	// package keeps its own copy of the lists.
+	for _, cmd := range builtinCommands {
+		if !slices.Contains(config.BuiltinSlashCommands, cmd.Name) {
+			t.Errorf("/%s is missing from config.BuiltinSlashCommands", cmd.Name)
+		}
+	}
+	for _, cmd := range builtinVimCommands {
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			if !slices.Contains(config.BuiltinVimCommands, name) {
+				t.Errorf(":%s is missing from config.BuiltinVimCommands", name)
+			}
+		}
+	}
+}
@@ -174,22 +174,22 @@ func (fp *FilePicker) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
 	switch {
-	case name == fp.cfg.Keybinds.FilePicker.Close:
+	case fp.cfg.Keybinds.FilePicker.Close.Matches(name):
 		fp.close()
 		return nil
 
-	case name == fp.cfg.Keybinds.FilePicker.Select:
+	case fp.cfg.Keybinds.FilePicker.Select.Matches(name):
 		fp.selectCurrent()
 		return nil
 
-	case name == fp.cfg.Keybinds.FilePicker.Up || event.Key() == tcell.KeyUp:
+	case fp.cfg.Keybinds.FilePicker.Up.Matches(name) || event.Key() == tcell.KeyUp:
 		cur := fp.list.GetCurrentItem()
 		if cur > 0 {
 			fp.list.SetCurrentItem(cur - 1)
 		}
 		return nil
 
-	case name == fp.cfg.Keybinds.FilePicker.Down || event.Key() == tcell.KeyDown:
+	case fp.cfg.Keybinds.FilePicker.Down.Matches(name) || event.Key() == tcell.KeyDown:
 		cur := fp.list.GetCurrentItem()
 		if cur < fp.list.GetItemCount()-1 {
 			fp.list.SetCurrentItem(cur + 1)
@@ -11,10 +11,10 @@ import (
 func newTestFilePicker(t *testing.T) *FilePicker {
 	t.Helper()
 	cfg := &config.Config{}
-	cfg.Keybinds.FilePicker.Close = "Escape"
-	cfg.Keybinds.FilePicker.Up = "Ctrl+P"
-	cfg.Keybinds.FilePicker.Down = "Ctrl+N"
-	cfg.Keybinds.FilePicker.Select = "Enter"
+	cfg.Keybinds.FilePicker.Close = config.Keybind{"Escape"}
+	cfg.Keybinds.FilePicker.Up = config.Keybind{"Ctrl+P"}
+	cfg.Keybinds.FilePicker.Down = config.Keybind{"Ctrl+N"}
+	cfg.Keybinds.FilePicker.Select = config.Keybind{"Enter"}
 	return NewFilePicker(cfg)
 }
 
@@ -127,30 +127,30 @@ func (gp *GroupDMPicker) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
 	switch {
-	case name == gp.cfg.Keybinds.GroupDMPicker.Close:
+	case gp.cfg.Keybinds.GroupDMPicker.Close.Matches(name):
 		gp.close()
 		return nil
 
-	case name == gp.cfg.Keybinds.GroupDMPicker.Confirm:
+	case gp.cfg.Keybinds.GroupDMPicker.Confirm.Matches(name):
 		gp.confirm()
 		return nil
 
-	case name == gp.cfg.Keybinds.GroupDMPicker.Add:
+	case gp.cfg.Keybinds.GroupDMPicker.Add.Matches(name):
 		gp.addCurrent()
 		return nil
 
-	case name == gp.cfg.Keybinds.GroupDMPicker.Remove:
+	case gp.cfg.Keybinds.GroupDMPicker.Remove.Matches(name):
 		gp.removeLastChosen()
 		return nil
 
-	case name == gp.cfg.Keybinds.GroupDMPicker.Up || event.Key() == tcell.KeyUp:
+	case gp.cfg.Keybinds.GroupDMPicker.Up.Matches(name) || event.Key() == tcell.KeyUp:
 		cur := gp.list.GetCurrentItem()
 		if cur > 0 {
 			gp.list.SetCurrentItem(cur - 1)
 		}
 		return nil
 
-	case name == gp.cfg.Keybinds.GroupDMPicker.Down || event.Key() == tcell.KeyDown:
+	case gp.cfg.Keybinds.GroupDMPicker.Down.Matches(name) || event.Key() == tcell.KeyDown:
 		cur := gp.list.GetCurrentItem()
 		if cur < gp.list.GetItemCount()-1 {
 			gp.list.SetCurrentItem(cur + 1)
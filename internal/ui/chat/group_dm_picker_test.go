@@ -8,12 +8,12 @@ import (
 
 func newTestGroupDMPicker() *GroupDMPicker {
 	cfg := &config.Config{}
-	cfg.Keybinds.GroupDMPicker.Close = "Escape"
-	cfg.Keybinds.GroupDMPicker.Up = "Ctrl+P"
-	cfg.Keybinds.GroupDMPicker.Down = "Ctrl+N"
-	cfg.Keybinds.GroupDMPicker.Add = "Enter"
-	cfg.Keybinds.GroupDMPicker.Remove = "Ctrl+D"
-	cfg.Keybinds.GroupDMPicker.Confirm = "Ctrl+Enter"
+	cfg.Keybinds.GroupDMPicker.Close = config.Keybind{"Escape"}
+	cfg.Keybinds.GroupDMPicker.Up = config.Keybind{"Ctrl+P"}
+	cfg.Keybinds.GroupDMPicker.Down = config.Keybind{"Ctrl+N"}
+	cfg.Keybinds.GroupDMPicker.Add = config.Keybind{"Enter"}
+	cfg.Keybinds.GroupDMPicker.Remove = config.Keybind{"Ctrl+D"}
+	cfg.Keybinds.GroupDMPicker.Confirm = config.Keybind{"Ctrl+Enter"}
 	return NewGroupDMPicker(cfg)
 }
 
@@ -105,22 +105,22 @@ func (ip *InvitePicker) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
 	switch {
-	case name == ip.cfg.Keybinds.InvitePicker.Close:
+	case ip.cfg.Keybinds.InvitePicker.Close.Matches(name):
 		ip.close()
 		return nil
 
-	case name == ip.cfg.Keybinds.InvitePicker.Select:
+	case ip.cfg.Keybinds.InvitePicker.Select.Matches(name):
 		ip.selectCurrent()
 		return nil
 
-	case name == ip.cfg.Keybinds.InvitePicker.Up || event.Key() == tcell.KeyUp:
+	case ip.cfg.Keybinds.InvitePicker.Up.Matches(name) || event.Key() == tcell.KeyUp:
 		cur := ip.list.GetCurrentItem()
 		if cur > 0 {
 			ip.list.SetCurrentItem(cur - 1)
 		}
 		return nil
 
-	case name == ip.cfg.Keybinds.InvitePicker.Down || event.Key() == tcell.KeyDown:
+	case ip.cfg.Keybinds.InvitePicker.Down.Matches(name) || event.Key() == tcell.KeyDown:
 		cur := ip.list.GetCurrentItem()
 		if cur < ip.list.GetItemCount()-1 {
 			ip.list.SetCurrentItem(cur + 1)
@@ -8,10 +8,10 @@ import (
 
 func newTestInvitePicker() *InvitePicker {
 	cfg := &config.Config{}
-	cfg.Keybinds.InvitePicker.Close = "Escape"
-	cfg.Keybinds.InvitePicker.Up = "Ctrl+P"
-	cfg.Keybinds.InvitePicker.Down = "Ctrl+N"
-	cfg.Keybinds.InvitePicker.Select = "Enter"
+	cfg.Keybinds.InvitePicker.Close = config.Keybind{"Escape"}
+	cfg.Keybinds.InvitePicker.Up = config.Keybind{"Ctrl+P"}
+	cfg.Keybinds.InvitePicker.Down = config.Keybind{"Ctrl+N"}
+	cfg.Keybinds.InvitePicker.Select = config.Keybind{"Enter"}
 	return NewInvitePicker(cfg)
 }
 
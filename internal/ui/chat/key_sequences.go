@@ -0,0 +1,89 @@
+package chat
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/m96-chan/Slacko/internal/config"
+)
+
+// keyHandler is implemented by panels whose actions can be triggered by a
+// completed key sequence or with a count, both resolved by the View.
+type keyHandler interface {
+	handleKey(name string, count int) bool
+}
+
+// activeBindings returns the sequences the key matcher considers: the
+// global keybinds plus those of the focused panel.
+func (v *View) activeBindings() []string {
+	seqs := config.Sequences(v.cfg.Keybinds)
+	switch v.activePanel {
+	case PanelChannels:
+		seqs = append(seqs, config.Sequences(v.cfg.Keybinds.ChannelsTree)...)
+	case PanelMessages:
+		seqs = append(seqs, config.Sequences(v.cfg.Keybinds.MessagesList)...)
+	case PanelInput:
+		seqs = append(seqs, config.Sequences(v.cfg.Keybinds.MessageInput)...)
+	case PanelThread:
+		if v.ThreadView.IsInputFocused() {
+			seqs = append(seqs, config.Sequences(v.cfg.Keybinds.MessageInput)...)
+		} else {
+			seqs = append(seqs, config.Sequences(v.cfg.Keybinds.ThreadView)...)
+		}
+	}
+	return seqs
+}
+
+// focusedKeyHandler returns the focused panel if it accepts sequences and
+// counts.
+func (v *View) focusedKeyHandler() keyHandler {
+	switch v.activePanel {
+	case PanelChannels:
+		return v.ChannelsTree
+	case PanelMessages:
+		return v.MessagesList
+	case PanelThread:
+		if !v.ThreadView.IsInputFocused() {
+			return v.ThreadView
+		}
+	}
+	return nil
+}
+
+// showPendingKeys displays the partially typed sequence or count in the
+// status bar and clears it once the matcher times out.
+func (v *View) showPendingKeys() {
+	pending := v.keyMatcher.Pending()
+	v.StatusBar.SetPendingKeys(pending)
+	if pending == "" {
+		return
+	}
+	time.AfterFunc(v.keyMatcher.Timeout(), func() {
+		v.app.QueueUpdateDraw(func() {
+			if v.keyMatcher.Expired() {
+				v.keyMatcher.Reset()
+				v.StatusBar.SetPendingKeys("")
+			}
+		})
+	})
+}
+
+// repeatKey delivers event n more times to the focused primitive, so a count
+// in front of a key the panel does not bind (e.g. 5j scrolling a text view)
+// repeats its built-in behavior.
+func (v *View) repeatKey(event *tcell.EventKey, n int) {
+	focused := v.app.GetFocus()
+	if focused == nil {
+		return
+	}
+	handler := focused.InputHandler()
+	if handler == nil {
+		return
+	}
+	setFocus := func(p tview.Primitive) { v.app.SetFocus(p) }
+	for range n {
+		handler(event, setFocus)
+	}
+}
@@ -0,0 +1,100 @@
+package chat
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/slack-go/slack"
+
+	"github.com/m96-chan/Slacko/internal/config"
+)
+
+func sequenceTestView(t *testing.T) *View {
+	t.Helper()
+	cfg := testConfig()
+	cfg.Keybinds.MessagesList.Down = config.Keybind{"Rune[j]"}
+	cfg.Keybinds.MessagesList.Up = config.Keybind{"Rune[k]"}
+	cfg.Keybinds.MessagesList.Yank = config.Keybind{"Rune[y] Rune[y]"}
+	cfg.Keybinds.FocusChannels = config.Keybind{"Rune[1]"}
+	cfg.Keybinds.MarkRead = config.Keybind{"Rune[m]", "Rune[\\] Rune[r]"}
+
+	v := New(tview.NewApplication(), cfg)
+	msgs := make([]slack.Message, 10)
+	for i := range msgs {
+		msgs[i] = slack.Message{Msg: slack.Msg{Timestamp: fmt.Sprintf("%d.0", 10-i), Text: "m"}}
+	}
+	v.MessagesList.SetMessages("C1", msgs, nil)
+	v.FocusPanel(PanelMessages)
+	return v
+}
+
+func runeKey(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestHandleKeyCount(t *testing.T) {
+	v := sequenceTestView(t)
+
+	v.MessagesList.SelectTimestamp("5.0")
+	start := v.MessagesList.selectedIdx
+	if v.HandleKey(runeKey('k')) == nil {
+		t.Fatal("plain k should pass through to the messages list")
+	}
+
+	if v.HandleKey(runeKey('3')) != nil {
+		t.Fatal("count digit should be consumed")
+	}
+	if got := v.StatusBar.pendingKeys; got != "3" {
+		t.Errorf("pending keys = %q, want 3", got)
+	}
+	if v.HandleKey(runeKey('k')) != nil {
+		t.Fatal("counted key should be consumed")
+	}
+	if got := v.MessagesList.selectedIdx; got != start-3 {
+		t.Errorf("selectedIdx = %d, want %d", got, start-3)
+	}
+	if got := v.StatusBar.pendingKeys; got != "" {
+		t.Errorf("pending keys = %q after count completed, want empty", got)
+	}
+}
+
+func TestHandleKeySequence(t *testing.T) {
+	v := sequenceTestView(t)
+	var yanked string
+	v.MessagesList.SetOnYank(func(text string) { yanked = text })
+	v.MessagesList.SelectTimestamp("5.0")
+
+	if v.HandleKey(runeKey('y')) != nil {
+		t.Fatal("first key of a sequence should be consumed")
+	}
+	if yanked != "" {
+		t.Fatal("yank fired before the sequence completed")
+	}
+	if v.HandleKey(runeKey('y')) != nil {
+		t.Fatal("completed sequence should be consumed")
+	}
+	if yanked != "m" {
+		t.Errorf("yanked = %q, want m", yanked)
+	}
+}
+
+func TestHandleKeyGlobalSequenceAndAlternatives(t *testing.T) {
+	v := sequenceTestView(t)
+	marked := 0
+	v.SetOnMarkRead(func() { marked++ })
+
+	v.HandleKey(runeKey('m'))
+	v.HandleKey(runeKey('\\'))
+	v.HandleKey(runeKey('r'))
+	if marked != 2 {
+		t.Errorf("mark read fired %d times, want 2 (m and \\ r)", marked)
+	}
+
+	// A bound digit is a key, not a count.
+	v.HandleKey(runeKey('1'))
+	if v.activePanel != PanelChannels {
+		t.Errorf("active panel = %v, want channels", v.activePanel)
+	}
+}
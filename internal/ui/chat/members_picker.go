@@ -107,22 +107,22 @@ func (mp *MembersPicker) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
 	switch {
-	case name == mp.cfg.Keybinds.MembersPicker.Close:
+	case mp.cfg.Keybinds.MembersPicker.Close.Matches(name):
 		mp.close()
 		return nil
 
-	case name == mp.cfg.Keybinds.MembersPicker.Select:
+	case mp.cfg.Keybinds.MembersPicker.Select.Matches(name):
 		mp.selectCurrent()
 		return nil
 
-	case name == mp.cfg.Keybinds.MembersPicker.Up || event.Key() == tcell.KeyUp:
+	case mp.cfg.Keybinds.MembersPicker.Up.Matches(name) || event.Key() == tcell.KeyUp:
 		cur := mp.list.GetCurrentItem()
 		if cur > 0 {
 			mp.list.SetCurrentItem(cur - 1)
 		}
 		return nil
 
-	case name == mp.cfg.Keybinds.MembersPicker.Down || event.Key() == tcell.KeyDown:
+	case mp.cfg.Keybinds.MembersPicker.Down.Matches(name) || event.Key() == tcell.KeyDown:
 		cur := mp.list.GetCurrentItem()
 		if cur < mp.list.GetItemCount()-1 {
 			mp.list.SetCurrentItem(cur + 1)
@@ -8,10 +8,10 @@ import (
 
 func newTestMembersPicker() *MembersPicker {
 	cfg := &config.Config{}
-	cfg.Keybinds.MembersPicker.Close = "Escape"
-	cfg.Keybinds.MembersPicker.Up = "Ctrl+P"
-	cfg.Keybinds.MembersPicker.Down = "Ctrl+N"
-	cfg.Keybinds.MembersPicker.Select = "Enter"
+	cfg.Keybinds.MembersPicker.Close = config.Keybind{"Escape"}
+	cfg.Keybinds.MembersPicker.Up = config.Keybind{"Ctrl+P"}
+	cfg.Keybinds.MembersPicker.Down = config.Keybind{"Ctrl+N"}
+	cfg.Keybinds.MembersPicker.Select = config.Keybind{"Enter"}
 	return NewMembersPicker(cfg)
 }
 
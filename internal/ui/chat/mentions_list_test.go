@@ -287,10 +287,10 @@ func TestFindAutocompleteTrigger_CommandAfterSpace(t *testing.T) {
 
 func TestMessageInput_CompleteAutocomplete(t *testing.T) {
 	cfg := &config.Config{}
-	cfg.Keybinds.MessageInput.Send = "Enter"
-	cfg.Keybinds.MessageInput.Newline = "Shift+Enter"
-	cfg.Keybinds.MessageInput.Cancel = "Escape"
-	cfg.Keybinds.MessageInput.TabComplete = "Tab"
+	cfg.Keybinds.MessageInput.Send = config.Keybind{"Enter"}
+	cfg.Keybinds.MessageInput.Newline = config.Keybind{"Shift+Enter"}
+	cfg.Keybinds.MessageInput.Cancel = config.Keybind{"Escape"}
+	cfg.Keybinds.MessageInput.TabComplete = config.Keybind{"Tab"}
 	cfg.AutocompleteLimit = 5
 
 	mi := NewMessageInput(cfg)
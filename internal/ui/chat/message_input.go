@@ -68,11 +68,7 @@ func NewMessageInput(cfg *config.Config) *MessageInput {
 	mi.SetBorder(true).SetTitle(" Input ")
 	mi.SetPlaceholder("Type a message...")
 
-	// Apply theme styles.
-	fg, bg, _ := cfg.Theme.MessageInput.Text.Style.Decompose()
-	mi.SetTextStyle(tcell.StyleDefault.Foreground(fg).Background(bg))
-	pfg, pbg, _ := cfg.Theme.MessageInput.Placeholder.Style.Decompose()
-	mi.SetPlaceholderStyle(tcell.StyleDefault.Foreground(pfg).Background(pbg))
+	mi.applyTheme()
 
 	mi.SetInputCapture(mi.handleInput)
 	mi.SetChangedFunc(mi.onTextChanged)
@@ -80,6 +76,14 @@ func NewMessageInput(cfg *config.Config) *MessageInput {
 	return mi
 }
 
+// applyTheme sets the text and placeholder styles from the current theme.
+func (mi *MessageInput) applyTheme() {
+	fg, bg, _ := mi.cfg.Theme.MessageInput.Text.Style.Decompose()
+	mi.SetTextStyle(tcell.StyleDefault.Foreground(fg).Background(bg))
+	pfg, pbg, _ := mi.cfg.Theme.MessageInput.Placeholder.Style.Decompose()
+	mi.SetPlaceholderStyle(tcell.StyleDefault.Foreground(pfg).Background(pbg))
+}
+
 // SetOnSend sets the callback for sending messages.
 func (mi *MessageInput) SetOnSend(fn OnSendFunc) {
 	mi.onSend = fn
@@ -171,7 +175,7 @@ func (mi *MessageInput) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	// Autocomplete navigation when dropdown is active.
 	if mi.acKind != acNone && mi.mentionsList != nil {
 		switch {
-		case name == mi.cfg.Keybinds.MessageInput.TabComplete:
+		case mi.cfg.Keybinds.MessageInput.TabComplete.Matches(name):
 			mi.completeAutocomplete()
 			return nil
 		case event.Key() == tcell.KeyUp:
@@ -180,29 +184,29 @@ func (mi *MessageInput) handleInput(event *tcell.EventKey) *tcell.EventKey {
 		case event.Key() == tcell.KeyDown:
 			mi.mentionsList.SelectNext()
 			return nil
-		case name == mi.cfg.Keybinds.MessageInput.Cancel:
+		case mi.cfg.Keybinds.MessageInput.Cancel.Matches(name):
 			mi.dismissAutocomplete()
 			return nil
 		}
 	}
 
-	switch name {
-	case mi.cfg.Keybinds.MessageInput.Send:
+	switch {
+	case mi.cfg.Keybinds.MessageInput.Send.Matches(name):
 		mi.dismissAutocomplete()
 		mi.send()
 		return nil
 
-	case mi.cfg.Keybinds.MessageInput.Newline:
+	case mi.cfg.Keybinds.MessageInput.Newline.Matches(name):
 		// Transform Shift+Enter into plain Enter so TextArea adds a newline.
 		return tcell.NewEventKey(tcell.KeyEnter, '\n', tcell.ModNone)
 
-	case mi.cfg.Keybinds.MessageInput.OpenFilePicker:
+	case mi.cfg.Keybinds.MessageInput.OpenFilePicker.Matches(name):
 		if mi.onOpenFilePicker != nil {
 			mi.onOpenFilePicker()
 		}
 		return nil
 
-	case mi.cfg.Keybinds.MessageInput.Paste:
+	case mi.cfg.Keybinds.MessageInput.Paste.Matches(name):
 		text, err := clipboard.ReadText()
 		if err == nil && text != "" {
 			current := mi.GetText()
@@ -210,7 +214,7 @@ func (mi *MessageInput) handleInput(event *tcell.EventKey) *tcell.EventKey {
 		}
 		return nil
 
-	case mi.cfg.Keybinds.MessageInput.Cancel:
+	case mi.cfg.Keybinds.MessageInput.Cancel.Matches(name):
 		if mi.mode != InputModeNormal {
 			mi.cancelMode()
 			return nil
@@ -8,9 +8,9 @@ import (
 
 func newTestInput() *MessageInput {
 	cfg := &config.Config{}
-	cfg.Keybinds.MessageInput.Send = "Enter"
-	cfg.Keybinds.MessageInput.Newline = "Shift+Enter"
-	cfg.Keybinds.MessageInput.Cancel = "Escape"
+	cfg.Keybinds.MessageInput.Send = config.Keybind{"Enter"}
+	cfg.Keybinds.MessageInput.Newline = config.Keybind{"Shift+Enter"}
+	cfg.Keybinds.MessageInput.Cancel = config.Keybind{"Escape"}
 	return NewMessageInput(cfg)
 }
 
@@ -0,0 +1,109 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/slack-go/slack"
+
+	"github.com/m96-chan/Slacko/internal/markdown"
+)
+
+// OnPreviewRequestFunc is called when a message links to a URL that has no
+// cached preview yet. The handler should fetch the linked message (if the URL
+// is a Slack permalink) and deliver it via SetMessagePreview.
+type OnPreviewRequestFunc func(url string)
+
+// MessagePreview is the content of a Slack message referenced by a permalink,
+// rendered as a quote card under the message that links to it.
+type MessagePreview struct {
+	Author      string
+	ChannelName string // display label, e.g. "#general" or a DM partner's name
+	Timestamp   string // Slack ts of the linked message
+	Text        string // raw mrkdwn text
+}
+
+// maxPreviewLines is the maximum number of text lines shown in a preview card.
+const maxPreviewLines = 4
+
+// SetOnPreviewRequest sets the callback for fetching linked message previews.
+func (ml *MessagesList) SetOnPreviewRequest(fn OnPreviewRequestFunc) {
+	ml.onPreviewRequest = fn
+}
+
+// SetMessagePreview caches the preview for a permalink URL and re-renders.
+func (ml *MessagesList) SetMessagePreview(url string, preview MessagePreview) {
+	ml.previews[url] = preview
+	ml.render()
+}
+
+// formatPreviews renders quote cards for the Slack messages linked from msg.
+// Links without a cached preview are requested once; links that Slack has
+// already unfurled as an attachment are skipped.
+func (ml *MessagesList) formatPreviews(msg slack.Message) string {
+	if msg.Text == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, link := range extractLinks(msg.Text) {
+		if unfurled(msg.Attachments, link) {
+			continue
+		}
+		preview, ok := ml.previews[link]
+		if !ok {
+			if !ml.previewRequested[link] && ml.onPreviewRequest != nil {
+				ml.previewRequested[link] = true
+				ml.onPreviewRequest(link)
+			}
+			continue
+		}
+		b.WriteString(ml.formatPreview(preview))
+	}
+	return b.String()
+}
+
+// formatPreview renders a single preview card: a header with author, channel
+// and time followed by the (truncated) message text.
+func (ml *MessagesList) formatPreview(p MessagePreview) string {
+	theme := ml.cfg.Theme.MessagesList
+
+	header := p.Author
+	if p.ChannelName != "" {
+		header += " in " + p.ChannelName
+	}
+	if t := parseSlackTimestamp(p.Timestamp); !t.IsZero() {
+		header += " · " + t.Format("Jan 2, 2006")
+		if ml.cfg.Timestamps.Format != "" {
+			header += " " + t.Format(ml.cfg.Timestamps.Format)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "  ┃ %s%s%s\n", theme.AttachmentTitle.Tag(), tview.Escape(header), theme.AttachmentTitle.Reset())
+
+	text := p.Text
+	if len(text) > maxAttachmentTextLen {
+		text = text[:maxAttachmentTextLen] + "…"
+	}
+	rendered := markdown.Render(text, ml.users, ml.channelNames, false, "", ml.mdColors)
+	lines := strings.Split(rendered, "\n")
+	if len(lines) > maxPreviewLines {
+		lines = append(lines[:maxPreviewLines], "…")
+	}
+	for _, line := range lines {
+		fmt.Fprintf(&b, "  ┃ %s%s%s\n", theme.AttachmentText.Tag(), line, theme.AttachmentText.Reset())
+	}
+	return b.String()
+}
+
+// unfurled reports whether Slack already attached a preview for link.
+func unfurled(attachments []slack.Attachment, link string) bool {
+	for _, att := range attachments {
+		if att.FromURL == link || att.OriginalURL == link {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+const testPermalink = "https://team.slack.com/archives/C1/p1700000000000100"
+
+func TestPreviewRequestedOnce(t *testing.T) {
+	ml := NewMessagesList(testConfig())
+	var requested []string
+	ml.SetOnPreviewRequest(func(url string) {
+		requested = append(requested, url)
+	})
+
+	ml.SetMessages("C2", []slack.Message{
+		{Msg: slack.Msg{Timestamp: "1700000100.000000", User: "U1", Text: "look <" + testPermalink + ">"}},
+	}, nil)
+	ml.render()
+
+	if len(requested) != 1 || requested[0] != testPermalink {
+		t.Fatalf("requested = %v, want [%s]", requested, testPermalink)
+	}
+}
+
+func TestPreviewCardRendered(t *testing.T) {
+	ml := NewMessagesList(testConfig())
+	ml.SetMessages("C2", []slack.Message{
+		{Msg: slack.Msg{Timestamp: "1700000100.000000", User: "U1", Text: "look <" + testPermalink + ">"}},
+	}, nil)
+
+	ml.SetMessagePreview(testPermalink, MessagePreview{
+		Author:      "alice",
+		ChannelName: "#general",
+		Timestamp:   "1700000000.000100",
+		Text:        "deploy is done",
+	})
+
+	text := ml.GetText(true)
+	if !strings.Contains(text, "alice in #general") {
+		t.Errorf("preview header missing, got %q", text)
+	}
+	if !strings.Contains(text, "┃ deploy is done") {
+		t.Errorf("preview body missing, got %q", text)
+	}
+}
+
+func TestPreviewSkippedWhenUnfurled(t *testing.T) {
+	ml := NewMessagesList(testConfig())
+	requested := 0
+	ml.SetOnPreviewRequest(func(string) { requested++ })
+
+	ml.SetMessages("C2", []slack.Message{
+		{Msg: slack.Msg{
+			Timestamp:   "1700000100.000000",
+			Text:        "<" + testPermalink + ">",
+			Attachments: []slack.Attachment{{FromURL: testPermalink, Text: "unfurled"}},
+		}},
+	}, nil)
+
+	if requested != 0 {
+		t.Errorf("unfurled link should not be requested, got %d requests", requested)
+	}
+}
+
+func TestFormatPreviewTruncatesLines(t *testing.T) {
+	ml := NewMessagesList(testConfig())
+	out := ml.formatPreview(MessagePreview{Author: "bob", Text: "1\n2\n3\n4\n5\n6"})
+	if strings.Contains(out, "5") {
+		t.Errorf("preview should be truncated to %d lines, got %q", maxPreviewLines, out)
+	}
+	if !strings.Contains(out, "…") {
+		t.Errorf("truncated preview should end with ellipsis, got %q", out)
+	}
+}
@@ -2,6 +2,7 @@ package chat
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -32,6 +33,9 @@ type OnReactionRemoveRequestFunc func(channelID, timestamp, reaction string)
 // OnFileOpenRequestFunc is called when the user wants to open/download a file.
 type OnFileOpenRequestFunc func(channelID string, file slack.File)
 
+// OnOpenLinkFunc is called when the user wants to open a link in a message.
+type OnOpenLinkFunc func(url string)
+
 // OnPinRequestFunc is called when the user wants to pin or unpin a message.
 type OnPinRequestFunc func(channelID, timestamp string, pinned bool)
 
@@ -50,6 +54,10 @@ type OnUserProfileRequestFunc func(userID string)
 // OnViewReactionsRequestFunc is called when the user wants to see who reacted.
 type OnViewReactionsRequestFunc func(channelID, timestamp string, reactions []slack.ItemReaction)
 
+// OnLoadHistoryFunc is called when the user scrolls past the loaded history.
+// edgeTS is the oldest (older=true) or newest (older=false) loaded timestamp.
+type OnLoadHistoryFunc func(channelID, edgeTS string, older bool)
+
 // MessagesList displays conversation messages with selection and scrolling.
 type MessagesList struct {
 	*tview.TextView
@@ -70,13 +78,22 @@ type MessagesList struct {
 	onReactionAddRequest    OnReactionAddRequestFunc
 	onReactionRemoveRequest OnReactionRemoveRequestFunc
 	onFileOpenRequest       OnFileOpenRequestFunc
+	onOpenLink              OnOpenLinkFunc
 	onPinRequest            OnPinRequestFunc
 	onStarRequest           OnStarRequestFunc
 	onYank                  OnYankFunc
 	onCopyPermalink         OnCopyPermalinkFunc
 	onUserProfileRequest    OnUserProfileRequestFunc
 	onViewReactionsRequest  OnViewReactionsRequestFunc
+	onLoadHistory           OnLoadHistoryFunc
+	onPreviewRequest        OnPreviewRequestFunc
 	lastReadTS              string // last-read timestamp for "New messages" separator
+	hasOlder                bool   // more history exists before the first loaded message
+	hasNewer                bool   // loaded window ends before the latest message
+	loadingHistory          bool   // a page request is in flight
+
+	previews         map[string]MessagePreview // permalink URL → fetched preview (cached across channels)
+	previewRequested map[string]bool           // permalink URLs already requested
 }
 
 // NewMessagesList creates a new messages list component.
@@ -90,6 +107,9 @@ func NewMessagesList(cfg *config.Config) *MessagesList {
 		pinnedSet:    make(map[string]bool),
 		starredSet:   make(map[string]bool),
 		mdColors:     mdColorsFromTheme(cfg.Theme.Markdown),
+
+		previews:         make(map[string]MessagePreview),
+		previewRequested: make(map[string]bool),
 	}
 
 	ml.SetDynamicColors(true)
@@ -167,6 +187,11 @@ func (ml *MessagesList) SetOnFileOpenRequest(fn OnFileOpenRequestFunc) {
 	ml.onFileOpenRequest = fn
 }
 
+// SetOnOpenLink sets the callback for opening links in messages without files.
+func (ml *MessagesList) SetOnOpenLink(fn OnOpenLinkFunc) {
+	ml.onOpenLink = fn
+}
+
 // SetOnPinRequest sets the callback for pin/unpin requests.
 func (ml *MessagesList) SetOnPinRequest(fn OnPinRequestFunc) {
 	ml.onPinRequest = fn
@@ -197,6 +222,95 @@ func (ml *MessagesList) SetOnViewReactionsRequest(fn OnViewReactionsRequestFunc)
 	ml.onViewReactionsRequest = fn
 }
 
+// SetOnLoadHistory sets the callback for paging in older or newer history.
+func (ml *MessagesList) SetOnLoadHistory(fn OnLoadHistoryFunc) {
+	ml.onLoadHistory = fn
+}
+
+// SetPaging records whether more history exists before and after the loaded
+// window. While hasNewer is set, live messages are not appended since they
+// would not be contiguous with the loaded window.
+func (ml *MessagesList) SetPaging(hasOlder, hasNewer bool) {
+	ml.hasOlder = hasOlder
+	ml.hasNewer = hasNewer
+	ml.render()
+}
+
+// HasNewer reports whether the loaded window ends before the latest message.
+func (ml *MessagesList) HasNewer() bool {
+	return ml.hasNewer
+}
+
+// SelectTimestamp selects and highlights the message with the given
+// timestamp, scrolling it into view. Returns false if it is not loaded.
+func (ml *MessagesList) SelectTimestamp(ts string) bool {
+	for i, msg := range ml.messages {
+		if msg.Timestamp == ts {
+			ml.selectedIdx = i
+			ml.render()
+			return true
+		}
+	}
+	return false
+}
+
+// SelectedMessage returns the selected message, if any.
+func (ml *MessagesList) SelectedMessage() (slack.Message, bool) {
+	if ml.selectedIdx < 0 || ml.selectedIdx >= len(ml.messages) {
+		return slack.Message{}, false
+	}
+	return ml.messages[ml.selectedIdx], true
+}
+
+// PrependHistory inserts an older page of history (newest-first, as returned
+// by the API) before the loaded messages, keeping the current selection.
+func (ml *MessagesList) PrependHistory(channelID string, messages []slack.Message, hasMore bool) {
+	if channelID != ml.channelID {
+		return
+	}
+	ml.loadingHistory = false
+	ml.hasOlder = hasMore
+
+	older := make([]slack.Message, 0, len(messages)+len(ml.messages))
+	for i := len(messages) - 1; i >= 0; i-- {
+		older = append(older, messages[i])
+	}
+	ml.messages = append(older, ml.messages...)
+	if ml.selectedIdx >= 0 {
+		ml.selectedIdx += len(messages)
+	}
+	ml.render()
+}
+
+// AppendHistory adds a newer page of history (newest-first, as returned by
+// the API) after the loaded messages, keeping the current selection.
+func (ml *MessagesList) AppendHistory(channelID string, messages []slack.Message, hasMore bool) {
+	if channelID != ml.channelID {
+		return
+	}
+	ml.loadingHistory = false
+	ml.hasNewer = hasMore
+
+	for i := len(messages) - 1; i >= 0; i-- {
+		ml.messages = append(ml.messages, messages[i])
+	}
+	ml.render()
+}
+
+// requestHistory asks for the next page in the given direction, unless one
+// is already being loaded.
+func (ml *MessagesList) requestHistory(older bool) {
+	if ml.loadingHistory || ml.onLoadHistory == nil || len(ml.messages) == 0 {
+		return
+	}
+	edge := ml.messages[len(ml.messages)-1].Timestamp
+	if older {
+		edge = ml.messages[0].Timestamp
+	}
+	ml.loadingHistory = true
+	ml.onLoadHistory(ml.channelID, edge, older)
+}
+
 // SetPinnedMessages sets the full set of pinned message timestamps for the current channel.
 func (ml *MessagesList) SetPinnedMessages(timestamps []string) {
 	ml.pinnedSet = make(map[string]bool, len(timestamps))
@@ -270,6 +384,9 @@ func (ml *MessagesList) SetMessages(channelID string, messages []slack.Message,
 	ml.selectedIdx = -1
 	ml.pinnedSet = make(map[string]bool)
 	ml.starredSet = make(map[string]bool)
+	ml.hasOlder = false
+	ml.hasNewer = false
+	ml.loadingHistory = false
 
 	// History returns newest-first; reverse to oldest-first.
 	ml.messages = make([]slack.Message, len(messages))
@@ -283,7 +400,7 @@ func (ml *MessagesList) SetMessages(channelID string, messages []slack.Message,
 
 // AppendMessage adds a new message to the bottom.
 func (ml *MessagesList) AppendMessage(channelID string, msg slack.Message) {
-	if channelID != ml.channelID {
+	if channelID != ml.channelID || ml.hasNewer {
 		return
 	}
 
@@ -407,6 +524,13 @@ func (ml *MessagesList) UpdateUsers(users map[string]slack.User) {
 	ml.render()
 }
 
+// applyTheme picks up theme and markdown color changes and re-renders the
+// current messages. The scroll position and selection are kept.
+func (ml *MessagesList) applyTheme() {
+	ml.mdColors = mdColorsFromTheme(ml.cfg.Theme.Markdown)
+	ml.render()
+}
+
 // render rebuilds the full text content from messages.
 func (ml *MessagesList) render() {
 	var b strings.Builder
@@ -531,6 +655,9 @@ func (ml *MessagesList) render() {
 			}, ml.cfg.ShowAttachmentLinks))
 		}
 
+		// Previews of linked Slack messages.
+		b.WriteString(ml.formatPreviews(msg))
+
 		// Reactions.
 		if len(msg.Reactions) > 0 {
 			b.WriteString("  ")
@@ -565,6 +692,10 @@ func (ml *MessagesList) render() {
 		prevTime = t
 	}
 
+	if ml.hasNewer {
+		fmt.Fprintf(&b, "\n  %s↓ newer messages not loaded%s\n", theme.SystemMessage.Tag(), theme.SystemMessage.Reset())
+	}
+
 	ml.SetText(b.String())
 
 	// Apply selection highlight.
@@ -578,22 +709,34 @@ func (ml *MessagesList) render() {
 
 // handleInput processes navigation keys.
 func (ml *MessagesList) handleInput(event *tcell.EventKey) *tcell.EventKey {
-	name := keys.Normalize(event.Name())
-
-	switch name {
-	case ml.cfg.Keybinds.MessagesList.Down:
-		ml.selectNext()
+	if ml.handleKey(keys.Normalize(event.Name()), 1) {
 		return nil
-	case ml.cfg.Keybinds.MessagesList.Up:
-		ml.selectPrev()
-		return nil
-	case ml.cfg.Keybinds.MessagesList.Cancel:
+	}
+	return event
+}
+
+// handleKey runs the action bound to name (a key or completed sequence),
+// moving count messages for movement actions. It reports whether name was
+// handled.
+func (ml *MessagesList) handleKey(name string, count int) bool {
+	switch {
+	case ml.cfg.Keybinds.MessagesList.Down.Matches(name):
+		for range count {
+			ml.selectNext()
+		}
+		return true
+	case ml.cfg.Keybinds.MessagesList.Up.Matches(name):
+		for range count {
+			ml.selectPrev()
+		}
+		return true
+	case ml.cfg.Keybinds.MessagesList.Cancel.Matches(name):
 		ml.selectedIdx = -1
 		ml.Highlight()
 		ml.ScrollToEnd()
-		return nil
+		return true
 
-	case ml.cfg.Keybinds.MessagesList.Reply:
+	case ml.cfg.Keybinds.MessagesList.Reply.Matches(name):
 		if ml.selectedIdx >= 0 && ml.selectedIdx < len(ml.messages) && ml.onReplyRequest != nil {
 			msg := ml.messages[ml.selectedIdx]
 			userName := resolveUserName(msg.User, msg.Username, msg.BotID, ml.users, ml.selfTeamID)
@@ -603,19 +746,19 @@ func (ml *MessagesList) handleInput(event *tcell.EventKey) *tcell.EventKey {
 				threadTS = msg.ThreadTimestamp
 			}
 			ml.onReplyRequest(ml.channelID, threadTS, userName)
-			return nil
+			return true
 		}
 
-	case ml.cfg.Keybinds.MessagesList.Edit:
+	case ml.cfg.Keybinds.MessagesList.Edit.Matches(name):
 		if ml.selectedIdx >= 0 && ml.selectedIdx < len(ml.messages) && ml.onEditRequest != nil {
 			msg := ml.messages[ml.selectedIdx]
 			if msg.User == ml.selfUserID {
 				ml.onEditRequest(ml.channelID, msg.Timestamp, msg.Text)
-				return nil
+				return true
 			}
 		}
 
-	case ml.cfg.Keybinds.MessagesList.Thread:
+	case ml.cfg.Keybinds.MessagesList.Thread.Matches(name):
 		if ml.selectedIdx >= 0 && ml.selectedIdx < len(ml.messages) && ml.onThreadRequest != nil {
 			msg := ml.messages[ml.selectedIdx]
 			threadTS := msg.Timestamp
@@ -623,84 +766,90 @@ func (ml *MessagesList) handleInput(event *tcell.EventKey) *tcell.EventKey {
 				threadTS = msg.ThreadTimestamp
 			}
 			ml.onThreadRequest(ml.channelID, threadTS)
-			return nil
+			return true
 		}
 
-	case ml.cfg.Keybinds.MessagesList.Reactions:
+	case ml.cfg.Keybinds.MessagesList.Reactions.Matches(name):
 		if ml.selectedIdx >= 0 && ml.selectedIdx < len(ml.messages) && ml.onReactionAddRequest != nil {
 			msg := ml.messages[ml.selectedIdx]
 			ml.onReactionAddRequest(ml.channelID, msg.Timestamp)
-			return nil
+			return true
 		}
 
-	case ml.cfg.Keybinds.MessagesList.RemoveReaction:
+	case ml.cfg.Keybinds.MessagesList.RemoveReaction.Matches(name):
 		if ml.selectedIdx >= 0 && ml.selectedIdx < len(ml.messages) && ml.onReactionRemoveRequest != nil {
 			msg := ml.messages[ml.selectedIdx]
 			// Find the first reaction from the current user and remove it.
 			for _, r := range msg.Reactions {
 				if containsStr(r.Users, ml.selfUserID) {
 					ml.onReactionRemoveRequest(ml.channelID, msg.Timestamp, r.Name)
-					return nil
+					return true
 				}
 			}
 		}
 
-	case ml.cfg.Keybinds.MessagesList.OpenFile:
+	case ml.cfg.Keybinds.MessagesList.OpenFile.Matches(name):
 		if ml.selectedIdx >= 0 && ml.selectedIdx < len(ml.messages) && ml.onFileOpenRequest != nil {
 			msg := ml.messages[ml.selectedIdx]
 			if len(msg.Files) > 0 {
 				ml.onFileOpenRequest(ml.channelID, msg.Files[0])
-				return nil
+				return true
+			}
+		}
+		if ml.selectedIdx >= 0 && ml.selectedIdx < len(ml.messages) && ml.onOpenLink != nil {
+			if links := extractLinks(ml.messages[ml.selectedIdx].Text); len(links) > 0 {
+				ml.onOpenLink(links[0])
+				return true
 			}
 		}
 
-	case ml.cfg.Keybinds.MessagesList.Pin:
+	case ml.cfg.Keybinds.MessagesList.Pin.Matches(name):
 		if ml.selectedIdx >= 0 && ml.selectedIdx < len(ml.messages) && ml.onPinRequest != nil {
 			msg := ml.messages[ml.selectedIdx]
 			ml.onPinRequest(ml.channelID, msg.Timestamp, !ml.pinnedSet[msg.Timestamp])
-			return nil
+			return true
 		}
 
-	case ml.cfg.Keybinds.MessagesList.Star:
+	case ml.cfg.Keybinds.MessagesList.Star.Matches(name):
 		if ml.selectedIdx >= 0 && ml.selectedIdx < len(ml.messages) && ml.onStarRequest != nil {
 			msg := ml.messages[ml.selectedIdx]
 			ml.onStarRequest(ml.channelID, msg.Timestamp, !ml.starredSet[msg.Timestamp])
-			return nil
+			return true
 		}
 
-	case ml.cfg.Keybinds.MessagesList.Yank:
+	case ml.cfg.Keybinds.MessagesList.Yank.Matches(name):
 		if ml.selectedIdx >= 0 && ml.selectedIdx < len(ml.messages) && ml.onYank != nil {
 			ml.onYank(ml.messages[ml.selectedIdx].Text)
-			return nil
+			return true
 		}
 
-	case ml.cfg.Keybinds.MessagesList.CopyPermalink:
+	case ml.cfg.Keybinds.MessagesList.CopyPermalink.Matches(name):
 		if ml.selectedIdx >= 0 && ml.selectedIdx < len(ml.messages) && ml.onCopyPermalink != nil {
 			msg := ml.messages[ml.selectedIdx]
 			ml.onCopyPermalink(ml.channelID, msg.Timestamp)
-			return nil
+			return true
 		}
 
-	case ml.cfg.Keybinds.MessagesList.UserProfile:
+	case ml.cfg.Keybinds.MessagesList.UserProfile.Matches(name):
 		if ml.selectedIdx >= 0 && ml.selectedIdx < len(ml.messages) && ml.onUserProfileRequest != nil {
 			msg := ml.messages[ml.selectedIdx]
 			if msg.User != "" {
 				ml.onUserProfileRequest(msg.User)
-				return nil
+				return true
 			}
 		}
 
-	case ml.cfg.Keybinds.MessagesList.ViewReactions:
+	case ml.cfg.Keybinds.MessagesList.ViewReactions.Matches(name):
 		if ml.selectedIdx >= 0 && ml.selectedIdx < len(ml.messages) && ml.onViewReactionsRequest != nil {
 			msg := ml.messages[ml.selectedIdx]
 			if len(msg.Reactions) > 0 {
 				ml.onViewReactionsRequest(ml.channelID, msg.Timestamp, msg.Reactions)
-				return nil
+				return true
 			}
 		}
 	}
 
-	return event
+	return false
 }
 
 // selectNext moves selection to the next message.
@@ -713,6 +862,8 @@ func (ml *MessagesList) selectNext() {
 		ml.selectedIdx = len(ml.messages) - 1
 	} else if ml.selectedIdx < len(ml.messages)-1 {
 		ml.selectedIdx++
+	} else if ml.hasNewer {
+		ml.requestHistory(false)
 	}
 	ml.Highlight(ml.messages[ml.selectedIdx].Timestamp)
 	ml.ScrollToHighlight()
@@ -727,11 +878,26 @@ func (ml *MessagesList) selectPrev() {
 		ml.selectedIdx = len(ml.messages) - 1
 	} else if ml.selectedIdx > 0 {
 		ml.selectedIdx--
+	} else if ml.hasOlder {
+		ml.requestHistory(true)
 	}
 	ml.Highlight(ml.messages[ml.selectedIdx].Timestamp)
 	ml.ScrollToHighlight()
 }
 
+// linkRe matches Slack mrkdwn links: <https://example.com> or <https://example.com|label>.
+var linkRe = regexp.MustCompile(`<(https?://[^|>]+)(?:\|[^>]*)?>`)
+
+// extractLinks returns the URLs of all links in a Slack mrkdwn message, in
+// order of appearance.
+func extractLinks(text string) []string {
+	var links []string
+	for _, m := range linkRe.FindAllStringSubmatch(text, -1) {
+		links = append(links, m[1])
+	}
+	return links
+}
+
 // parseSlackTimestamp converts a Slack timestamp (e.g. "1234567890.000100")
 // to a time.Time.
 func parseSlackTimestamp(ts string) time.Time {
@@ -1,6 +1,7 @@
 package chat
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -678,3 +679,116 @@ func makeMsg(ts, user, text string) slack.Message {
 	msg.Text = text
 	return msg
 }
+
+func TestSelectTimestamp(t *testing.T) {
+	ml := NewMessagesList(&config.Config{})
+	ml.SetMessages("C1", []slack.Message{
+		{Msg: slack.Msg{Timestamp: "3.0", Text: "c"}},
+		{Msg: slack.Msg{Timestamp: "2.0", Text: "b"}},
+		{Msg: slack.Msg{Timestamp: "1.0", Text: "a"}},
+	}, nil)
+
+	if !ml.SelectTimestamp("2.0") {
+		t.Fatal("SelectTimestamp(2.0) = false, want true")
+	}
+	if ml.selectedIdx != 1 {
+		t.Errorf("selectedIdx = %d, want 1", ml.selectedIdx)
+	}
+	if ml.SelectTimestamp("9.0") {
+		t.Error("SelectTimestamp(9.0) = true, want false")
+	}
+}
+
+func TestPrependAndAppendHistory(t *testing.T) {
+	ml := NewMessagesList(&config.Config{})
+	ml.SetMessages("C1", []slack.Message{
+		{Msg: slack.Msg{Timestamp: "5.0"}},
+		{Msg: slack.Msg{Timestamp: "4.0"}},
+	}, nil)
+	ml.SetPaging(true, true)
+	ml.SelectTimestamp("4.0")
+
+	ml.PrependHistory("C1", []slack.Message{
+		{Msg: slack.Msg{Timestamp: "3.0"}},
+		{Msg: slack.Msg{Timestamp: "2.0"}},
+	}, false)
+	ml.AppendHistory("C1", []slack.Message{
+		{Msg: slack.Msg{Timestamp: "7.0"}},
+		{Msg: slack.Msg{Timestamp: "6.0"}},
+	}, false)
+
+	want := []string{"2.0", "3.0", "4.0", "5.0", "6.0", "7.0"}
+	if len(ml.messages) != len(want) {
+		t.Fatalf("messages len = %d, want %d", len(ml.messages), len(want))
+	}
+	for i, ts := range want {
+		if ml.messages[i].Timestamp != ts {
+			t.Errorf("messages[%d] = %q, want %q", i, ml.messages[i].Timestamp, ts)
+		}
+	}
+	if ml.messages[ml.selectedIdx].Timestamp != "4.0" {
+		t.Errorf("selection moved to %q, want 4.0", ml.messages[ml.selectedIdx].Timestamp)
+	}
+	if ml.hasOlder || ml.HasNewer() {
+		t.Error("paging flags should be cleared when no more history")
+	}
+
+	// Pages for another channel are ignored.
+	ml.AppendHistory("C2", []slack.Message{{Msg: slack.Msg{Timestamp: "8.0"}}}, false)
+	if len(ml.messages) != len(want) {
+		t.Errorf("page for other channel was applied")
+	}
+}
+
+func TestAppendMessageSkippedWhileDetached(t *testing.T) {
+	ml := NewMessagesList(&config.Config{})
+	ml.SetMessages("C1", []slack.Message{{Msg: slack.Msg{Timestamp: "1.0"}}}, nil)
+	ml.SetPaging(false, true)
+
+	ml.AppendMessage("C1", slack.Message{Msg: slack.Msg{Timestamp: "9.0"}})
+	if len(ml.messages) != 1 {
+		t.Errorf("live message appended while newer history is not loaded")
+	}
+}
+
+func TestLoadHistoryRequests(t *testing.T) {
+	ml := NewMessagesList(&config.Config{})
+	ml.SetMessages("C1", []slack.Message{
+		{Msg: slack.Msg{Timestamp: "2.0"}},
+		{Msg: slack.Msg{Timestamp: "1.0"}},
+	}, nil)
+	ml.SetPaging(true, true)
+
+	var calls []string
+	ml.SetOnLoadHistory(func(channelID, edgeTS string, older bool) {
+		calls = append(calls, fmt.Sprintf("%s/%s/%v", channelID, edgeTS, older))
+	})
+
+	ml.SelectTimestamp("1.0")
+	ml.selectPrev()
+	ml.selectPrev() // in flight: no duplicate request
+	if len(calls) != 1 || calls[0] != "C1/1.0/true" {
+		t.Fatalf("older request calls = %v", calls)
+	}
+
+	ml.PrependHistory("C1", nil, false)
+	ml.SelectTimestamp("2.0")
+	ml.selectNext()
+	if len(calls) != 2 || calls[1] != "C1/2.0/false" {
+		t.Errorf("newer request calls = %v", calls)
+	}
+}
+
+func TestExtractLinks(t *testing.T) {
+	text := "see <https://team.slack.com/archives/C1/p1700000000000100|this> and <https://example.com> but not <@U1> or <#C2|general>"
+	links := extractLinks(text)
+	want := []string{"https://team.slack.com/archives/C1/p1700000000000100", "https://example.com"}
+	if len(links) != len(want) {
+		t.Fatalf("extractLinks() = %v, want %v", links, want)
+	}
+	for i := range want {
+		if links[i] != want[i] {
+			t.Errorf("links[%d] = %q, want %q", i, links[i], want[i])
+		}
+	}
+}
@@ -101,29 +101,29 @@ func (pp *PinsPicker) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
 	switch {
-	case name == pp.cfg.Keybinds.PinsPicker.Close:
+	case pp.cfg.Keybinds.PinsPicker.Close.Matches(name):
 		pp.close()
 		return nil
 
-	case name == pp.cfg.Keybinds.PinsPicker.Select:
+	case pp.cfg.Keybinds.PinsPicker.Select.Matches(name):
 		pp.selectCurrent()
 		return nil
 
-	case name == pp.cfg.Keybinds.PinsPicker.Up || event.Key() == tcell.KeyUp:
+	case pp.cfg.Keybinds.PinsPicker.Up.Matches(name) || event.Key() == tcell.KeyUp:
 		cur := pp.list.GetCurrentItem()
 		if cur > 0 {
 			pp.list.SetCurrentItem(cur - 1)
 		}
 		return nil
 
-	case name == pp.cfg.Keybinds.PinsPicker.Down || event.Key() == tcell.KeyDown:
+	case pp.cfg.Keybinds.PinsPicker.Down.Matches(name) || event.Key() == tcell.KeyDown:
 		cur := pp.list.GetCurrentItem()
 		if cur < pp.list.GetItemCount()-1 {
 			pp.list.SetCurrentItem(cur + 1)
 		}
 		return nil
 
-	case name == pp.cfg.Keybinds.PinnedMessages:
+	case pp.cfg.Keybinds.PinnedMessages.Matches(name):
 		// Toggle: pressing the keybind again closes the picker.
 		pp.close()
 		return nil
@@ -2,11 +2,13 @@ package chat
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/slack-go/slack"
 
 	"github.com/m96-chan/Slacko/internal/config"
 	"github.com/m96-chan/Slacko/internal/ui/keys"
@@ -19,26 +21,66 @@ type SearchResultEntry struct {
 	UserName    string
 	Timestamp   string
 	Text        string
+	File        *slack.File // set for results from the Files tab; ChannelName is then a display label
 }
 
-// SearchPicker is a modal popup for searching Slack messages.
+// SearchKind selects which Slack search API a query runs against.
+type SearchKind int
+
+const (
+	SearchKindMessages SearchKind = iota // search.messages
+	SearchKindFiles                      // search.files
+)
+
+// Sort orders accepted by the Slack search APIs.
+const (
+	SearchSortTimestamp = "timestamp"
+	SearchSortScore     = "score"
+)
+
+// SearchRequest describes one page of a search. Results are delivered back
+// with ShowResults together with the request they answer, so that responses
+// for outdated queries can be discarded.
+type SearchRequest struct {
+	Query string
+	Kind  SearchKind
+	Sort  string // SearchSortTimestamp or SearchSortScore
+	Page  int    // 1-based
+}
+
+// SearchPicker is a modal popup for searching Slack messages and files.
 type SearchPicker struct {
 	*tview.Flex
-	cfg      *config.Config
-	input    *tview.InputField
-	list     *tview.List
-	status   *tview.TextView
-	results  []SearchResultEntry
-	onSelect func(channelID, timestamp string)
-	onSearch func(query string)
-	onClose  func()
-	debounce *time.Timer
+	cfg          *config.Config
+	input        *tview.InputField
+	list         *tview.List
+	status       *tview.TextView
+	results      []SearchResultEntry
+	onSelect     func(channelID, timestamp string)
+	onFileSelect func(file slack.File)
+	onSearch     func(req SearchRequest)
+	onClose      func()
+	debounce     *time.Timer
+
+	kind    SearchKind
+	sort    string
+	page    int  // last page shown
+	hasMore bool // more pages are available
+	loading bool // a page request is in flight
+
+	channelNames []string // completion candidates for in:
+	userNames    []string // completion candidates for from:
+	completions  []string // candidates for the token being completed
+	completeIdx  int      // next candidate to apply on repeated Tab
+	completeBase string   // input text before the completed token
+	completing   bool     // the input is being changed by completeFilter
 }
 
 // NewSearchPicker creates a new search picker component.
 func NewSearchPicker(cfg *config.Config) *SearchPicker {
 	sp := &SearchPicker{
-		cfg: cfg,
+		cfg:  cfg,
+		sort: SearchSortTimestamp,
 	}
 
 	sp.input = tview.NewInputField()
@@ -61,7 +103,8 @@ func NewSearchPicker(cfg *config.Config) *SearchPicker {
 		AddItem(sp.input, 1, 0, true).
 		AddItem(sp.list, 0, 1, false).
 		AddItem(sp.status, 1, 0, false)
-	sp.SetBorder(true).SetTitle(" Search Messages ")
+	sp.SetBorder(true)
+	sp.updateTitle()
 
 	return sp
 }
@@ -71,40 +114,203 @@ func (sp *SearchPicker) SetOnSelect(fn func(channelID, timestamp string)) {
 	sp.onSelect = fn
 }
 
+// SetOnFileSelect sets the callback for selecting a result in the Files tab.
+func (sp *SearchPicker) SetOnFileSelect(fn func(file slack.File)) {
+	sp.onFileSelect = fn
+}
+
 // SetOnSearch sets the callback for triggering a search query.
-func (sp *SearchPicker) SetOnSearch(fn func(query string)) {
+func (sp *SearchPicker) SetOnSearch(fn func(req SearchRequest)) {
 	sp.onSearch = fn
 }
 
+// SetCompletions sets the channel and user names offered when completing
+// in: and from: filters.
+func (sp *SearchPicker) SetCompletions(channelNames, userNames []string) {
+	sp.channelNames = append([]string(nil), channelNames...)
+	sp.userNames = append([]string(nil), userNames...)
+	sort.Strings(sp.channelNames)
+	sort.Strings(sp.userNames)
+}
+
+// SetQuery fills the input with query and searches immediately.
+func (sp *SearchPicker) SetQuery(query string) {
+	sp.input.SetText(query)
+	if sp.debounce != nil {
+		sp.debounce.Stop()
+	}
+	if query != "" {
+		sp.search(1)
+	}
+}
+
 // SetOnClose sets the callback for closing the picker.
 func (sp *SearchPicker) SetOnClose(fn func()) {
 	sp.onClose = fn
 }
 
-// Reset clears the input, results, and shows filter hints.
+// Reset clears the input, results, and shows filter hints. The selected tab
+// and sort order are kept.
 func (sp *SearchPicker) Reset() {
 	sp.input.SetText("")
 	sp.list.Clear()
 	sp.results = nil
+	sp.page = 0
+	sp.hasMore = false
+	sp.loading = false
+	sp.resetCompletion()
 	sp.SetStatus(filterHelpText())
 }
 
-// SetResults populates the list with search results.
+// SetResults populates the list with a single page of search results.
 func (sp *SearchPicker) SetResults(results []SearchResultEntry) {
-	sp.results = results
+	sp.results = nil
 	sp.list.Clear()
+	sp.page = 1
+	sp.hasMore = false
+	sp.loading = false
+	sp.addResults(results)
+	if sp.list.GetItemCount() > 0 {
+		sp.list.SetCurrentItem(0)
+	}
+}
+
+// ShowResults displays the results for req: page 1 replaces the list, later
+// pages are appended. Results for a request that no longer matches the
+// current query, tab or sort order are ignored. It reports whether the
+// results were shown.
+func (sp *SearchPicker) ShowResults(req SearchRequest, results []SearchResultEntry, total int, hasMore bool) bool {
+	if req != sp.currentRequest(req.Page) {
+		return false
+	}
+
+	if req.Page <= 1 {
+		sp.SetResults(results)
+	} else {
+		sp.addResults(results)
+	}
+	sp.page = req.Page
+	sp.hasMore = hasMore
+	sp.loading = false
+
+	status := fmt.Sprintf("%d results", total)
+	if hasMore {
+		status = fmt.Sprintf("%d of %d results — move past the end to load more", len(sp.results), total)
+	}
+	sp.SetStatus(status)
+	return true
+}
+
+// SearchFailed clears the in-flight state after a failed request.
+func (sp *SearchPicker) SearchFailed(req SearchRequest) {
+	if req != sp.currentRequest(req.Page) {
+		return
+	}
+	sp.loading = false
+	sp.SetStatus("Search failed")
+}
+
+// addResults appends entries to the list.
+func (sp *SearchPicker) addResults(results []SearchResultEntry) {
+	sp.results = append(sp.results, results...)
 	for _, r := range results {
 		timeStr := r.Timestamp
 		if t := parseSlackTimestamp(r.Timestamp); !t.IsZero() {
 			timeStr = t.Format(sp.cfg.Timestamps.Format)
 		}
-		main := fmt.Sprintf("#%s  @%s  %s", r.ChannelName, r.UserName, timeStr)
-		secondary := truncateText(r.Text, 70)
-		sp.list.AddItem(main, secondary, 0, nil)
+		var main, secondary string
+		if r.File != nil {
+			main = fmt.Sprintf("%s %s  @%s  %s", fileIcon(r.File.Name, sp.cfg.AsciiIcons), r.File.Name, r.UserName, timeStr)
+			secondary = truncateText(r.Text, 70)
+			if r.ChannelName != "" {
+				secondary = r.ChannelName + "  " + secondary
+			}
+		} else {
+			main = fmt.Sprintf("#%s  @%s  %s", r.ChannelName, r.UserName, timeStr)
+			secondary = truncateText(r.Text, 70)
+		}
+		sp.list.AddItem(tview.Escape(main), tview.Escape(secondary), 0, nil)
 	}
-	if sp.list.GetItemCount() > 0 {
-		sp.list.SetCurrentItem(0)
+}
+
+// currentRequest returns the request for the given page of the current
+// query, tab and sort order.
+func (sp *SearchPicker) currentRequest(page int) SearchRequest {
+	return SearchRequest{
+		Query: strings.TrimSpace(sp.input.GetText()),
+		Kind:  sp.kind,
+		Sort:  sp.sort,
+		Page:  page,
+	}
+}
+
+// search requests the given page of the current query.
+func (sp *SearchPicker) search(page int) {
+	req := sp.currentRequest(page)
+	if req.Query == "" || sp.onSearch == nil {
+		return
 	}
+	sp.loading = true
+	if page > 1 {
+		sp.SetStatus("Loading more...")
+	} else {
+		sp.SetStatus("Searching...")
+	}
+	sp.onSearch(req)
+}
+
+// loadMore requests the next page if one is available.
+func (sp *SearchPicker) loadMore() {
+	if !sp.hasMore || sp.loading {
+		return
+	}
+	sp.search(sp.page + 1)
+}
+
+// toggleKind switches between the Messages and Files tabs and re-runs the
+// current query.
+func (sp *SearchPicker) toggleKind() {
+	if sp.kind == SearchKindMessages {
+		sp.kind = SearchKindFiles
+	} else {
+		sp.kind = SearchKindMessages
+	}
+	sp.updateTitle()
+	sp.rerun()
+}
+
+// toggleSort switches between newest-first and relevance ordering and re-runs
+// the current query.
+func (sp *SearchPicker) toggleSort() {
+	if sp.sort == SearchSortTimestamp {
+		sp.sort = SearchSortScore
+	} else {
+		sp.sort = SearchSortTimestamp
+	}
+	sp.updateTitle()
+	sp.rerun()
+}
+
+// rerun clears the results and searches page 1 of the current query.
+func (sp *SearchPicker) rerun() {
+	sp.list.Clear()
+	sp.results = nil
+	sp.page = 0
+	sp.hasMore = false
+	sp.search(1)
+}
+
+// updateTitle shows the active tab and sort order in the border title.
+func (sp *SearchPicker) updateTitle() {
+	tabs := " [Messages]  Files "
+	if sp.kind == SearchKindFiles {
+		tabs = " Messages  [Files] "
+	}
+	order := "newest"
+	if sp.sort == SearchSortScore {
+		order = "relevance"
+	}
+	sp.SetTitle(" Search:" + tview.Escape(tabs) + "· " + order + " ")
 }
 
 // SetStatus updates the status text at the bottom of the picker.
@@ -117,29 +323,43 @@ func (sp *SearchPicker) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
 	switch {
-	case name == sp.cfg.Keybinds.SearchPicker.Close:
+	case sp.cfg.Keybinds.SearchPicker.Close.Matches(name):
 		sp.close()
 		return nil
 
-	case name == sp.cfg.Keybinds.SearchPicker.Select:
+	case sp.cfg.Keybinds.SearchPicker.Select.Matches(name):
 		sp.selectCurrent()
 		return nil
 
-	case name == sp.cfg.Keybinds.SearchPicker.Up || event.Key() == tcell.KeyUp:
+	case sp.cfg.Keybinds.SearchPicker.Up.Matches(name) || event.Key() == tcell.KeyUp:
 		cur := sp.list.GetCurrentItem()
 		if cur > 0 {
 			sp.list.SetCurrentItem(cur - 1)
 		}
 		return nil
 
-	case name == sp.cfg.Keybinds.SearchPicker.Down || event.Key() == tcell.KeyDown:
+	case sp.cfg.Keybinds.SearchPicker.Down.Matches(name) || event.Key() == tcell.KeyDown:
 		cur := sp.list.GetCurrentItem()
 		if cur < sp.list.GetItemCount()-1 {
 			sp.list.SetCurrentItem(cur + 1)
+		} else {
+			sp.loadMore()
 		}
 		return nil
 
-	case name == sp.cfg.Keybinds.Search:
+	case sp.cfg.Keybinds.SearchPicker.ToggleFiles.Matches(name):
+		sp.toggleKind()
+		return nil
+
+	case sp.cfg.Keybinds.SearchPicker.ToggleSort.Matches(name):
+		sp.toggleSort()
+		return nil
+
+	case sp.cfg.Keybinds.SearchPicker.Complete.Matches(name):
+		sp.completeFilter()
+		return nil
+
+	case sp.cfg.Keybinds.Search.Matches(name):
 		// Ctrl+S while picker is open -> close it.
 		sp.close()
 		return nil
@@ -154,9 +374,15 @@ func (sp *SearchPicker) onInputChanged(text string) {
 		sp.debounce.Stop()
 	}
 
+	// Any edit other than our own completion starts a new completion.
+	if !sp.completing {
+		sp.resetCompletion()
+	}
+
 	if text == "" {
 		sp.list.Clear()
 		sp.results = nil
+		sp.hasMore = false
 		sp.SetStatus(filterHelpText())
 		return
 	}
@@ -170,7 +396,7 @@ func (sp *SearchPicker) onInputChanged(text string) {
 
 	sp.debounce = time.AfterFunc(300*time.Millisecond, func() {
 		if sp.onSearch != nil {
-			sp.onSearch(text)
+			sp.onSearch(SearchRequest{Query: strings.TrimSpace(text), Kind: sp.kind, Sort: sp.sort, Page: 1})
 		}
 	})
 }
@@ -183,7 +409,11 @@ func (sp *SearchPicker) selectCurrent() {
 	}
 
 	entry := sp.results[cur]
-	if sp.onSelect != nil {
+	if entry.File != nil {
+		if sp.onFileSelect != nil {
+			sp.onFileSelect(*entry.File)
+		}
+	} else if sp.onSelect != nil {
 		sp.onSelect(entry.ChannelID, entry.Timestamp)
 	}
 	sp.close()
@@ -201,7 +431,7 @@ func (sp *SearchPicker) close() {
 
 // filterHelpText returns the full help text listing available search filters.
 func filterHelpText() string {
-	return "Filters: from:@user  in:#channel  has:reaction/link/pin  before:YYYY-MM-DD  after:YYYY-MM-DD"
+	return "Filters: from:@user  in:#channel  has:reaction/link/pin  before:YYYY-MM-DD  after:YYYY-MM-DD  (Tab completes)"
 }
 
 // filterHints maps each recognized filter prefix to its hint text.
@@ -241,3 +471,87 @@ func truncateText(text string, maxLen int) string {
 	}
 	return text
 }
+
+// hasValues lists the values offered when completing has: filters.
+var hasValues = []string{"link", "pin", "reaction", "star"}
+
+// resetCompletion forgets the candidates of the last Tab completion.
+func (sp *SearchPicker) resetCompletion() {
+	sp.completions = nil
+	sp.completeIdx = 0
+	sp.completeBase = ""
+}
+
+// completeFilter completes the filter token at the end of the input. The
+// first Tab applies the first candidate; repeated Tabs cycle through the rest.
+func (sp *SearchPicker) completeFilter() {
+	if sp.completions == nil {
+		text := sp.input.GetText()
+		start := strings.LastIndexAny(text, " \t") + 1
+		candidates := sp.filterCandidates(text[start:], time.Now())
+		if len(candidates) == 0 {
+			return
+		}
+		sp.completions = candidates
+		sp.completeIdx = 0
+		sp.completeBase = text[:start]
+	}
+
+	candidate := sp.completions[sp.completeIdx]
+	sp.completeIdx = (sp.completeIdx + 1) % len(sp.completions)
+	sp.completing = true
+	sp.input.SetText(sp.completeBase + candidate)
+	sp.completing = false
+
+	if len(sp.completions) > 1 {
+		sp.SetStatus(tview.Escape(strings.Join(sp.completions, "  ")))
+	}
+}
+
+// filterCandidates returns the completions for a single filter token such as
+// "in:#gen" or "from:al". Tokens that are not filters yield nil.
+func (sp *SearchPicker) filterCandidates(token string, now time.Time) []string {
+	prefix, value, ok := strings.Cut(token, ":")
+	if !ok {
+		return nil
+	}
+	prefix = strings.ToLower(prefix)
+
+	var names []string
+	var sigil string
+	switch prefix {
+	case "in":
+		names, sigil = sp.channelNames, "#"
+	case "from":
+		names, sigil = sp.userNames, "@"
+	case "has":
+		names = hasValues
+	case "before", "after", "on":
+		names = []string{
+			now.Format("2006-01-02"),
+			now.AddDate(0, 0, -1).Format("2006-01-02"),
+			now.AddDate(0, 0, -7).Format("2006-01-02"),
+			now.AddDate(0, -1, 0).Format("2006-01-02"),
+		}
+	default:
+		return nil
+	}
+
+	value = strings.ToLower(strings.TrimPrefix(value, sigil))
+	limit := sp.cfg.AutocompleteLimit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var out []string
+	for _, name := range names {
+		if !strings.HasPrefix(strings.ToLower(name), value) {
+			continue
+		}
+		out = append(out, prefix+":"+sigil+name)
+		if len(out) >= limit {
+			break
+		}
+	}
+	return out
+}
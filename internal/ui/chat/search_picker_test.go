@@ -3,6 +3,10 @@ package chat
 import (
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/slack-go/slack"
 
 	"github.com/m96-chan/Slacko/internal/config"
 )
@@ -168,7 +172,7 @@ func TestSearchPickerCallbacks(t *testing.T) {
 	closeCalled := false
 
 	sp.SetOnSelect(func(ch, ts string) { selectCh = ch; selectTs = ts })
-	sp.SetOnSearch(func(q string) { searchQuery = q })
+	sp.SetOnSearch(func(req SearchRequest) { searchQuery = req.Query })
 	sp.SetOnClose(func() { closeCalled = true })
 
 	sp.onClose()
@@ -181,8 +185,126 @@ func TestSearchPickerCallbacks(t *testing.T) {
 		t.Errorf("onSelect got (%q, %q), want (%q, %q)", selectCh, selectTs, "C1", "123.456")
 	}
 
-	sp.onSearch("test query")
+	sp.onSearch(SearchRequest{Query: "test query", Page: 1})
 	if searchQuery != "test query" {
 		t.Errorf("onSearch got %q, want %q", searchQuery, "test query")
 	}
 }
+
+func searchTestConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Keybinds.SearchPicker.Down = config.Keybind{"Ctrl+N"}
+	cfg.Keybinds.SearchPicker.ToggleFiles = config.Keybind{"Ctrl+F"}
+	cfg.Keybinds.SearchPicker.ToggleSort = config.Keybind{"Ctrl+R"}
+	cfg.Keybinds.SearchPicker.Complete = config.Keybind{"Tab"}
+	return cfg
+}
+
+func TestSearchPickerLoadMore(t *testing.T) {
+	sp := NewSearchPicker(searchTestConfig())
+	var reqs []SearchRequest
+	sp.SetOnSearch(func(req SearchRequest) { reqs = append(reqs, req) })
+
+	sp.SetQuery("deploy")
+	if len(reqs) != 1 || reqs[0].Page != 1 || reqs[0].Sort != SearchSortTimestamp {
+		t.Fatalf("initial requests = %+v", reqs)
+	}
+
+	sp.ShowResults(reqs[0], []SearchResultEntry{{ChannelID: "C1", Text: "a"}}, 2, true)
+	sp.handleInput(tcell.NewEventKey(tcell.KeyCtrlN, 0, tcell.ModCtrl))
+	sp.handleInput(tcell.NewEventKey(tcell.KeyCtrlN, 0, tcell.ModCtrl)) // in flight: no duplicate
+	if len(reqs) != 2 || reqs[1].Page != 2 {
+		t.Fatalf("load more requests = %+v", reqs)
+	}
+
+	sp.ShowResults(reqs[1], []SearchResultEntry{{ChannelID: "C1", Text: "b"}}, 2, false)
+	if sp.list.GetItemCount() != 2 {
+		t.Errorf("list count = %d, want 2 after appending page 2", sp.list.GetItemCount())
+	}
+}
+
+func TestSearchPickerIgnoresStaleResults(t *testing.T) {
+	sp := NewSearchPicker(searchTestConfig())
+	sp.SetOnSearch(func(SearchRequest) {})
+	sp.SetQuery("new")
+
+	stale := SearchRequest{Query: "old", Kind: SearchKindMessages, Sort: SearchSortTimestamp, Page: 1}
+	if sp.ShowResults(stale, []SearchResultEntry{{Text: "x"}}, 1, false) {
+		t.Error("results for an outdated query should be ignored")
+	}
+	if sp.list.GetItemCount() != 0 {
+		t.Errorf("list count = %d, want 0", sp.list.GetItemCount())
+	}
+}
+
+func TestSearchPickerToggles(t *testing.T) {
+	sp := NewSearchPicker(searchTestConfig())
+	var reqs []SearchRequest
+	sp.SetOnSearch(func(req SearchRequest) { reqs = append(reqs, req) })
+	sp.SetQuery("report")
+
+	sp.handleInput(tcell.NewEventKey(tcell.KeyCtrlF, 0, tcell.ModCtrl))
+	sp.handleInput(tcell.NewEventKey(tcell.KeyCtrlR, 0, tcell.ModCtrl))
+	if len(reqs) != 3 {
+		t.Fatalf("requests = %+v, want 3", reqs)
+	}
+	if reqs[1].Kind != SearchKindFiles {
+		t.Errorf("toggle files: kind = %v, want files", reqs[1].Kind)
+	}
+	if reqs[2].Kind != SearchKindFiles || reqs[2].Sort != SearchSortScore {
+		t.Errorf("toggle sort: request = %+v", reqs[2])
+	}
+}
+
+func TestSearchPickerFileSelect(t *testing.T) {
+	sp := NewSearchPicker(&config.Config{})
+	var opened string
+	sp.SetOnFileSelect(func(f slack.File) { opened = f.ID })
+	sp.SetOnSelect(func(string, string) { t.Error("message select should not fire for files") })
+
+	sp.SetResults([]SearchResultEntry{{File: &slack.File{ID: "F1", Name: "a.txt"}}})
+	sp.selectCurrent()
+	if opened != "F1" {
+		t.Errorf("opened file = %q, want F1", opened)
+	}
+}
+
+func TestFilterCandidates(t *testing.T) {
+	sp := NewSearchPicker(&config.Config{})
+	sp.SetCompletions([]string{"random", "general", "gen-ops"}, []string{"alice", "bob"})
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		token string
+		want  []string
+	}{
+		{"in:#gen", []string{"in:#gen-ops", "in:#general"}},
+		{"in:ran", []string{"in:#random"}},
+		{"from:a", []string{"from:@alice"}},
+		{"has:r", []string{"has:reaction"}},
+		{"before:2026-03-09", []string{"before:2026-03-09"}},
+		{"hello", nil},
+		{"unknown:x", nil},
+	}
+	for _, tt := range tests {
+		got := sp.filterCandidates(tt.token, now)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("filterCandidates(%q) = %v, want %v", tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestCompleteFilterCycles(t *testing.T) {
+	sp := NewSearchPicker(&config.Config{})
+	sp.SetCompletions([]string{"general", "gen-ops"}, nil)
+	sp.input.SetText("deploy in:gen")
+
+	sp.completeFilter()
+	if got := sp.input.GetText(); got != "deploy in:#gen-ops" {
+		t.Fatalf("first completion = %q", got)
+	}
+	sp.completeFilter()
+	if got := sp.input.GetText(); got != "deploy in:#general" {
+		t.Errorf("second completion = %q", got)
+	}
+}
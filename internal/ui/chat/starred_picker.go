@@ -112,33 +112,33 @@ func (sp *StarredPicker) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
 	switch {
-	case name == sp.cfg.Keybinds.StarredPicker.Close:
+	case sp.cfg.Keybinds.StarredPicker.Close.Matches(name):
 		sp.close()
 		return nil
 
-	case name == sp.cfg.Keybinds.StarredPicker.Select:
+	case sp.cfg.Keybinds.StarredPicker.Select.Matches(name):
 		sp.selectCurrent()
 		return nil
 
-	case name == sp.cfg.Keybinds.StarredPicker.Unstar:
+	case sp.cfg.Keybinds.StarredPicker.Unstar.Matches(name):
 		sp.unstarCurrent()
 		return nil
 
-	case name == sp.cfg.Keybinds.StarredPicker.Up || event.Key() == tcell.KeyUp:
+	case sp.cfg.Keybinds.StarredPicker.Up.Matches(name) || event.Key() == tcell.KeyUp:
 		cur := sp.list.GetCurrentItem()
 		if cur > 0 {
 			sp.list.SetCurrentItem(cur - 1)
 		}
 		return nil
 
-	case name == sp.cfg.Keybinds.StarredPicker.Down || event.Key() == tcell.KeyDown:
+	case sp.cfg.Keybinds.StarredPicker.Down.Matches(name) || event.Key() == tcell.KeyDown:
 		cur := sp.list.GetCurrentItem()
 		if cur < sp.list.GetItemCount()-1 {
 			sp.list.SetCurrentItem(cur + 1)
 		}
 		return nil
 
-	case name == sp.cfg.Keybinds.StarredItems:
+	case sp.cfg.Keybinds.StarredItems.Matches(name):
 		// Toggle: pressing the keybind again closes the picker.
 		sp.close()
 		return nil
@@ -2,6 +2,7 @@ package chat
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/m96-chan/Slacko/internal/config"
 	"github.com/rivo/tview"
@@ -14,6 +15,8 @@ type StatusBar struct {
 	connStatus   string
 	typingText   string
 	presenceText string
+	pendingKeys  string
+	badgesText   string
 }
 
 // NewStatusBar creates a themed status bar.
@@ -21,19 +24,22 @@ func NewStatusBar(cfg *config.Config) *StatusBar {
 	tv := tview.NewTextView().
 		SetDynamicColors(true)
 
-	// Apply status bar theme.
-	_, bg, _ := cfg.Theme.StatusBar.Background.Style.Decompose()
-	fg, _, _ := cfg.Theme.StatusBar.Text.Style.Decompose()
-	tv.SetBackgroundColor(bg)
-	tv.SetTextColor(fg)
-
 	sb := &StatusBar{
 		TextView: tv,
 		cfg:      cfg,
 	}
+	sb.applyTheme()
 	return sb
 }
 
+// applyTheme sets the status bar colors from the current theme.
+func (sb *StatusBar) applyTheme() {
+	_, bg, _ := sb.cfg.Theme.StatusBar.Background.Style.Decompose()
+	fg, _, _ := sb.cfg.Theme.StatusBar.Text.Style.Decompose()
+	sb.SetBackgroundColor(bg)
+	sb.SetTextColor(fg)
+}
+
 // SetConnectionStatus updates the connection status text.
 func (sb *StatusBar) SetConnectionStatus(s string) {
 	sb.connStatus = s
@@ -56,14 +62,47 @@ func (sb *StatusBar) SetChannelPresence(online, total int) {
 	sb.render()
 }
 
+// SetWorkspaceBadges shows the unread badges of the other connected
+// workspaces, and the workspaces that need a new sign-in. Workspaces without
+// unread messages are left out.
+func (sb *StatusBar) SetWorkspaceBadges(entries []WorkspaceEntry) {
+	var parts []string
+	for _, e := range entries {
+		if e.Unread > 0 || e.Mentions > 0 || e.Expired {
+			parts = append(parts, e.label())
+		}
+	}
+	text := strings.Join(parts, "  ")
+	if text == sb.badgesText {
+		return
+	}
+	sb.badgesText = text
+	sb.render()
+}
+
+// SetPendingKeys shows a partially typed key sequence or count.
+func (sb *StatusBar) SetPendingKeys(s string) {
+	if s == sb.pendingKeys {
+		return
+	}
+	sb.pendingKeys = s
+	sb.render()
+}
+
 // render rebuilds the status bar text from current state.
 func (sb *StatusBar) render() {
 	text := " " + sb.connStatus
 	if sb.presenceText != "" {
 		text += "  |  " + sb.presenceText
 	}
+	if sb.badgesText != "" {
+		text += "  |  " + sb.badgesText
+	}
 	if sb.typingText != "" {
 		text += "  |  " + sb.typingText
 	}
+	if sb.pendingKeys != "" {
+		text += "  |  " + sb.pendingKeys
+	}
 	sb.TextView.SetText(text)
 }
@@ -70,3 +70,39 @@ func TestStatusBarRenderAll(t *testing.T) {
 		t.Errorf("text = %q, want %q", got, want)
 	}
 }
+
+func TestStatusBarSetPendingKeys(t *testing.T) {
+	sb := NewStatusBar(&config.Config{})
+	sb.SetConnectionStatus("Online")
+	sb.SetPendingKeys("5 g")
+
+	if got, want := sb.GetText(false), " Online  |  5 g"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+
+	sb.SetPendingKeys("")
+	if got, want := sb.GetText(false), " Online"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+}
+
+func TestStatusBarSetWorkspaceBadges(t *testing.T) {
+	sb := NewStatusBar(&config.Config{})
+	sb.SetConnectionStatus("Online")
+	sb.SetWorkspaceBadges([]WorkspaceEntry{
+		{ID: "T2", Name: "Beta", Unread: 3, Mentions: 1},
+		{ID: "T3", Name: "Gamma"},
+		{ID: "T4", Name: "Delta", Mentions: 2},
+	})
+
+	got := sb.GetText(false)
+	want := " Online  |  Beta (3, @1)  Delta (@2)"
+	if got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+
+	sb.SetWorkspaceBadges(nil)
+	if got := sb.GetText(false); got != " Online" {
+		t.Errorf("text = %q, want %q", got, " Online")
+	}
+}
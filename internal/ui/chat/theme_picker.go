@@ -0,0 +1,159 @@
+package chat
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/m96-chan/Slacko/internal/config"
+	"github.com/m96-chan/Slacko/internal/ui/keys"
+)
+
+// ThemePicker is a modal popup for choosing a theme preset. Moving through
+// the list previews each theme on the live UI; closing without selecting
+// restores the theme that was active when the picker opened.
+type ThemePicker struct {
+	*tview.Flex
+	cfg       *config.Config
+	list      *tview.List
+	status    *tview.TextView
+	themes    []string
+	original  string
+	onPreview func(name string)
+	onSelect  func(name string, save bool)
+	onClose   func()
+}
+
+// NewThemePicker creates a new theme picker component.
+func NewThemePicker(cfg *config.Config) *ThemePicker {
+	tp := &ThemePicker{
+		cfg: cfg,
+	}
+
+	tp.list = tview.NewList()
+	tp.list.ShowSecondaryText(false)
+	tp.list.SetHighlightFullLine(true)
+	tp.list.SetWrapAround(false)
+	tp.list.SetInputCapture(tp.handleInput)
+	tp.list.SetChangedFunc(func(index int, _, _ string, _ rune) {
+		if index >= 0 && index < len(tp.themes) && tp.onPreview != nil {
+			tp.onPreview(tp.themes[index])
+		}
+	})
+
+	tp.status = tview.NewTextView()
+	tp.status.SetText(" Enter apply, w apply and save, Esc cancel")
+
+	tp.Flex = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tp.list, 0, 1, true).
+		AddItem(tp.status, 1, 0, false)
+	tp.SetBorder(true).SetTitle(" Theme ")
+	tp.SetInputCapture(tp.handleInput)
+
+	return tp
+}
+
+// SetOnPreview sets the callback invoked with the highlighted theme name.
+func (tp *ThemePicker) SetOnPreview(fn func(name string)) {
+	tp.onPreview = fn
+}
+
+// SetOnSelect sets the callback invoked when a theme is chosen. save is true
+// when the choice should also be written to the config file.
+func (tp *ThemePicker) SetOnSelect(fn func(name string, save bool)) {
+	tp.onSelect = fn
+}
+
+// SetOnClose sets the callback for closing the picker.
+func (tp *ThemePicker) SetOnClose(fn func()) {
+	tp.onClose = fn
+}
+
+// SetThemes populates the list and highlights current, which is restored
+// when the picker is cancelled.
+func (tp *ThemePicker) SetThemes(themes []string, current string) {
+	tp.themes = themes
+	tp.original = current
+
+	// Populate without previewing each item as it is added.
+	onPreview := tp.onPreview
+	tp.onPreview = nil
+	tp.list.Clear()
+	selected := 0
+	for i, name := range themes {
+		label := name
+		if name == current {
+			label += " (current)"
+			selected = i
+		}
+		tp.list.AddItem(label, "", 0, nil)
+	}
+	if len(themes) > 0 {
+		tp.list.SetCurrentItem(selected)
+	}
+	tp.onPreview = onPreview
+}
+
+// Selected returns the highlighted theme name, or "" when the list is empty.
+func (tp *ThemePicker) Selected() string {
+	cur := tp.list.GetCurrentItem()
+	if cur < 0 || cur >= len(tp.themes) {
+		return ""
+	}
+	return tp.themes[cur]
+}
+
+// handleInput processes keybindings for the theme picker.
+func (tp *ThemePicker) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	name := keys.Normalize(event.Name())
+
+	switch {
+	case tp.cfg.Keybinds.ThemePicker.Close.Matches(name):
+		tp.cancel()
+		return nil
+
+	case tp.cfg.Keybinds.ThemePicker.Select.Matches(name):
+		tp.selectCurrent(false)
+		return nil
+
+	case tp.cfg.Keybinds.ThemePicker.Save.Matches(name):
+		tp.selectCurrent(true)
+		return nil
+
+	case tp.cfg.Keybinds.ThemePicker.Up.Matches(name) || event.Key() == tcell.KeyUp:
+		cur := tp.list.GetCurrentItem()
+		if cur > 0 {
+			tp.list.SetCurrentItem(cur - 1)
+		}
+		return nil
+
+	case tp.cfg.Keybinds.ThemePicker.Down.Matches(name) || event.Key() == tcell.KeyDown:
+		cur := tp.list.GetCurrentItem()
+		if cur < tp.list.GetItemCount()-1 {
+			tp.list.SetCurrentItem(cur + 1)
+		}
+		return nil
+	}
+
+	return event
+}
+
+// selectCurrent chooses the highlighted theme.
+func (tp *ThemePicker) selectCurrent(save bool) {
+	name := tp.Selected()
+	if name == "" {
+		return
+	}
+	if tp.onSelect != nil {
+		tp.onSelect(name, save)
+	}
+}
+
+// cancel restores the original theme and closes the picker.
+func (tp *ThemePicker) cancel() {
+	if tp.Selected() != tp.original && tp.onPreview != nil {
+		tp.onPreview(tp.original)
+	}
+	if tp.onClose != nil {
+		tp.onClose()
+	}
+}
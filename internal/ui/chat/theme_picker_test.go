@@ -0,0 +1,92 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/m96-chan/Slacko/internal/config"
+)
+
+func newTestThemePicker() *ThemePicker {
+	cfg := &config.Config{}
+	cfg.Keybinds.ThemePicker.Close = config.Keybind{"Escape"}
+	cfg.Keybinds.ThemePicker.Up = config.Keybind{"Ctrl+P"}
+	cfg.Keybinds.ThemePicker.Down = config.Keybind{"Ctrl+N"}
+	cfg.Keybinds.ThemePicker.Select = config.Keybind{"Enter"}
+	cfg.Keybinds.ThemePicker.Save = config.Keybind{"Rune[w]"}
+	return NewThemePicker(cfg)
+}
+
+func TestThemePickerSetThemesHighlightsCurrent(t *testing.T) {
+	tp := newTestThemePicker()
+	var previews []string
+	tp.SetOnPreview(func(name string) { previews = append(previews, name) })
+
+	tp.SetThemes([]string{"default", "dark", "light"}, "dark")
+
+	if got := tp.Selected(); got != "dark" {
+		t.Errorf("Selected() = %q, want dark", got)
+	}
+	if main, _ := tp.list.GetItemText(1); main != "dark (current)" {
+		t.Errorf("item text = %q, want %q", main, "dark (current)")
+	}
+	if len(previews) != 0 {
+		t.Errorf("SetThemes previewed %v, want nothing", previews)
+	}
+}
+
+func TestThemePickerPreviewsAndRestores(t *testing.T) {
+	tp := newTestThemePicker()
+	var previews []string
+	closed := false
+	tp.SetOnPreview(func(name string) { previews = append(previews, name) })
+	tp.SetOnClose(func() { closed = true })
+	tp.SetThemes([]string{"default", "dark", "light"}, "default")
+
+	tp.handleInput(tcell.NewEventKey(tcell.KeyCtrlN, 0, tcell.ModCtrl))
+	tp.handleInput(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	tp.handleInput(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+
+	want := []string{"dark", "light", "default"}
+	if len(previews) != len(want) {
+		t.Fatalf("previews = %v, want %v", previews, want)
+	}
+	for i := range want {
+		if previews[i] != want[i] {
+			t.Errorf("previews = %v, want %v", previews, want)
+			break
+		}
+	}
+	if !closed {
+		t.Error("Escape should close the picker")
+	}
+}
+
+func TestThemePickerSelect(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    *tcell.EventKey
+		wantSave bool
+	}{
+		{"enter applies", tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), false},
+		{"w applies and saves", tcell.NewEventKey(tcell.KeyRune, 'w', tcell.ModNone), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp := newTestThemePicker()
+			var gotName string
+			var gotSave bool
+			tp.SetOnSelect(func(name string, save bool) { gotName, gotSave = name, save })
+			tp.SetThemes([]string{"default", "dark"}, "default")
+			tp.handleInput(tcell.NewEventKey(tcell.KeyCtrlN, 0, tcell.ModCtrl))
+
+			if tp.handleInput(tt.event) != nil {
+				t.Error("event should be consumed")
+			}
+			if gotName != "dark" || gotSave != tt.wantSave {
+				t.Errorf("onSelect(%q, %v), want (dark, %v)", gotName, gotSave, tt.wantSave)
+			}
+		})
+	}
+}
@@ -123,6 +123,12 @@ func (tv *ThreadView) FocusInput() {
 	tv.app.SetFocus(tv.replyInput)
 }
 
+// SetReplyText replaces the text of the reply input and focuses it.
+func (tv *ThreadView) SetReplyText(text string) {
+	tv.replyInput.SetText(text, true)
+	tv.FocusInput()
+}
+
 // SetMessages sets the thread messages and renders.
 func (tv *ThreadView) SetMessages(channelID, threadTS string, messages []slack.Message, users map[string]slack.User) {
 	tv.channelID = channelID
@@ -134,6 +140,19 @@ func (tv *ThreadView) SetMessages(channelID, threadTS string, messages []slack.M
 	tv.repliesView.ScrollToEnd()
 }
 
+// SelectTimestamp selects and highlights the reply with the given timestamp,
+// scrolling it into view. Returns false if it is not part of the thread.
+func (tv *ThreadView) SelectTimestamp(ts string) bool {
+	for i, msg := range tv.messages {
+		if msg.Timestamp == ts {
+			tv.selectedIdx = i
+			tv.render()
+			return true
+		}
+	}
+	return false
+}
+
 // AppendReply adds a reply to the thread.
 func (tv *ThreadView) AppendReply(msg slack.Message) {
 	tv.messages = append(tv.messages, msg)
@@ -189,6 +208,13 @@ func (tv *ThreadView) UpdateUsers(users map[string]slack.User) {
 	tv.render()
 }
 
+// applyTheme picks up theme and markdown color changes and re-renders the
+// open thread.
+func (tv *ThreadView) applyTheme() {
+	tv.mdColors = mdColorsFromTheme(tv.cfg.Theme.Markdown)
+	tv.render()
+}
+
 // render rebuilds the full text content from thread messages.
 func (tv *ThreadView) render() {
 	var b strings.Builder
@@ -294,37 +320,49 @@ func (tv *ThreadView) render() {
 
 // handleRepliesInput processes keybindings for the replies view.
 func (tv *ThreadView) handleRepliesInput(event *tcell.EventKey) *tcell.EventKey {
-	name := keys.Normalize(event.Name())
-
-	switch name {
-	case tv.cfg.Keybinds.ThreadView.Down:
-		tv.selectNext()
-		return nil
-	case tv.cfg.Keybinds.ThreadView.Up:
-		tv.selectPrev()
+	if tv.handleKey(keys.Normalize(event.Name()), 1) {
 		return nil
-	case tv.cfg.Keybinds.ThreadView.Reply:
+	}
+	return event
+}
+
+// handleKey runs the replies-view action bound to name (a key or completed
+// sequence), moving count replies for movement actions. It reports whether
+// name was handled.
+func (tv *ThreadView) handleKey(name string, count int) bool {
+	switch {
+	case tv.cfg.Keybinds.ThreadView.Down.Matches(name):
+		for range count {
+			tv.selectNext()
+		}
+		return true
+	case tv.cfg.Keybinds.ThreadView.Up.Matches(name):
+		for range count {
+			tv.selectPrev()
+		}
+		return true
+	case tv.cfg.Keybinds.ThreadView.Reply.Matches(name):
 		tv.FocusInput()
-		return nil
-	case tv.cfg.Keybinds.ThreadView.Close:
+		return true
+	case tv.cfg.Keybinds.ThreadView.Close.Matches(name):
 		tv.close()
-		return nil
+		return true
 	}
 
-	return event
+	return false
 }
 
 // handleReplyInput processes keybindings for the reply input.
 func (tv *ThreadView) handleReplyInput(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
-	switch name {
-	case tv.cfg.Keybinds.MessageInput.Send:
+	switch {
+	case tv.cfg.Keybinds.MessageInput.Send.Matches(name):
 		tv.sendReply()
 		return nil
-	case tv.cfg.Keybinds.MessageInput.Newline:
+	case tv.cfg.Keybinds.MessageInput.Newline.Matches(name):
 		return tcell.NewEventKey(tcell.KeyEnter, '\n', tcell.ModNone)
-	case tv.cfg.Keybinds.MessageInput.Cancel:
+	case tv.cfg.Keybinds.MessageInput.Cancel.Matches(name):
 		tv.FocusReplies()
 		return nil
 	}
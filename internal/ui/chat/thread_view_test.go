@@ -12,13 +12,13 @@ import (
 func newTestThreadView() *ThreadView {
 	app := tview.NewApplication()
 	cfg := &config.Config{}
-	cfg.Keybinds.ThreadView.Up = "Rune[k]"
-	cfg.Keybinds.ThreadView.Down = "Rune[j]"
-	cfg.Keybinds.ThreadView.Reply = "Rune[r]"
-	cfg.Keybinds.ThreadView.Close = "Escape"
-	cfg.Keybinds.MessageInput.Send = "Enter"
-	cfg.Keybinds.MessageInput.Newline = "Shift+Enter"
-	cfg.Keybinds.MessageInput.Cancel = "Escape"
+	cfg.Keybinds.ThreadView.Up = config.Keybind{"Rune[k]"}
+	cfg.Keybinds.ThreadView.Down = config.Keybind{"Rune[j]"}
+	cfg.Keybinds.ThreadView.Reply = config.Keybind{"Rune[r]"}
+	cfg.Keybinds.ThreadView.Close = config.Keybind{"Escape"}
+	cfg.Keybinds.MessageInput.Send = config.Keybind{"Enter"}
+	cfg.Keybinds.MessageInput.Newline = config.Keybind{"Shift+Enter"}
+	cfg.Keybinds.MessageInput.Cancel = config.Keybind{"Escape"}
 	cfg.Timestamps.Enabled = true
 	cfg.Timestamps.Format = "3:04PM"
 	return NewThreadView(app, cfg)
@@ -269,3 +269,18 @@ func TestThreadView_UpdateUsers(t *testing.T) {
 		t.Errorf("users len = %d, want 1", len(tv.users))
 	}
 }
+
+func TestThreadViewSelectTimestamp(t *testing.T) {
+	tv := newTestThreadView()
+	tv.SetMessages("C1", "1.0", []slack.Message{
+		{Msg: slack.Msg{Timestamp: "1.0"}},
+		{Msg: slack.Msg{Timestamp: "2.0"}},
+	}, nil)
+
+	if !tv.SelectTimestamp("2.0") || tv.selectedIdx != 1 {
+		t.Errorf("SelectTimestamp(2.0): selectedIdx = %d, want 1", tv.selectedIdx)
+	}
+	if tv.SelectTimestamp("3.0") {
+		t.Error("SelectTimestamp(3.0) = true, want false")
+	}
+}
@@ -179,18 +179,18 @@ func (up *UserProfilePanel) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
 	switch {
-	case name == up.cfg.Keybinds.UserProfilePanel.Close:
+	case up.cfg.Keybinds.UserProfilePanel.Close.Matches(name):
 		up.close()
 		return nil
 
-	case name == up.cfg.Keybinds.UserProfilePanel.OpenDM:
+	case up.cfg.Keybinds.UserProfilePanel.OpenDM.Matches(name):
 		if up.onOpenDM != nil && up.data.UserID != "" {
 			up.onOpenDM(up.data.UserID)
 			up.close()
 		}
 		return nil
 
-	case name == up.cfg.Keybinds.UserProfilePanel.CopyID:
+	case up.cfg.Keybinds.UserProfilePanel.CopyID.Matches(name):
 		if up.onCopyID != nil && up.data.UserID != "" {
 			up.onCopyID(up.data.UserID)
 		}
@@ -2,6 +2,7 @@ package chat
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -49,6 +50,8 @@ type View struct {
 	ChannelCreateForm  *ChannelCreateForm
 	InvitePicker       *InvitePicker
 	GroupDMPicker      *GroupDMPicker
+	ActivityPanel      *ActivityPanel
+	ThemePicker        *ThemePicker
 
 	outerFlex            *tview.Flex
 	contentFlex          *tview.Flex
@@ -68,6 +71,8 @@ type View struct {
 	channelCreateModal   tview.Primitive
 	inviteModal          tview.Primitive
 	groupDMModal         tview.Primitive
+	activityModal        tview.Primitive
+	themeModal           tview.Primitive
 	activePanel          Panel
 	onMarkRead           func()
 	onMarkAllRead        func()
@@ -76,6 +81,7 @@ type View struct {
 	onStarredItems       func()
 	onChannelInfo        func()
 	onChannelMembers     func()
+	onActivity           func()
 	channelsVisible      bool
 	threadVisible        bool
 	pickerVisible        bool
@@ -94,7 +100,11 @@ type View struct {
 	channelCreateVisible bool
 	inviteVisible        bool
 	groupDMVisible       bool
+	activityVisible      bool
+	themeVisible         bool
+	dialogVisible        bool
 	onSwitchWorkspace    func(workspaceID string)
+	keyMatcher           *keys.Matcher
 }
 
 // New creates the main chat view with the full flex layout.
@@ -114,6 +124,7 @@ func New(app *tview.Application, cfg *config.Config) *View {
 		app:             app,
 		cfg:             cfg,
 		channelsVisible: true,
+		keyMatcher:      keys.NewMatcher(time.Duration(cfg.Keybinds.Timeout) * time.Millisecond),
 	}
 
 	// Channel tree (left sidebar).
@@ -245,6 +256,39 @@ func New(app *tview.Application, cfg *config.Config) *View {
 			0, 2, true).
 		AddItem(nil, 0, 1, false)
 
+	// Activity panel (modal overlay).
+	v.ActivityPanel = NewActivityPanel(cfg)
+	v.ActivityPanel.SetOnClose(func() {
+		v.HideActivityPanel()
+	})
+
+	// Centered modal wrapper for the activity panel.
+	v.activityModal = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			AddItem(nil, 0, 1, false).
+			AddItem(v.ActivityPanel, 80, 0, true).
+			AddItem(nil, 0, 1, false),
+			0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	// Theme picker (modal overlay).
+	v.ThemePicker = NewThemePicker(cfg)
+	v.ThemePicker.SetOnClose(func() {
+		v.HideThemePicker()
+	})
+
+	// Small centered modal wrapper for the theme picker, so most of the
+	// UI stays visible while previewing.
+	v.themeModal = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			AddItem(nil, 0, 1, false).
+			AddItem(v.ThemePicker, 40, 0, true).
+			AddItem(nil, 0, 1, false),
+			len(config.ThemePresets)+3, 0, true).
+		AddItem(nil, 0, 1, false)
+
 	// Bookmarks picker (modal overlay).
 	v.BookmarksPicker = NewBookmarksPicker(cfg)
 	v.BookmarksPicker.SetOnClose(func() {
@@ -442,6 +486,11 @@ func (v *View) SetOnPinnedMessages(fn func()) {
 	v.onPinnedMessages = fn
 }
 
+// SetOnActivity sets the callback invoked when the user opens the activity inbox.
+func (v *View) SetOnActivity(fn func()) {
+	v.onActivity = fn
+}
+
 // SetOnStarredItems sets the callback invoked when the user opens the starred items popup.
 func (v *View) SetOnStarredItems(fn func()) {
 	v.onStarredItems = fn
@@ -469,18 +518,41 @@ func (v *View) FocusPanel(panel Panel) {
 	}
 }
 
+// ActivePanel returns the panel that has focus.
+func (v *View) ActivePanel() Panel {
+	return v.activePanel
+}
+
 // HandleKey processes chat-level keybindings. Returns nil to consume the event.
 func (v *View) HandleKey(event *tcell.EventKey) *tcell.EventKey {
 	name := keys.Normalize(event.Name())
 
+	modalVisible := v.pickerVisible || v.reactionVisible || v.filePickerVisible || v.searchVisible || v.pinsVisible || v.bookmarksVisible || v.starredVisible || v.membersVisible || v.userProfileVisible || v.channelInfoVisible || v.reactionUsersVisible || v.commandBarVisible || v.workspaceVisible || v.channelCreateVisible || v.inviteVisible || v.groupDMVisible || v.activityVisible || v.themeVisible || v.dialogVisible
+
+	// Skip Rune-based keybinds when text input is active so the user can type.
+	skipRune := (v.activePanel == PanelInput) ||
+		(v.activePanel == PanelThread && v.ThreadView.IsInputFocused())
+
+	// Resolve multi-key sequences and counts, except while typing into a
+	// modal or text input. seq is the completed sequence, or the key itself.
+	seq, count := name, 1
+	if !modalVisible && !(skipRune && event.Key() == tcell.KeyRune) {
+		result := v.keyMatcher.Feed(name, v.activeBindings())
+		v.showPendingKeys()
+		if result.Pending {
+			return nil
+		}
+		seq, count = result.Seq, result.Count
+	}
+
 	// Toggle channels sidebar (Ctrl+B — non-rune, always works).
-	if name == v.cfg.Keybinds.ToggleChannels {
+	if v.cfg.Keybinds.ToggleChannels.Matches(seq) {
 		v.ToggleChannels()
 		return nil
 	}
 
 	// Toggle channel picker (Ctrl+K — non-rune, always works).
-	if name == v.cfg.Keybinds.ChannelPicker {
+	if v.cfg.Keybinds.ChannelPicker.Matches(seq) {
 		if v.pickerVisible {
 			v.HidePicker()
 		} else {
@@ -490,7 +562,7 @@ func (v *View) HandleKey(event *tcell.EventKey) *tcell.EventKey {
 	}
 
 	// Toggle search picker (Ctrl+S — non-rune, always works).
-	if name == v.cfg.Keybinds.Search {
+	if v.cfg.Keybinds.Search.Matches(seq) {
 		if v.searchVisible {
 			v.HideSearchPicker()
 		} else {
@@ -500,7 +572,7 @@ func (v *View) HandleKey(event *tcell.EventKey) *tcell.EventKey {
 	}
 
 	// Toggle workspace picker (Ctrl+T — non-rune, always works).
-	if name == v.cfg.Keybinds.SwitchTeam {
+	if v.cfg.Keybinds.SwitchTeam.Matches(seq) {
 		if v.workspaceVisible {
 			v.HideWorkspacePicker()
 		} else {
@@ -510,7 +582,7 @@ func (v *View) HandleKey(event *tcell.EventKey) *tcell.EventKey {
 	}
 
 	// Toggle channel info (Ctrl+O — non-rune, always works).
-	if name == v.cfg.Keybinds.ChannelInfo {
+	if v.cfg.Keybinds.ChannelInfo.Matches(seq) {
 		if v.channelInfoVisible {
 			v.HideChannelInfo()
 		} else {
@@ -523,54 +595,51 @@ func (v *View) HandleKey(event *tcell.EventKey) *tcell.EventKey {
 	}
 
 	// Focus cycling (Ctrl-based, works even in text input).
-	if name == v.cfg.Keybinds.FocusPrevious {
+	if v.cfg.Keybinds.FocusPrevious.Matches(seq) {
 		v.cycleFocus(-1)
 		return nil
 	}
-	if name == v.cfg.Keybinds.FocusNext {
+	if v.cfg.Keybinds.FocusNext.Matches(seq) {
 		v.cycleFocus(1)
 		return nil
 	}
 
 	// When a modal or command bar is visible, all other keys go to its input.
-	if v.pickerVisible || v.reactionVisible || v.filePickerVisible || v.searchVisible || v.pinsVisible || v.bookmarksVisible || v.starredVisible || v.membersVisible || v.userProfileVisible || v.channelInfoVisible || v.reactionUsersVisible || v.commandBarVisible || v.workspaceVisible || v.channelCreateVisible || v.inviteVisible || v.groupDMVisible {
+	if modalVisible {
 		return event
 	}
 
-	// Skip Rune-based focus keybinds when text input is active so the user can type.
-	skipRune := (v.activePanel == PanelInput) ||
-		(v.activePanel == PanelThread && v.ThreadView.IsInputFocused())
 	if skipRune && event.Key() == tcell.KeyRune {
 		return event
 	}
 
 	// Close thread panel if visible.
-	if name == v.cfg.Keybinds.ToggleThread && v.threadVisible {
+	if v.cfg.Keybinds.ToggleThread.Matches(seq) && v.threadVisible {
 		v.CloseThread()
 		return nil
 	}
 
-	switch name {
-	case v.cfg.Keybinds.FocusChannels:
+	switch {
+	case v.cfg.Keybinds.FocusChannels.Matches(seq):
 		v.FocusPanel(PanelChannels)
 		return nil
-	case v.cfg.Keybinds.FocusMessages:
+	case v.cfg.Keybinds.FocusMessages.Matches(seq):
 		v.FocusPanel(PanelMessages)
 		return nil
-	case v.cfg.Keybinds.FocusInput:
+	case v.cfg.Keybinds.FocusInput.Matches(seq):
 		v.FocusPanel(PanelInput)
 		return nil
-	case v.cfg.Keybinds.MarkRead:
+	case v.cfg.Keybinds.MarkRead.Matches(seq):
 		if v.onMarkRead != nil {
 			v.onMarkRead()
 		}
 		return nil
-	case v.cfg.Keybinds.MarkAllRead:
+	case v.cfg.Keybinds.MarkAllRead.Matches(seq):
 		if v.onMarkAllRead != nil {
 			v.onMarkAllRead()
 		}
 		return nil
-	case v.cfg.Keybinds.PinnedMessages:
+	case v.cfg.Keybinds.PinnedMessages.Matches(seq):
 		if v.pinsVisible {
 			v.HidePinsPicker()
 		} else {
@@ -580,7 +649,17 @@ func (v *View) HandleKey(event *tcell.EventKey) *tcell.EventKey {
 			}
 		}
 		return nil
-	case v.cfg.Keybinds.StarredItems:
+	case v.cfg.Keybinds.Activity.Matches(seq):
+		if v.activityVisible {
+			v.HideActivityPanel()
+		} else {
+			v.ShowActivityPanel()
+			if v.onActivity != nil {
+				v.onActivity()
+			}
+		}
+		return nil
+	case v.cfg.Keybinds.StarredItems.Matches(seq):
 		if v.starredVisible {
 			v.HideStarredPicker()
 		} else {
@@ -590,12 +669,23 @@ func (v *View) HandleKey(event *tcell.EventKey) *tcell.EventKey {
 			}
 		}
 		return nil
-	case v.cfg.Keybinds.CommandMode:
+	case v.cfg.Keybinds.CommandMode.Matches(seq):
 		v.ShowCommandBar()
 		return nil
 	}
 
-	return event
+	// Not a global binding: plain keys go to the focused panel as usual.
+	if seq == name && count == 1 {
+		return event
+	}
+	if h := v.focusedKeyHandler(); h != nil && h.handleKey(seq, count) {
+		return nil
+	}
+	if seq == name {
+		v.repeatKey(event, count-1)
+		return event
+	}
+	return nil
 }
 
 // ToggleChannels shows or hides the channel tree sidebar.
@@ -684,6 +774,35 @@ func (v *View) HidePinsPicker() {
 	v.FocusPanel(v.activePanel)
 }
 
+// ShowActivityPanel shows the activity inbox modal overlay.
+func (v *View) ShowActivityPanel() {
+	v.activityVisible = true
+	v.ActivityPanel.Reset()
+	v.Pages.AddPage("activity", v.activityModal, true, true)
+	v.app.SetFocus(v.ActivityPanel.list)
+}
+
+// HideActivityPanel hides the activity inbox and restores focus.
+func (v *View) HideActivityPanel() {
+	v.activityVisible = false
+	v.Pages.RemovePage("activity")
+	v.FocusPanel(v.activePanel)
+}
+
+// ShowThemePicker shows the theme picker modal overlay.
+func (v *View) ShowThemePicker() {
+	v.themeVisible = true
+	v.Pages.AddPage("theme", v.themeModal, true, true)
+	v.app.SetFocus(v.ThemePicker.list)
+}
+
+// HideThemePicker hides the theme picker and restores focus.
+func (v *View) HideThemePicker() {
+	v.themeVisible = false
+	v.Pages.RemovePage("theme")
+	v.FocusPanel(v.activePanel)
+}
+
 // SetOnBookmarks sets the callback invoked when the user opens the bookmarks popup.
 func (v *View) SetOnBookmarks(fn func()) {
 	v.onBookmarks = fn
@@ -855,6 +974,50 @@ func (v *View) HideGroupDMPicker() {
 	v.FocusPanel(v.activePanel)
 }
 
+// ShowDialog shows p centered over the chat view at the given size, e.g. a
+// form that is not part of the view. HideDialog removes it.
+func (v *View) ShowDialog(p tview.Primitive, width, height int) {
+	modal := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			AddItem(nil, 0, 1, false).
+			AddItem(p, width, 0, true).
+			AddItem(nil, 0, 1, false),
+			height, 0, true).
+		AddItem(nil, 0, 1, false)
+	v.showDialogPage(modal, p)
+}
+
+// ShowConfirm asks a yes/no question in a dialog. confirm labels the button
+// that calls onConfirm; the other one only closes the dialog.
+func (v *View) ShowConfirm(text, confirm string, onConfirm func()) {
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{confirm, "Cancel"}).
+		SetDoneFunc(func(_ int, label string) {
+			v.HideDialog()
+			if label == confirm && onConfirm != nil {
+				onConfirm()
+			}
+		})
+	v.showDialogPage(modal, modal)
+}
+
+// showDialogPage adds page as the dialog overlay and focuses focus.
+func (v *View) showDialogPage(page, focus tview.Primitive) {
+	v.dialogVisible = true
+	v.Pages.AddPage("dialog", page, true, true)
+	v.app.SetFocus(focus)
+}
+
+// HideDialog removes the dialog shown with ShowDialog or ShowConfirm and
+// restores focus.
+func (v *View) HideDialog() {
+	v.dialogVisible = false
+	v.Pages.RemovePage("dialog")
+	v.FocusPanel(v.activePanel)
+}
+
 // ShowCommandBar shows the vim-style command bar at the bottom.
 func (v *View) ShowCommandBar() {
 	v.commandBarVisible = true
@@ -985,3 +1148,45 @@ func (v *View) applyBorderStyles() {
 		}
 	}
 }
+
+// ApplyConfig re-applies the shared config after it has been replaced in
+// place (e.g. by a config reload): theme colors, markdown colors and the key
+// sequence timeout. Keybinds and options are read from the config on use and
+// need no extra work. The open channel, thread, selection and scroll
+// position are kept.
+func (v *View) ApplyConfig() {
+	v.keyMatcher.Reset()
+	v.keyMatcher.SetTimeout(time.Duration(v.cfg.Keybinds.Timeout) * time.Millisecond)
+	v.StatusBar.SetPendingKeys("")
+
+	v.StatusBar.applyTheme()
+	v.MessageInput.applyTheme()
+	v.ChannelsTree.applyTheme()
+	v.MessagesList.applyTheme()
+	v.ThreadView.applyTheme()
+	v.applyBorderStyles()
+
+	secondary := v.cfg.Theme.Modal.SecondaryText.Foreground()
+	for _, list := range []*tview.List{
+		v.PinsPicker.list,
+		v.StarredPicker.list,
+		v.BookmarksPicker.list,
+		v.ActivityPanel.list,
+		v.SearchPicker.list,
+	} {
+		list.SetSecondaryTextColor(secondary)
+	}
+
+	inputBg := v.cfg.Theme.Modal.InputBackground.Background()
+	for _, input := range []*tview.InputField{
+		v.ChannelsPicker.input,
+		v.FilePicker.input,
+		v.GroupDMPicker.input,
+		v.InvitePicker.input,
+		v.MembersPicker.input,
+		v.ReactionsPicker.input,
+		v.SearchPicker.input,
+	} {
+		input.SetFieldBackgroundColor(inputBg)
+	}
+}
@@ -0,0 +1,94 @@
+package chat
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/slack-go/slack"
+
+	"github.com/m96-chan/Slacko/internal/config"
+)
+
+func TestApplyConfigKeepsState(t *testing.T) {
+	cfg := testConfig()
+	v := New(tview.NewApplication(), cfg)
+
+	msgs := make([]slack.Message, 5)
+	for i := range msgs {
+		msgs[i] = slack.Message{Msg: slack.Msg{Timestamp: fmt.Sprintf("%d.0", 5-i), Text: "m"}}
+	}
+	v.MessagesList.SetMessages("C1", msgs, nil)
+	v.MessagesList.SelectTimestamp("3.0")
+	selected := v.MessagesList.selectedIdx
+
+	// Replace the config in place, as a reload does.
+	newCfg := *cfg
+	newCfg.Theme = config.BuiltinTheme("light")
+	newCfg.Theme.StatusBar.Background.Style = tcell.StyleDefault.Background(tcell.ColorNavy)
+	newCfg.Keybinds.Timeout = 250
+	*cfg = newCfg
+
+	v.ApplyConfig()
+
+	if v.MessagesList.selectedIdx != selected {
+		t.Errorf("selectedIdx = %d, want %d", v.MessagesList.selectedIdx, selected)
+	}
+	if got := v.MessagesList.GetHighlights(); len(got) != 1 || got[0] != "3.0" {
+		t.Errorf("highlights = %v, want [3.0]", got)
+	}
+	if got := v.StatusBar.GetBackgroundColor(); got != tcell.ColorNavy {
+		t.Errorf("status bar background = %v, want navy", got)
+	}
+	if want := mdColorsFromTheme(newCfg.Theme.Markdown); v.ThreadView.mdColors != want {
+		t.Errorf("thread markdown colors = %+v, want %+v", v.ThreadView.mdColors, want)
+	}
+	if v.MessagesList.mdColors == mdColorsFromTheme(config.BuiltinTheme("default").Markdown) {
+		t.Error("messages list still uses the old markdown colors")
+	}
+	if got := v.keyMatcher.Timeout(); got != 250*time.Millisecond {
+		t.Errorf("key timeout = %v, want 250ms", got)
+	}
+}
+
+func TestShowConfirm(t *testing.T) {
+	v := New(tview.NewApplication(), testConfig())
+
+	confirmed := false
+	v.ShowConfirm("Remove Work?", "Remove", func() { confirmed = true })
+	if !v.dialogVisible || !v.Pages.HasPage("dialog") {
+		t.Fatal("confirm dialog not shown")
+	}
+
+	// Keys go to the dialog, not to the chat view's bindings.
+	key := tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone)
+	if got := v.HandleKey(key); got != key {
+		t.Error("key consumed by the chat view while a dialog is shown")
+	}
+
+	// The confirm button has focus first.
+	modal := v.Pages.GetPage("dialog").(*tview.Modal)
+	modal.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(p tview.Primitive) {})
+	if !confirmed {
+		t.Error("onConfirm not called")
+	}
+	if v.dialogVisible || v.Pages.HasPage("dialog") {
+		t.Error("dialog still shown after answering")
+	}
+}
+
+func TestShowDialog(t *testing.T) {
+	v := New(tview.NewApplication(), testConfig())
+
+	form := tview.NewForm()
+	v.ShowDialog(form, 60, 10)
+	if !v.dialogVisible || !v.Pages.HasPage("dialog") {
+		t.Fatal("dialog not shown")
+	}
+	v.HideDialog()
+	if v.dialogVisible || v.Pages.HasPage("dialog") {
+		t.Error("dialog still shown after HideDialog")
+	}
+}
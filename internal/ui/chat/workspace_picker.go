@@ -12,8 +12,29 @@ import (
 
 // WorkspaceEntry represents a workspace for display in the picker.
 type WorkspaceEntry struct {
-	ID   string
-	Name string
+	ID       string
+	Name     string
+	Unread   int  // unread messages outside muted channels
+	Mentions int  // mentions and unread DMs
+	Offline  bool // not connected
+	Expired  bool // signed out after its token could not be refreshed
+}
+
+// label returns the entry's name with its unread badge, e.g. "Acme (3, @1)".
+func (e WorkspaceEntry) label() string {
+	label := e.Name
+	switch {
+	case e.Unread > 0 && e.Mentions > 0:
+		label += fmt.Sprintf(" (%d, @%d)", e.Unread, e.Mentions)
+	case e.Unread > 0:
+		label += fmt.Sprintf(" (%d)", e.Unread)
+	case e.Mentions > 0:
+		label += fmt.Sprintf(" (@%d)", e.Mentions)
+	}
+	if e.Expired {
+		label += " (signed out)"
+	}
+	return label
 }
 
 // WorkspacePicker is a modal for selecting a workspace.
@@ -66,17 +87,24 @@ func (wp *WorkspacePicker) SetCurrentWorkspace(id string) {
 	wp.currentID = id
 }
 
-// SetWorkspaces populates the workspace list.
+// SetWorkspaces populates the workspace list. The highlighted entry is
+// kept when the list is refreshed, e.g. as unread badges change.
 func (wp *WorkspacePicker) SetWorkspaces(entries []WorkspaceEntry) {
+	selected := wp.list.GetCurrentItem()
 	wp.entries = entries
 	wp.list.Clear()
 	for _, e := range entries {
-		label := e.Name
+		label := e.label()
 		if e.ID == wp.currentID {
 			label += " (current)"
+		} else if e.Offline && !e.Expired {
+			label += " (offline)"
 		}
 		wp.list.AddItem(label, "", 0, nil)
 	}
+	if selected > 0 && selected < len(entries) {
+		wp.list.SetCurrentItem(selected)
+	}
 	if len(entries) == 0 {
 		wp.status.SetText(" No workspaces configured")
 	} else {
@@ -36,6 +36,32 @@ func TestWorkspacePickerSetWorkspaces(t *testing.T) {
 	}
 }
 
+func TestWorkspacePickerBadges(t *testing.T) {
+	wp := NewWorkspacePicker(&config.Config{})
+	wp.SetCurrentWorkspace("team-1")
+	wp.SetWorkspaces([]WorkspaceEntry{
+		{ID: "team-1", Name: "Alpha", Unread: 2},
+		{ID: "team-2", Name: "Beta", Unread: 5, Mentions: 1},
+		{ID: "team-3", Name: "Gamma", Mentions: 2},
+		{ID: "team-4", Name: "Delta", Offline: true},
+		{ID: "team-5", Name: "Echo", Offline: true, Expired: true},
+	})
+
+	want := []string{"Alpha (2) (current)", "Beta (5, @1)", "Gamma (@2)", "Delta (offline)", "Echo (signed out)"}
+	for i, w := range want {
+		if got, _ := wp.list.GetItemText(i); got != w {
+			t.Errorf("item %d = %q, want %q", i, got, w)
+		}
+	}
+
+	// Refreshing the badges keeps the highlighted entry.
+	wp.list.SetCurrentItem(2)
+	wp.SetWorkspaces([]WorkspaceEntry{{ID: "team-1"}, {ID: "team-2"}, {ID: "team-3"}})
+	if got := wp.list.GetCurrentItem(); got != 2 {
+		t.Errorf("current item after refresh = %d, want 2", got)
+	}
+}
+
 func TestWorkspacePickerSetWorkspacesEmpty(t *testing.T) {
 	wp := NewWorkspacePicker(&config.Config{})
 	wp.SetWorkspaces(nil)
@@ -1,9 +1,112 @@
 package keys
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
 
 // Normalize converts tcell key names to the config format.
-// tcell outputs "Ctrl-C" (hyphen) for bare Ctrl keys but config uses "Ctrl+C" (plus).
+// tcell outputs "Ctrl-C" (hyphen) for bare Ctrl keys but config uses "Ctrl+C" (plus),
+// and names the escape key "Esc" where config uses "Escape".
 func Normalize(name string) string {
-	return strings.ReplaceAll(name, "Ctrl-", "Ctrl+")
+	return Canonical(strings.ReplaceAll(name, "Ctrl-", "Ctrl+"))
+}
+
+// Canonical returns the config spelling of a key name read from a config
+// file. "Esc" is tcell's own name for the escape key and was the spelling
+// that matched before events were normalized, so it is kept working as
+// "Escape", also with modifiers.
+func Canonical(name string) string {
+	if name == "Esc" || strings.HasSuffix(name, "+Esc") {
+		name += "ape"
+	}
+	return name
+}
+
+// modifiers are the prefixes tcell puts in front of a key name.
+var modifiers = []string{"Shift+", "Alt+", "Meta+", "Ctrl+"}
+
+// keyNames holds the normalized names of all named (non-rune) tcell keys.
+var keyNames = func() map[string]bool {
+	names := make(map[string]bool, len(tcell.KeyNames))
+	for _, name := range tcell.KeyNames {
+		names[Normalize(name)] = true
+	}
+	return names
+}()
+
+// Validate reports whether name is a key name that tcell can produce, in
+// the config format (e.g. "Rune[j]", "Ctrl+W", "Shift+Enter", "Alt+Rune[x]").
+// The empty string (an unbound action) is valid, and so is "Esc" (see
+// Canonical).
+func Validate(name string) error {
+	name = Canonical(name)
+	if name == "" || keyNames[name] {
+		return nil
+	}
+
+	base := name
+	for {
+		trimmed := base
+		for _, m := range modifiers {
+			trimmed = strings.TrimPrefix(trimmed, m)
+		}
+		if trimmed == base {
+			break
+		}
+		base = trimmed
+	}
+
+	// Ctrl chords lose their "Ctrl-" prefix when combined with other
+	// modifiers: Shift+Ctrl+A.
+	if keyNames[base] || (strings.Contains(name, "Ctrl+") && keyNames["Ctrl+"+base]) {
+		return nil
+	}
+	if inner, ok := strings.CutPrefix(base, "Rune["); ok {
+		inner, ok = strings.CutSuffix(inner, "]")
+		if ok && utf8.RuneCountInString(inner) == 1 {
+			return nil
+		}
+		return fmt.Errorf("invalid key %q: Rune[...] must contain exactly one character", name)
+	}
+	if base == "" {
+		return fmt.Errorf("invalid key %q: modifier without a key", name)
+	}
+	return fmt.Errorf("unknown key %q", name)
+}
+
+// Split splits a key sequence such as "Rune[g] Rune[g]" into key names.
+// Names are separated by whitespace; "Rune[ ]" (the space bar) is kept
+// intact.
+func Split(seq string) []string {
+	var names []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			names = append(names, cur.String())
+			cur.Reset()
+		}
+	}
+
+	rs := []rune(seq)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		if strings.HasSuffix(cur.String(), "Rune[") && i+1 < len(rs) && rs[i+1] == ']' {
+			cur.WriteRune(r)
+			cur.WriteRune(']')
+			i++
+			continue
+		}
+		if unicode.IsSpace(r) {
+			flush()
+			continue
+		}
+		cur.WriteRune(r)
+	}
+	flush()
+	return names
 }
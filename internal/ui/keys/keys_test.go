@@ -12,6 +12,8 @@ func TestNormalize(t *testing.T) {
 		{"Rune[j]", "Rune[j]"},
 		{"Enter", "Enter"},
 		{"Escape", "Escape"},
+		{"Esc", "Escape"},
+		{"Alt+Esc", "Alt+Escape"},
 		{"Ctrl-Shift-A", "Ctrl+Shift-A"},
 	}
 
@@ -24,3 +26,19 @@ func TestNormalize(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate(t *testing.T) {
+	valid := []string{"", "Rune[j]", "Rune[?]", "Rune[]]", "Ctrl+W", "Enter", "Escape", "Shift+Enter", "Alt+Rune[x]", "Shift+Ctrl+A", "F5", "PgDn", "Backtab", "Esc", "Alt+Esc"}
+	for _, name := range valid {
+		if err := Validate(name); err != nil {
+			t.Errorf("Validate(%q) = %v, want nil", name, err)
+		}
+	}
+
+	invalid := []string{"j", "Ctrl-", "Rune[jk]", "Rune[]", "Ctrl+", "Rune[j"}
+	for _, name := range invalid {
+		if err := Validate(name); err == nil {
+			t.Errorf("Validate(%q) = nil, want error", name)
+		}
+	}
+}
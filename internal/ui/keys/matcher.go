@@ -0,0 +1,187 @@
+package keys
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is how long a Matcher waits for the next key of a sequence
+// when no timeout is configured.
+const DefaultTimeout = time.Second
+
+// maxCount caps count prefixes so a stuck key cannot overflow them.
+const maxCount = 9999
+
+// Result is the outcome of feeding one key press to a Matcher.
+type Result struct {
+	// Seq is the completed sequence (key names separated by spaces), or the
+	// key itself when it does not start or complete any binding.
+	Seq string
+	// Count is the count typed before the sequence, or 1.
+	Count int
+	// Pending is true when more keys are needed; Seq and Count are unset.
+	Pending bool
+}
+
+// Matcher resolves multi-key sequences ("Rune[g] Rune[g]") and vim-style
+// count prefixes ("5" then "Rune[j]") from individual key presses. It is not
+// safe for concurrent use; feed it from the UI goroutine.
+type Matcher struct {
+	timeout time.Duration
+	keys    []string
+	count   int
+	last    time.Time
+	now     func() time.Time
+}
+
+// NewMatcher returns a Matcher that abandons a partial sequence or count
+// after timeout without a key press.
+func NewMatcher(timeout time.Duration) *Matcher {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Matcher{timeout: timeout, now: time.Now}
+}
+
+// Timeout returns how long the matcher waits for the next key.
+func (m *Matcher) Timeout() time.Duration {
+	return m.timeout
+}
+
+// SetTimeout changes how long the matcher waits for the next key.
+func (m *Matcher) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	m.timeout = timeout
+}
+
+// Feed processes a normalized key name against the bound sequences.
+//
+// A bound sequence completes as soon as it is typed, even when it is also
+// the start of a longer one. Digits start a count unless a binding starts
+// with that digit; once a count has started every digit extends it. Escape
+// cancels a pending sequence or count.
+func (m *Matcher) Feed(name string, bindings []string) Result {
+	if m.Expired() {
+		m.Reset()
+	}
+	m.last = m.now()
+
+	if name == "Escape" && m.active() {
+		m.Reset()
+		return Result{Pending: true}
+	}
+
+	if d, ok := digit(name); ok && len(m.keys) == 0 {
+		if m.count > 0 || (d != 0 && !startsAny(bindings, name)) {
+			m.count = min(m.count*10+d, maxCount)
+			return Result{Pending: true}
+		}
+	}
+
+	seq := strings.Join(append(m.keys[:len(m.keys):len(m.keys)], name), " ")
+	exact, prefix := false, false
+	for _, b := range bindings {
+		if b == seq {
+			exact = true
+		} else if strings.HasPrefix(b, seq+" ") {
+			prefix = true
+		}
+	}
+
+	switch {
+	case exact:
+		count := max(m.count, 1)
+		m.Reset()
+		return Result{Seq: seq, Count: count}
+	case prefix:
+		m.keys = append(m.keys, name)
+		return Result{Pending: true}
+	case len(m.keys) > 0:
+		// The partial sequence went nowhere: drop it and its count, and
+		// treat this key as the start of a new one.
+		m.Reset()
+		return m.Feed(name, bindings)
+	default:
+		count := max(m.count, 1)
+		m.Reset()
+		return Result{Seq: name, Count: count}
+	}
+}
+
+// Pending returns the count and keys typed so far in display form
+// (e.g. "5", "g", "2 Ctrl+X"), or "" when nothing is pending.
+func (m *Matcher) Pending() string {
+	var parts []string
+	if m.count > 0 {
+		parts = append(parts, strconv.Itoa(m.count))
+	}
+	for _, k := range m.keys {
+		parts = append(parts, Display(k))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Expired reports whether a pending sequence or count has waited longer
+// than the timeout.
+func (m *Matcher) Expired() bool {
+	return m.active() && m.now().Sub(m.last) >= m.timeout
+}
+
+// Reset discards any pending sequence and count.
+func (m *Matcher) Reset() {
+	m.keys = nil
+	m.count = 0
+}
+
+func (m *Matcher) active() bool {
+	return len(m.keys) > 0 || m.count > 0
+}
+
+// Display returns a short human-readable form of a key name: "Rune[g]"
+// becomes "g", "Rune[ ]" becomes "Space"; other names are unchanged.
+func Display(name string) string {
+	mods := ""
+	for {
+		trimmed := name
+		for _, m := range modifiers {
+			if strings.HasPrefix(trimmed, m) {
+				mods += m
+				trimmed = trimmed[len(m):]
+			}
+		}
+		if trimmed == name {
+			break
+		}
+		name = trimmed
+	}
+	if inner, ok := strings.CutPrefix(name, "Rune["); ok {
+		if r, ok := strings.CutSuffix(inner, "]"); ok && r != "" {
+			name = r
+			if r == " " {
+				name = "Space"
+			}
+		}
+	}
+	return mods + name
+}
+
+// digit returns the value of a digit key.
+func digit(name string) (int, bool) {
+	if len(name) == len("Rune[0]") && strings.HasPrefix(name, "Rune[") && name[5] >= '0' && name[5] <= '9' && name[6] == ']' {
+		return int(name[5] - '0'), true
+	}
+	return 0, false
+}
+
+// startsAny reports whether any binding begins with the given key.
+func startsAny(bindings []string, name string) bool {
+	for _, b := range bindings {
+		if b == name || strings.HasPrefix(b, name+" ") {
+			return true
+		}
+	}
+	return false
+}
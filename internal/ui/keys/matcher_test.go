@@ -0,0 +1,105 @@
+package keys
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMatcherSequences(t *testing.T) {
+	bindings := []string{"Rune[g] Rune[g]", "Rune[G]", "Rune[d] Rune[d]", "Rune[1]", "Rune[ ] Rune[f]"}
+	m := NewMatcher(time.Second)
+
+	feed := func(names ...string) []Result {
+		var out []Result
+		for _, n := range names {
+			out = append(out, m.Feed(n, bindings))
+		}
+		return out
+	}
+
+	tests := []struct {
+		name  string
+		keys  []string
+		want  []Result
+		after string // Pending() after the keys
+	}{
+		{"single", []string{"Rune[G]"}, []Result{{Seq: "Rune[G]", Count: 1}}, ""},
+		{"sequence", []string{"Rune[g]", "Rune[g]"}, []Result{{Pending: true}, {Seq: "Rune[g] Rune[g]", Count: 1}}, ""},
+		{"unbound passes through", []string{"Rune[x]"}, []Result{{Seq: "Rune[x]", Count: 1}}, ""},
+		{"broken sequence retries last key", []string{"Rune[g]", "Rune[G]"}, []Result{{Pending: true}, {Seq: "Rune[G]", Count: 1}}, ""},
+		{"count", []string{"Rune[5]", "Rune[G]"}, []Result{{Pending: true}, {Seq: "Rune[G]", Count: 5}}, ""},
+		{"multi-digit count with bound digit", []string{"Rune[2]", "Rune[1]", "Rune[d]", "Rune[d]"}, []Result{{Pending: true}, {Pending: true}, {Pending: true}, {Seq: "Rune[d] Rune[d]", Count: 21}}, ""},
+		{"bound digit is not a count", []string{"Rune[1]"}, []Result{{Seq: "Rune[1]", Count: 1}}, ""},
+		{"zero is not a count", []string{"Rune[0]"}, []Result{{Seq: "Rune[0]", Count: 1}}, ""},
+		{"escape cancels", []string{"Rune[3]", "Rune[g]", "Escape"}, []Result{{Pending: true}, {Pending: true}, {Pending: true}}, ""},
+		{"space leader", []string{"Rune[ ]", "Rune[f]"}, []Result{{Pending: true}, {Seq: "Rune[ ] Rune[f]", Count: 1}}, ""},
+		{"pending display", []string{"Rune[4]", "Rune[g]"}, []Result{{Pending: true}, {Pending: true}}, "4 g"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.Reset()
+			got := feed(tt.keys...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results = %+v, want %+v", got, tt.want)
+			}
+			if p := m.Pending(); p != tt.after {
+				t.Errorf("Pending() = %q, want %q", p, tt.after)
+			}
+		})
+	}
+}
+
+func TestMatcherTimeout(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := NewMatcher(500 * time.Millisecond)
+	m.now = func() time.Time { return now }
+	bindings := []string{"Rune[g] Rune[g]"}
+
+	if r := m.Feed("Rune[g]", bindings); !r.Pending {
+		t.Fatalf("first g = %+v, want pending", r)
+	}
+	if m.Expired() {
+		t.Error("expired too early")
+	}
+
+	now = now.Add(time.Second)
+	if !m.Expired() {
+		t.Error("should have expired")
+	}
+	if r := m.Feed("Rune[g]", bindings); !r.Pending {
+		t.Errorf("g after timeout = %+v, want a new pending sequence", r)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := map[string][]string{
+		"Rune[g] Rune[g]":     {"Rune[g]", "Rune[g]"},
+		"  Ctrl+X   Rune[s] ": {"Ctrl+X", "Rune[s]"},
+		"Rune[ ] Rune[f]":     {"Rune[ ]", "Rune[f]"},
+		"Alt+Rune[ ]":         {"Alt+Rune[ ]"},
+		"Rune[]]":             {"Rune[]]"},
+		"":                    nil,
+	}
+	for in, want := range tests {
+		if got := Split(in); !reflect.DeepEqual(got, want) {
+			t.Errorf("Split(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDisplay(t *testing.T) {
+	tests := map[string]string{
+		"Rune[g]":     "g",
+		"Rune[ ]":     "Space",
+		"Ctrl+X":      "Ctrl+X",
+		"Alt+Rune[x]": "Alt+x",
+		"Enter":       "Enter",
+	}
+	for in, want := range tests {
+		if got := Display(in); got != want {
+			t.Errorf("Display(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package login
 import (
 	"context"
 	"log/slog"
+	"os"
+	"time"
 
 	"github.com/rivo/tview"
 
@@ -23,32 +25,85 @@ type Form struct {
 	done      DoneFn
 	userField *tview.InputField
 	appField  *tview.InputField
+
+	// Set by NewAdd: the form signs in to another workspace next to the
+	// open one, so it keeps the default tokens and shows errors in status.
+	cancel func()
+	status *tview.TextView
 }
 
 // New creates a login form. If OAuth credentials (client_id) are configured,
 // it shows a browser-based OAuth login. Otherwise it falls back to the manual
 // token input form.
 func New(app *tview.Application, cfg *config.Config, done DoneFn) *Form {
+	return newForm(app, cfg, "", done)
+}
+
+// NewReauth creates a login form that explains with notice why the user has
+// to sign in again, e.g. because a token could not be refreshed.
+func NewReauth(app *tview.Application, cfg *config.Config, notice string, done DoneFn) *Form {
+	return newForm(app, cfg, notice, done)
+}
+
+// NewAdd creates a login form for signing in to another workspace while one
+// is open, to be shown over the chat view. The new workspace is only added
+// to the registry; the default tokens stay as they are. cancel is called
+// when the user gives up.
+func NewAdd(app *tview.Application, cfg *config.Config, done DoneFn, cancel func()) *Form {
+	f := &Form{
+		Form:   tview.NewForm(),
+		app:    app,
+		cfg:    cfg,
+		done:   done,
+		cancel: cancel,
+		status: tview.NewTextView().SetSize(2, 0),
+	}
+	f.build("")
+	f.AddFormItem(f.status)
+	f.SetTitle(" slacko — add workspace ")
+	f.SetCancelFunc(cancel)
+	return f
+}
+
+func newForm(app *tview.Application, cfg *config.Config, notice string, done DoneFn) *Form {
 	f := &Form{
 		Form: tview.NewForm(),
 		app:  app,
 		cfg:  cfg,
 		done: done,
 	}
+	f.build(notice)
+	return f
+}
 
-	if cfg.OAuth.ClientID != "" {
+// build adds the fields and buttons for the configured login method.
+func (f *Form) build(notice string) {
+	if notice != "" {
+		f.AddTextView("", notice, 0, 3, false, false)
+	}
+	if f.cfg.OAuth.ClientID != "" {
 		f.buildOAuthForm()
 	} else {
 		f.buildManualForm()
 	}
+}
 
+// addQuitButton adds the button that leaves the form: Quit stops Slacko,
+// Cancel closes a form created with NewAdd.
+func (f *Form) addQuitButton() *Form {
+	if f.cancel != nil {
+		f.AddButton("Cancel", f.cancel)
+	} else {
+		f.AddButton("Quit", func() { f.app.Stop() })
+	}
 	return f
 }
 
 // buildOAuthForm sets up a single-button form that triggers the browser OAuth flow.
 func (f *Form) buildOAuthForm() {
 	f.AddButton("Authorize with Slack (opens browser)", f.submitOAuth).
-		AddButton("Quit", func() { f.app.Stop() }).
+		AddButton("Use another device", f.submitManualOAuth)
+	f.addQuitButton().
 		SetBorder(true).
 		SetTitle(" slacko login — OAuth ").
 		SetTitleAlign(tview.AlignCenter)
@@ -65,8 +120,8 @@ func (f *Form) buildManualForm() {
 
 	f.AddFormItem(f.userField).
 		AddFormItem(f.appField).
-		AddButton("Login", f.submitManual).
-		AddButton("Quit", func() { f.app.Stop() }).
+		AddButton("Login", f.submitManual)
+	f.addQuitButton().
 		SetBorder(true).
 		SetTitle(" slacko login ").
 		SetTitleAlign(tview.AlignCenter)
@@ -74,27 +129,46 @@ func (f *Form) buildManualForm() {
 
 // submitOAuth suspends the TUI, runs the OAuth flow, and resumes.
 func (f *Form) submitOAuth() {
-	cfg := f.cfg.OAuth
-
 	var result *oauth.Result
 	var oauthErr error
+	f.app.Suspend(func() {
+		result, oauthErr = oauth.Run(context.Background(), f.oauthParams())
+	})
+	f.finishOAuth(result, oauthErr)
+}
 
+// submitManualOAuth suspends the TUI and runs the OAuth flow for a browser
+// on another machine: the authorize URL is printed to the terminal and the
+// answer is pasted back there.
+func (f *Form) submitManualOAuth() {
+	var result *oauth.Result
+	var oauthErr error
 	f.app.Suspend(func() {
-		result, oauthErr = oauth.Run(
-			context.Background(),
-			oauth.Params{
-				ClientID:     cfg.ClientID,
-				ClientSecret: cfg.ClientSecret,
-				ProxyURL:     cfg.ProxyURL,
-			},
-		)
+		result, oauthErr = oauth.RunManual(context.Background(), f.oauthParams(), os.Stdin, os.Stdout)
 	})
+	f.finishOAuth(result, oauthErr)
+}
 
+// oauthParams returns the OAuth settings from the config.
+func (f *Form) oauthParams() oauth.Params {
+	cfg := f.cfg.OAuth
+	return oauth.Params{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		ProxyURL:     cfg.ProxyURL,
+		Port:         cfg.Port,
+	}
+}
+
+// finishOAuth validates the tokens of a finished OAuth flow, stores them and
+// calls the done callback.
+func (f *Form) finishOAuth(result *oauth.Result, oauthErr error) {
 	if oauthErr != nil {
 		f.showError("OAuth failed: " + oauthErr.Error())
 		return
 	}
 
+	cfg := f.cfg.OAuth
 	appToken := cfg.AppToken
 	if result.AppToken != "" {
 		appToken = result.AppToken
@@ -105,15 +179,11 @@ func (f *Form) submitOAuth() {
 		return
 	}
 
-	if err := keyring.SetUserToken(result.UserToken); err != nil {
-		slog.Warn("failed to store user token in keyring", "error", err)
-	}
-	if err := keyring.SetAppToken(appToken); err != nil {
-		slog.Warn("failed to store app token in keyring", "error", err)
-	}
-
+	f.storeDefaultTokens(result.UserToken, appToken)
 	if err := keyring.AddWorkspace(client.TeamID, client.TeamName, result.UserToken, appToken); err != nil {
 		slog.Warn("failed to register workspace", "error", err)
+	} else if err := keyring.SetWorkspaceRefresh(client.TeamID, result.RefreshToken, result.ExpiresAt); err != nil {
+		slog.Warn("failed to store refresh token", "error", err)
 	}
 
 	f.done(client)
@@ -136,23 +206,40 @@ func (f *Form) submitManual() {
 		return
 	}
 
-	if err := keyring.SetUserToken(user); err != nil {
-		slog.Warn("failed to store user token in keyring", "error", err)
-	}
-	if err := keyring.SetAppToken(app); err != nil {
-		slog.Warn("failed to store app token in keyring", "error", err)
-	}
+	f.storeDefaultTokens(user, app)
 
-	// Register in multi-workspace registry.
+	// Register in multi-workspace registry. Pasted tokens don't rotate.
 	if err := keyring.AddWorkspace(client.TeamID, client.TeamName, user, app); err != nil {
 		slog.Warn("failed to register workspace", "error", err)
+	} else if err := keyring.SetWorkspaceRefresh(client.TeamID, "", time.Time{}); err != nil {
+		slog.Warn("failed to clear refresh token", "error", err)
 	}
 
 	f.done(client)
 }
 
-// showError displays a modal error message and returns to the form on dismiss.
+// storeDefaultTokens makes the tokens the ones Slacko starts with, unless
+// the form adds a workspace next to the open one.
+func (f *Form) storeDefaultTokens(user, app string) {
+	if f.cancel != nil {
+		return
+	}
+	if err := keyring.SetUserToken(user); err != nil {
+		slog.Warn("failed to store user token in keyring", "error", err)
+	}
+	if err := keyring.SetAppToken(app); err != nil {
+		slog.Warn("failed to store app token in keyring", "error", err)
+	}
+}
+
+// showError displays a modal error message and returns to the form on
+// dismiss. A form created with NewAdd shows it in the form instead, since
+// it isn't the root.
 func (f *Form) showError(msg string) {
+	if f.status != nil {
+		f.status.SetText(msg)
+		return
+	}
 	modal := tview.NewModal().
 		SetText(msg).
 		AddButtons([]string{"OK"}).
@@ -4,6 +4,7 @@
  * Handles the full OAuth callback flow server-side:
  *   GET /authorize  — redirects to Slack with worker as callback
  *   GET /callback   — receives code from Slack, exchanges token, POSTs result to localhost
+ *   POST /refresh   — renews a rotating user token with its refresh token
  *
  * Secrets (set via `wrangler secret put`):
  *   SLACK_CLIENT_ID
@@ -26,7 +27,7 @@ const USER_SCOPES = [
 	"pins:read", "pins:write", "reactions:read", "reactions:write",
 	"search:read", "stars:read", "stars:write",
 	"team:read", "users:read", "users:read.email", "users.profile:read", "users.profile:write",
-	"reminders:read", "reminders:write",
+	"reminders:read", "reminders:write", "usergroups:read",
 ].join(",");
 
 export default {
@@ -38,6 +39,8 @@ export default {
 				return handleAuthorize(url, env);
 			case "/callback":
 				return handleCallback(url, env);
+			case "/refresh":
+				return handleRefresh(request, env);
 			default:
 				return new Response("Not Found", { status: 404 });
 		}
@@ -48,6 +51,8 @@ export default {
  * GET /authorize?port=PORT&state=CSRF_STATE
  *
  * Encodes port + CSRF state into Slack's state param, then redirects to Slack.
+ * Port 0 selects manual mode: the result is shown as a code to paste into
+ * Slacko instead of being posted to localhost.
  */
 function handleAuthorize(url: URL, env: Env): Response {
 	const port = url.searchParams.get("port");
@@ -131,10 +136,13 @@ async function handleCallback(url: URL, env: Env): Promise<Response> {
 		return errorPage(`Token exchange failed: ${errMsg}`);
 	}
 
-	const authedUser = data.authed_user as Record<string, string> | undefined;
+	const authedUser = data.authed_user as Record<string, string | number> | undefined;
 	const team = data.team as Record<string, string> | undefined;
-	const userToken = authedUser?.access_token || "";
-	const userId = authedUser?.id || "";
+	const userToken = String(authedUser?.access_token || "");
+	const userId = String(authedUser?.id || "");
+	// Only set when the app has token rotation enabled.
+	const refreshToken = String(authedUser?.refresh_token || "");
+	const expiresIn = String(authedUser?.expires_in || "");
 	const teamId = team?.id || "";
 	const teamName = team?.name || "";
 
@@ -142,19 +150,31 @@ async function handleCallback(url: URL, env: Env): Promise<Response> {
 		return errorPage("No user token in Slack response");
 	}
 
+	const fields = {
+		token: userToken,
+		user_id: userId,
+		team_id: teamId,
+		team_name: teamName,
+		app_token: env.SLACK_APP_TOKEN,
+		refresh_token: refreshToken,
+		expires_in: expiresIn,
+		state: csrfState,
+	};
+	if (port === "0") {
+		return manualPage(fields);
+	}
+
 	// Return an HTML page that auto-submits a form POST to the CLI's local server.
 	const localhostURL = `http://localhost:${port}/done`;
+	const inputs = Object.entries(fields)
+		.map(([name, value]) => `<input type="hidden" name="${name}" value="${escapeHtml(value)}">`)
+		.join("\n");
 	const html = `<!DOCTYPE html>
 <html><head><meta charset="utf-8"><title>Slacko — Authorizing...</title></head>
 <body>
 <p>Completing authorization...</p>
 <form id="f" method="POST" action="${escapeHtml(localhostURL)}">
-<input type="hidden" name="token" value="${escapeHtml(userToken)}">
-<input type="hidden" name="user_id" value="${escapeHtml(userId)}">
-<input type="hidden" name="team_id" value="${escapeHtml(teamId)}">
-<input type="hidden" name="team_name" value="${escapeHtml(teamName)}">
-<input type="hidden" name="app_token" value="${escapeHtml(env.SLACK_APP_TOKEN)}">
-<input type="hidden" name="state" value="${escapeHtml(csrfState)}">
+${inputs}
 <noscript><button type="submit">Click to complete authorization</button></noscript>
 </form>
 <script>document.getElementById('f').submit();</script>
@@ -165,6 +185,68 @@ async function handleCallback(url: URL, env: Env): Promise<Response> {
 	});
 }
 
+/**
+ * POST /refresh (form: refresh_token)
+ *
+ * Exchanges a refresh token for a new user token on behalf of the CLI, which
+ * doesn't have the client secret. Slack's JSON response is returned as is.
+ */
+async function handleRefresh(request: Request, env: Env): Promise<Response> {
+	if (request.method !== "POST") {
+		return new Response("Method Not Allowed", { status: 405 });
+	}
+
+	const form = await request.formData();
+	const refreshToken = form.get("refresh_token");
+	if (typeof refreshToken !== "string" || !refreshToken) {
+		return Response.json({ ok: false, error: "missing_refresh_token" }, { status: 400 });
+	}
+
+	const params = new URLSearchParams({
+		client_id: env.SLACK_CLIENT_ID,
+		client_secret: env.SLACK_CLIENT_SECRET,
+		grant_type: "refresh_token",
+		refresh_token: refreshToken,
+	});
+	const slackResp = await fetch("https://slack.com/api/oauth.v2.access", {
+		method: "POST",
+		headers: { "Content-Type": "application/x-www-form-urlencoded" },
+		body: params.toString(),
+	});
+
+	return new Response(await slackResp.text(), {
+		status: slackResp.status,
+		headers: { "Content-Type": "application/json" },
+	});
+}
+
+/**
+ * Shows the callback fields as a "slacko:" completion code: the
+ * base64url-encoded form that would otherwise be posted to localhost.
+ */
+function manualPage(fields: Record<string, string>): Response {
+	const query = new URLSearchParams(fields).toString();
+	const bytes = new TextEncoder().encode(query);
+	let binary = "";
+	for (const b of bytes) {
+		binary += String.fromCharCode(b);
+	}
+	const code = "slacko:" + btoa(binary).replace(/\+/g, "-").replace(/\//g, "_").replace(/=+$/, "");
+
+	const html = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Slacko — Authorized</title></head>
+<body>
+<h2>Authorization successful!</h2>
+<p>Paste this code into Slacko to finish signing in:</p>
+<textarea readonly rows="6" cols="80" onclick="this.select()">${escapeHtml(code)}</textarea>
+<p>The code contains your token; don't share it.</p>
+</body></html>`;
+
+	return new Response(html, {
+		headers: { "Content-Type": "text/html; charset=utf-8", "Cache-Control": "no-store" },
+	});
+}
+
 function errorPage(message: string): Response {
 	const html = `<!DOCTYPE html>
 <html><head><meta charset="utf-8"><title>Slacko — Error</title></head>